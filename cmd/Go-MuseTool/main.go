@@ -3,8 +3,15 @@ package main
 import (
 	_ "embed"
 	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"go-musetool/internal/assets"
+	"go-musetool/internal/cli"
+	"go-musetool/internal/deeplink"
+	"go-musetool/internal/ipc"
 	"go-musetool/internal/logger"
 	"go-musetool/internal/storage"
 	"go-musetool/internal/ui"
@@ -15,21 +22,82 @@ import (
 // IconData is now imported from assets package
 var iconData = assets.IconData
 
+// configFileName is stored next to the executable (see configPath)
+const configFileName = "config.json"
+
+// configPath returns the config file next to the executable, so the window,
+// the headless commands and the autostart entry use the same config (and the
+// history, token and icons beside it) whatever the working directory is
+func configPath() string {
+	exe, err := os.Executable()
+	if err != nil {
+		log.Printf("Cannot locate the executable, using %s in the working directory: %v", configFileName, err)
+		return configFileName
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return filepath.Join(filepath.Dir(exe), configFileName)
+}
+
 func main() {
 	// Set standard log format to match logger package
 	log.SetFlags(log.Ldate | log.Ltime)
 	log.SetPrefix("【SYSTEM】")
 
+	configFile := configPath()
+
+	// Headless subcommands (list, add, launch, ...) never open a window
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		cli.AttachConsole()
+		os.Exit(cli.Run(configFile, os.Args[1:], os.Stdout, os.Stderr))
+	}
+
+	// Translate the command line into a request (show, add files, launch, switch group).
+	// A musetool:// deep link opened from a browser or chat arrives as the only argument.
+	var request ipc.Request
+	var err error
+	if len(os.Args) > 1 && deeplink.IsLink(os.Args[1]) {
+		var link deeplink.Link
+		if link, err = deeplink.Parse(os.Args[1]); err == nil {
+			request = link.Request()
+		}
+	} else {
+		request, err = ipc.ParseArgs(os.Args[1:])
+	}
+	if err != nil {
+		log.Printf("Invalid arguments: %v", err)
+		request = ipc.NewRequest(ipc.ActionShow)
+	}
+
+	// The autostart entry passes --tray and --delay N (see Settings)
+	startHidden, delay := startupFlags(os.Args[1:])
+	if delay > 0 {
+		log.Printf("Delaying startup by %v", delay)
+		time.Sleep(delay)
+	}
+
 	// Check if another instance is already running
 	if !ui.CheckSingleInstance() {
-		// Another instance is running, show dialog and exit
-		ui.ShowAlreadyRunningDialog()
+		// Started at login but the user opened MuseTool in the meantime: nothing to do
+		if startHidden {
+			return
+		}
+		// Another instance is running: forward our request to it and exit
+		if err := ipc.Send(request); err != nil {
+			log.Printf("Failed to forward request to running instance: %v", err)
+			lang := ""
+			if config, err := storage.LoadConfig(configFile); err == nil {
+				lang = config.Language
+			}
+			ui.ShowAlreadyRunningDialog(lang)
+		}
 		return
 	}
 	defer ui.ReleaseSingleInstance()
 
 	// Load configuration first to get debug mode setting
-	config, err := storage.LoadConfig("config.json")
+	config, err := storage.LoadConfig(configFile)
 	if err != nil {
 		log.Printf("Warning: Could not load config: %v. Starting with default config.", err)
 		// Proceed with empty config if load fails
@@ -48,7 +116,9 @@ func main() {
 	logger.Info("Initializing UI...")
 
 	// Pass icon data to NewLauncherApp so it's available when tray initializes
-	app := ui.NewLauncherApp(config, "config.json", iconData)
+	app := ui.NewLauncherApp(config, configFile, iconData)
+	app.StartHidden = startHidden
+	app.ListenForInstances(request)
 
 	// Load and set application icon from embedded resource
 	iconResource := fyne.NewStaticResource("icon.ico", iconData)
@@ -59,3 +129,23 @@ func main() {
 	logger.Info("UI Initialized. Running app...")
 	app.Run()
 }
+
+// startupFlags reads the flags that only matter when this process becomes the
+// running instance: --tray (or --minimized) starts with only the tray icon,
+// --delay N waits N seconds first so login is not slowed down
+func startupFlags(args []string) (hidden bool, delay time.Duration) {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--tray", "--minimized":
+			hidden = true
+		case "--delay":
+			if i+1 < len(args) {
+				i++
+				if n, err := strconv.Atoi(args[i]); err == nil && n > 0 {
+					delay = time.Duration(n) * time.Second
+				}
+			}
+		}
+	}
+	return hidden, delay
+}
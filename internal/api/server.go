@@ -0,0 +1,368 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-musetool/internal/launcher"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+)
+
+// DefaultPort is used when the configured port is 0
+const DefaultPort = 51745
+
+// maxBodySize bounds request bodies; shortcut payloads are tiny
+const maxBodySize = 1 << 20
+
+// Backend performs the actual work. The UI implements it so that every API
+// call goes through the same storage and launch code paths as a click.
+type Backend interface {
+	// Groups returns a snapshot of all groups and their shortcuts
+	Groups() []model.Group
+
+	LaunchShortcut(group, name string) error
+
+	AddGroup(name string) error
+	RenameGroup(oldName, newName string) error
+	DeleteGroup(name string) error
+
+	AddShortcut(group string, shortcut model.Shortcut) error
+	UpdateShortcut(group, name string, shortcut model.Shortcut) error
+	DeleteShortcut(group, name string) error
+
+	// SwitchGroup changes the group visible in the main window
+	SwitchGroup(name string) error
+	ShowWindow()
+	HideWindow()
+}
+
+// Server exposes a Backend over HTTP/JSON. It implements http.Handler, so it
+// can be exercised with httptest without opening a socket.
+type Server struct {
+	backend Backend
+	token   string
+	mux     *http.ServeMux
+	http    *http.Server
+}
+
+// NewServer creates a server that accepts requests carrying token
+func NewServer(backend Backend, token string) *Server {
+	s := &Server{backend: backend, token: token, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /api/v1/shortcuts", s.handleListShortcuts)
+	s.mux.HandleFunc("GET /api/v1/search", s.handleSearch)
+	s.mux.HandleFunc("POST /api/v1/launch", s.handleLaunch)
+
+	s.mux.HandleFunc("GET /api/v1/groups", s.handleListGroups)
+	s.mux.HandleFunc("POST /api/v1/groups", s.handleAddGroup)
+	s.mux.HandleFunc("PUT /api/v1/groups/{group}", s.handleRenameGroup)
+	s.mux.HandleFunc("DELETE /api/v1/groups/{group}", s.handleDeleteGroup)
+
+	s.mux.HandleFunc("POST /api/v1/groups/{group}/shortcuts", s.handleAddShortcut)
+	s.mux.HandleFunc("PUT /api/v1/groups/{group}/shortcuts/{name}", s.handleUpdateShortcut)
+	s.mux.HandleFunc("DELETE /api/v1/groups/{group}/shortcuts/{name}", s.handleDeleteShortcut)
+
+	s.mux.HandleFunc("POST /api/v1/window/show", s.handleShowWindow)
+	s.mux.HandleFunc("POST /api/v1/window/hide", s.handleHideWindow)
+	return s
+}
+
+// ServeHTTP checks the host and token before dispatching to the routes
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// 拒绝非回环 Host，防止 DNS 重绑定攻击让网页访问本地 API
+	if !isLoopbackHost(r.Host) {
+		writeError(w, http.StatusForbidden, errors.New("forbidden host"))
+		return
+	}
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// Start listens on 127.0.0.1:port and serves in the background
+func (s *Server) Start(port int) error {
+	if port == 0 {
+		port = DefaultPort
+	}
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s.http = &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := s.http.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Printf("api: server stopped: %v", err)
+		}
+	}()
+	log.Printf("api: listening on http://%s", addr)
+	return nil
+}
+
+// Close stops the server started by Start
+func (s *Server) Close() error {
+	if s.http == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return s.http.Shutdown(ctx)
+}
+
+// authorized accepts "Authorization: Bearer <token>" or "X-MuseTool-Token"
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return false
+	}
+	got := r.Header.Get("X-MuseTool-Token")
+	if auth := r.Header.Get("Authorization"); got == "" && strings.HasPrefix(auth, "Bearer ") {
+		got = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+func isLoopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// --- handlers ---
+
+func (s *Server) handleListShortcuts(w http.ResponseWriter, r *http.Request) {
+	groups := s.backend.Groups()
+	if name := r.URL.Query().Get("group"); name != "" {
+		for _, g := range groups {
+			if g.Name == name {
+				writeJSON(w, http.StatusOK, g.Shortcuts)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: %s", storage.ErrGroupNotFound, name))
+		return
+	}
+	writeJSON(w, http.StatusOK, storage.SearchShortcuts(&model.Config{Groups: groups}, ""))
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	config := &model.Config{Groups: s.backend.Groups()}
+	writeJSON(w, http.StatusOK, storage.SearchShortcuts(config, r.URL.Query().Get("q")))
+}
+
+// launchRequest names a shortcut either by "Group/Name" or by its parts
+type launchRequest struct {
+	Ref   string `json:"ref"`
+	Group string `json:"group"`
+	Name  string `json:"name"`
+}
+
+func (s *Server) handleLaunch(w http.ResponseWriter, r *http.Request) {
+	var req launchRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	group, name := req.Group, req.Name
+	if req.Ref != "" {
+		g, shortcut, err := storage.LookupShortcut(&model.Config{Groups: s.backend.Groups()}, req.Ref)
+		if err != nil {
+			writeStorageError(w, fmt.Errorf("%w: %s", err, req.Ref))
+			return
+		}
+		group, name = g, shortcut.Name
+	}
+	if group == "" || name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("ref or group and name are required"))
+		return
+	}
+
+	if err := s.backend.LaunchShortcut(group, name); err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"group": group, "name": name})
+}
+
+func (s *Server) handleListGroups(w http.ResponseWriter, r *http.Request) {
+	groups := s.backend.Groups()
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	writeJSON(w, http.StatusOK, names)
+}
+
+// groupRequest is the body for creating or renaming a group
+type groupRequest struct {
+	Name string `json:"name"`
+}
+
+func (s *Server) handleAddGroup(w http.ResponseWriter, r *http.Request) {
+	var req groupRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if err := s.backend.AddGroup(req.Name); err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, req)
+}
+
+func (s *Server) handleRenameGroup(w http.ResponseWriter, r *http.Request) {
+	var req groupRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if err := s.backend.RenameGroup(r.PathValue("group"), req.Name); err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, req)
+}
+
+func (s *Server) handleDeleteGroup(w http.ResponseWriter, r *http.Request) {
+	if err := s.backend.DeleteGroup(r.PathValue("group")); err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleAddShortcut(w http.ResponseWriter, r *http.Request) {
+	var shortcut model.Shortcut
+	if !readJSON(w, r, &shortcut) {
+		return
+	}
+	if !validShortcut(w, &shortcut) {
+		return
+	}
+	if err := s.backend.AddShortcut(r.PathValue("group"), shortcut); err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, shortcut)
+}
+
+func (s *Server) handleUpdateShortcut(w http.ResponseWriter, r *http.Request) {
+	var shortcut model.Shortcut
+	if !readJSON(w, r, &shortcut) {
+		return
+	}
+	if !validShortcut(w, &shortcut) {
+		return
+	}
+	if err := s.backend.UpdateShortcut(r.PathValue("group"), r.PathValue("name"), shortcut); err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, shortcut)
+}
+
+// validShortcut fills in a missing kind and rejects shortcuts without the
+// payload their kind needs
+func validShortcut(w http.ResponseWriter, shortcut *model.Shortcut) bool {
+	if shortcut.Name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("name is required"))
+		return false
+	}
+	shortcut.Kind = launcher.KindOf(*shortcut)
+	if err := launcher.Validate(*shortcut); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w (kind %s)", err, shortcut.Kind))
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleDeleteShortcut(w http.ResponseWriter, r *http.Request) {
+	if err := s.backend.DeleteShortcut(r.PathValue("group"), r.PathValue("name")); err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// windowRequest optionally selects the group to show
+type windowRequest struct {
+	Group string `json:"group"`
+}
+
+func (s *Server) handleShowWindow(w http.ResponseWriter, r *http.Request) {
+	var req windowRequest
+	if r.ContentLength != 0 && !readJSON(w, r, &req) {
+		return
+	}
+	if req.Group != "" {
+		if err := s.backend.SwitchGroup(req.Group); err != nil {
+			writeStorageError(w, err)
+			return
+		}
+	}
+	s.backend.ShowWindow()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleHideWindow(w http.ResponseWriter, r *http.Request) {
+	s.backend.HideWindow()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// --- helpers ---
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false) // 保留中文字符，不转义为Unicode
+	encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeStorageError maps storage errors to HTTP status codes
+func writeStorageError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, storage.ErrGroupNotFound), errors.Is(err, storage.ErrShortcutNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, storage.ErrGroupExists), errors.Is(err, storage.ErrShortcutExists), errors.Is(err, storage.ErrLastGroup):
+		status = http.StatusConflict
+	case errors.Is(err, storage.ErrGroupNameEmpty):
+		status = http.StatusBadRequest
+	}
+	writeError(w, status, err)
+}
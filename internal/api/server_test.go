@@ -0,0 +1,267 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+)
+
+const testToken = "secret"
+
+// fakeBackend applies the calls to an in-memory config and records launches
+// and window changes
+type fakeBackend struct {
+	config   model.Config
+	launched []string
+	visible  bool
+	current  string
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{config: model.Config{Groups: []model.Group{
+		{Name: "Dev", Shortcuts: []model.Shortcut{
+			{Name: "Docs", Kind: model.KindURL, Path: "https://example.com/docs"},
+			{Name: "Build", Kind: model.KindCommand, Command: "make"},
+		}},
+		{Name: "工具", Shortcuts: []model.Shortcut{
+			{Name: "记事本", Kind: model.KindApplication, Path: "notepad.exe"},
+		}},
+	}}}
+}
+
+func (b *fakeBackend) Groups() []model.Group { return b.config.Groups }
+
+func (b *fakeBackend) LaunchShortcut(group, name string) error {
+	if _, err := storage.FindShortcut(&b.config, group, name); err != nil {
+		return err
+	}
+	b.launched = append(b.launched, group+"/"+name)
+	return nil
+}
+
+func (b *fakeBackend) AddGroup(name string) error { return storage.AddGroup(&b.config, name) }
+func (b *fakeBackend) RenameGroup(oldName, newName string) error {
+	return storage.RenameGroup(&b.config, oldName, newName)
+}
+func (b *fakeBackend) DeleteGroup(name string) error { return storage.DeleteGroup(&b.config, name) }
+
+// AddShortcut rejects duplicate names like the UI backend does
+func (b *fakeBackend) AddShortcut(group string, shortcut model.Shortcut) error {
+	if _, err := storage.FindShortcut(&b.config, group, shortcut.Name); err == nil {
+		return storage.ErrShortcutExists
+	}
+	return storage.AddShortcut(&b.config, group, shortcut)
+}
+func (b *fakeBackend) UpdateShortcut(group, name string, shortcut model.Shortcut) error {
+	return storage.UpdateShortcut(&b.config, group, name, shortcut)
+}
+func (b *fakeBackend) DeleteShortcut(group, name string) error {
+	return storage.RemoveShortcut(&b.config, group, name)
+}
+
+func (b *fakeBackend) SwitchGroup(name string) error {
+	if storage.FindGroup(&b.config, name) == nil {
+		return storage.ErrGroupNotFound
+	}
+	b.current = name
+	return nil
+}
+func (b *fakeBackend) ShowWindow() { b.visible = true }
+func (b *fakeBackend) HideWindow() { b.visible = false }
+
+// do sends an authorized request to a server backed by b
+func do(b *fakeBackend, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "http://127.0.0.1:51745"+path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	NewServer(b, testToken).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		token  string // 服务器的令牌
+		want   int
+	}{
+		{"bearer", "Authorization", "Bearer " + testToken, testToken, http.StatusOK},
+		{"token header", "X-MuseTool-Token", testToken, testToken, http.StatusOK},
+		{"no token", "", "", testToken, http.StatusUnauthorized},
+		{"wrong bearer", "Authorization", "Bearer nope", testToken, http.StatusUnauthorized},
+		{"wrong token header", "X-MuseTool-Token", "nope", testToken, http.StatusUnauthorized},
+		{"basic auth", "Authorization", "Basic " + testToken, testToken, http.StatusUnauthorized},
+		{"server without token", "X-MuseTool-Token", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://127.0.0.1/api/v1/groups", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			NewServer(newFakeBackend(), tt.token).ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRejectsNonLoopbackHosts(t *testing.T) {
+	tests := []struct {
+		host string
+		want int
+	}{
+		{"127.0.0.1:51745", http.StatusOK},
+		{"localhost:51745", http.StatusOK},
+		{"localhost", http.StatusOK},
+		{"[::1]:51745", http.StatusOK},
+		{"127.0.0.2", http.StatusOK},
+		{"example.com", http.StatusForbidden},
+		{"evil.example:51745", http.StatusForbidden},
+		{"192.168.1.10:51745", http.StatusForbidden},
+		{"localhost.evil.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/v1/groups", nil)
+		req.Host = tt.host
+		req.Header.Set("X-MuseTool-Token", testToken)
+		rec := httptest.NewRecorder()
+		NewServer(newFakeBackend(), testToken).ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Host %q: status = %d, want %d", tt.host, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"list shortcuts", "GET", "/api/v1/shortcuts", "", http.StatusOK},
+		{"list group shortcuts", "GET", "/api/v1/shortcuts?group=Dev", "", http.StatusOK},
+		{"list missing group", "GET", "/api/v1/shortcuts?group=Nope", "", http.StatusNotFound},
+		{"search", "GET", "/api/v1/search?q=docs", "", http.StatusOK},
+
+		{"launch by ref", "POST", "/api/v1/launch", `{"ref":"Dev/Docs"}`, http.StatusOK},
+		{"launch by parts", "POST", "/api/v1/launch", `{"group":"工具","name":"记事本"}`, http.StatusOK},
+		{"launch missing shortcut", "POST", "/api/v1/launch", `{"ref":"Dev/Nope"}`, http.StatusNotFound},
+		{"launch missing group", "POST", "/api/v1/launch", `{"ref":"Nope/Docs"}`, http.StatusNotFound},
+		{"launch without target", "POST", "/api/v1/launch", `{}`, http.StatusBadRequest},
+		{"launch unknown field", "POST", "/api/v1/launch", `{"shortcut":"Docs"}`, http.StatusBadRequest},
+		{"launch invalid json", "POST", "/api/v1/launch", `{`, http.StatusBadRequest},
+
+		{"list groups", "GET", "/api/v1/groups", "", http.StatusOK},
+		{"add group", "POST", "/api/v1/groups", `{"name":"Work"}`, http.StatusCreated},
+		{"add existing group", "POST", "/api/v1/groups", `{"name":"Dev"}`, http.StatusConflict},
+		{"add empty group", "POST", "/api/v1/groups", `{"name":""}`, http.StatusBadRequest},
+		{"rename group", "PUT", "/api/v1/groups/Dev", `{"name":"Code"}`, http.StatusOK},
+		{"rename onto existing", "PUT", "/api/v1/groups/Dev", `{"name":"工具"}`, http.StatusConflict},
+		{"rename missing group", "PUT", "/api/v1/groups/Nope", `{"name":"X"}`, http.StatusNotFound},
+		{"delete group", "DELETE", "/api/v1/groups/Dev", "", http.StatusNoContent},
+		{"delete missing group", "DELETE", "/api/v1/groups/Nope", "", http.StatusNotFound},
+
+		{"add shortcut", "POST", "/api/v1/groups/Dev/shortcuts", `{"name":"Site","path":"https://example.org"}`, http.StatusCreated},
+		{"add duplicate shortcut", "POST", "/api/v1/groups/Dev/shortcuts", `{"name":"Docs","path":"https://example.org"}`, http.StatusConflict},
+		{"add shortcut without name", "POST", "/api/v1/groups/Dev/shortcuts", `{"path":"https://example.org"}`, http.StatusBadRequest},
+		{"add shortcut without target", "POST", "/api/v1/groups/Dev/shortcuts", `{"name":"Empty","kind":"command"}`, http.StatusBadRequest},
+		{"add shortcut to missing group", "POST", "/api/v1/groups/Nope/shortcuts", `{"name":"Site","path":"https://example.org"}`, http.StatusNotFound},
+		{"update shortcut", "PUT", "/api/v1/groups/Dev/shortcuts/Build", `{"name":"Build","kind":"command","command":"make all"}`, http.StatusOK},
+		{"update missing shortcut", "PUT", "/api/v1/groups/Dev/shortcuts/Nope", `{"name":"Nope","path":"https://example.org"}`, http.StatusNotFound},
+		{"delete shortcut", "DELETE", "/api/v1/groups/Dev/shortcuts/Docs", "", http.StatusNoContent},
+		{"delete missing shortcut", "DELETE", "/api/v1/groups/Dev/shortcuts/Nope", "", http.StatusNotFound},
+
+		{"show window", "POST", "/api/v1/window/show", "", http.StatusNoContent},
+		{"show window with group", "POST", "/api/v1/window/show", `{"group":"Dev"}`, http.StatusNoContent},
+		{"show missing group", "POST", "/api/v1/window/show", `{"group":"Nope"}`, http.StatusNotFound},
+		{"hide window", "POST", "/api/v1/window/hide", "", http.StatusNoContent},
+
+		{"unknown route", "GET", "/api/v1/nothing", "", http.StatusNotFound},
+		{"wrong method", "DELETE", "/api/v1/launch", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(newFakeBackend(), tt.method, tt.path, tt.body)
+			if rec.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d: %s", tt.method, tt.path, rec.Code, tt.want, rec.Body)
+			}
+			if rec.Code >= 400 && rec.Code != http.StatusNotFound && rec.Code != http.StatusMethodNotAllowed {
+				var body struct{ Error string }
+				if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Error == "" {
+					t.Errorf("error response without an error message: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestLastGroupCannotBeDeleted(t *testing.T) {
+	b := newFakeBackend()
+	do(b, "DELETE", "/api/v1/groups/Dev", "")
+	if rec := do(b, "DELETE", "/api/v1/groups/工具", ""); rec.Code != http.StatusConflict {
+		t.Errorf("deleting the last group = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
+
+func TestRoutesReachBackend(t *testing.T) {
+	b := newFakeBackend()
+
+	do(b, "POST", "/api/v1/launch", `{"ref":"Dev/Docs"}`)
+	if len(b.launched) != 1 || b.launched[0] != "Dev/Docs" {
+		t.Errorf("launched = %q, want [Dev/Docs]", b.launched)
+	}
+
+	rec := do(b, "POST", "/api/v1/groups/Dev/shortcuts", `{"name":"Site","path":"https://example.org"}`)
+	var added model.Shortcut
+	json.NewDecoder(rec.Body).Decode(&added)
+	if added.Kind != model.KindURL {
+		t.Errorf("added shortcut kind = %q, want the detected %q", added.Kind, model.KindURL)
+	}
+	if _, err := storage.FindShortcut(&b.config, "Dev", "Site"); err != nil {
+		t.Errorf("added shortcut not in backend: %v", err)
+	}
+
+	do(b, "POST", "/api/v1/window/show", `{"group":"工具"}`)
+	if !b.visible || b.current != "工具" {
+		t.Errorf("after show: visible %v, group %q", b.visible, b.current)
+	}
+	do(b, "POST", "/api/v1/window/hide", "")
+	if b.visible {
+		t.Error("window still visible after hide")
+	}
+
+	rec = do(b, "GET", "/api/v1/search?q=MAKE", "")
+	var results []storage.SearchResult
+	if err := json.NewDecoder(rec.Body).Decode(&results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Shortcut.Name != "Build" {
+		t.Errorf("search = %+v, want Dev/Build", results)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestLoadOrCreateToken(t *testing.T) {
+	dir := t.TempDir()
+	token, err := LoadOrCreateToken(dir)
+	if err != nil || token == "" {
+		t.Fatalf("LoadOrCreateToken = %q, %v", token, err)
+	}
+	again, err := LoadOrCreateToken(dir)
+	if err != nil || again != token {
+		t.Errorf("second LoadOrCreateToken = %q, %v, want the stored %q", again, err, token)
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TokenFileName is the file in the data directory holding the API token
+const TokenFileName = "api_token"
+
+// TokenPath returns the location of the token file inside dataDir
+func TokenPath(dataDir string) string {
+	return filepath.Join(dataDir, TokenFileName)
+}
+
+// LoadOrCreateToken reads the API token from dataDir, generating and storing
+// a new random token on first use. The file is readable by the owner only.
+func LoadOrCreateToken(dataDir string) (string, error) {
+	path := TokenPath(dataDir)
+	if data, err := os.ReadFile(path); err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read api token: %w", err)
+	}
+	return RegenerateToken(dataDir)
+}
+
+// RegenerateToken replaces the stored token, invalidating the old one
+func RegenerateToken(dataDir string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := os.WriteFile(TokenPath(dataDir), []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write api token: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,36 @@
+// Package autostart starts MuseTool when the user logs in: a value under
+// HKCU\...\Run on Windows, and a freedesktop Autostart entry
+// ($XDG_CONFIG_HOME/autostart/gomusetool.desktop) on Linux.
+package autostart
+
+import (
+	"errors"
+	"slices"
+)
+
+var ErrNotInstalled = errors.New("autostart entry not installed")
+
+// Options configure the autostart entry
+type Options struct {
+	Args []string // 传给程序的启动参数，如 --tray
+}
+
+// Entry describes the installed autostart entry
+type Entry struct {
+	Path    string   // 登录时启动的可执行文件
+	Args    []string // 启动参数
+	Enabled bool     // 用户在系统设置中关闭自启动时为 false
+}
+
+// Matches reports whether the entry starts exePath with the given options,
+// i.e. it does not need to be rewritten
+func (e Entry) Matches(exePath string, opts Options) bool {
+	return e.Path == exePath && slices.Equal(e.Args, opts.Args)
+}
+
+// IsEnabled reports whether an entry is installed and not turned off by
+// the user in the desktop's startup settings
+func IsEnabled() bool {
+	entry, err := Read()
+	return err == nil && entry.Enabled
+}
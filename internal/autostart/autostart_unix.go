@@ -0,0 +1,105 @@
+//go:build !windows
+
+package autostart
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-musetool/internal/desktopentry"
+)
+
+const (
+	desktopFileName = "gomusetool.desktop"
+	desktopGroup    = "[Desktop Entry]"
+)
+
+func configHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config")
+}
+
+// desktopFilePath is the per-user autostart entry defined by the
+// freedesktop Desktop Application Autostart Specification
+func desktopFilePath() string {
+	return filepath.Join(configHome(), "autostart", desktopFileName)
+}
+
+// Enable writes the autostart entry for exePath. Rewriting the entry also
+// clears Hidden=true left behind when the user turned it off.
+func Enable(exePath string, opts Options) error {
+	path := desktopFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	var sb strings.Builder
+	sb.WriteString(desktopGroup + "\n")
+	sb.WriteString("Type=Application\n")
+	sb.WriteString("Name=Go MuseTool\n")
+	sb.WriteString("Exec=" + desktopentry.QuoteExec(append([]string{exePath}, opts.Args...)) + "\n")
+	sb.WriteString("Terminal=false\n")
+	sb.WriteString("X-GNOME-Autostart-enabled=true\n")
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return err
+	}
+	log.Printf("Auto-start enabled: %s", exePath)
+	return nil
+}
+
+// Disable removes the autostart entry
+func Disable() error {
+	if err := os.Remove(desktopFilePath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	log.Printf("Auto-start disabled")
+	return nil
+}
+
+// Read parses the installed autostart entry
+func Read() (Entry, error) {
+	f, err := os.Open(desktopFilePath())
+	if os.IsNotExist(err) {
+		return Entry{}, ErrNotInstalled
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+	defer f.Close()
+
+	entry := Entry{Enabled: true}
+	inGroup := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inGroup = line == desktopGroup
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !inGroup || !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Exec":
+			if args := desktopentry.ParseExec(strings.TrimSpace(value)); len(args) > 0 {
+				entry.Path, entry.Args = args[0], args[1:]
+			}
+		case "Hidden":
+			// Hidden=true 表示用户删除了该项（桌面环境的 "启动应用程序" 设置）
+			if strings.TrimSpace(value) == "true" {
+				entry.Enabled = false
+			}
+		case "X-GNOME-Autostart-enabled":
+			if strings.TrimSpace(value) == "false" {
+				entry.Enabled = false
+			}
+		}
+	}
+	return entry, scanner.Err()
+}
@@ -0,0 +1,93 @@
+//go:build !windows
+
+package autostart
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useTempConfigHome points XDG_CONFIG_HOME at a temporary directory and
+// returns the path of the autostart entry
+func useTempConfigHome(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	return filepath.Join(dir, "autostart", desktopFileName)
+}
+
+func TestEnableReadDisable(t *testing.T) {
+	path := useTempConfigHome(t)
+	if _, err := Read(); !errors.Is(err, ErrNotInstalled) {
+		t.Fatalf("Read before Enable = %v, want %v", err, ErrNotInstalled)
+	}
+	if IsEnabled() {
+		t.Error("IsEnabled = true before Enable")
+	}
+
+	exe := "/opt/Go MuseTool/musetool"
+	opts := Options{Args: []string{"--tray", "--delay", "5"}}
+	if err := Enable(exe, opts); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), desktopGroup+"\n") || !strings.Contains(string(data), "\nType=Application\n") {
+		t.Errorf("desktop file:\n%s", data)
+	}
+	entry, err := Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entry.Enabled || !entry.Matches(exe, opts) {
+		t.Errorf("Read = %+v, want an enabled entry for %q %q", entry, exe, opts.Args)
+	}
+
+	if err := Disable(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("desktop file still exists after Disable: %v", err)
+	}
+	if err := Disable(); err != nil {
+		t.Errorf("Disable without an entry: %v", err)
+	}
+}
+
+func TestReadDisabledByDesktop(t *testing.T) {
+	tests := []struct {
+		name, extra string
+		enabled     bool
+	}{
+		{"enabled", "", true},
+		{"hidden", "Hidden=true\n", false},
+		{"gnome disabled", "X-GNOME-Autostart-enabled=false\n", false},
+		{"other group", "[Desktop Action New]\nHidden=true\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := useTempConfigHome(t)
+			if err := Enable("/usr/bin/musetool", Options{}); err != nil {
+				t.Fatal(err)
+			}
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.WriteString(tt.extra)
+			f.Close()
+
+			entry, err := Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if entry.Enabled != tt.enabled || IsEnabled() != tt.enabled {
+				t.Errorf("Enabled = %v, want %v", entry.Enabled, tt.enabled)
+			}
+		})
+	}
+}
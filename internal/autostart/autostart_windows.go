@@ -0,0 +1,97 @@
+package autostart
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+const (
+	runKeyPath = `Software\Microsoft\Windows\CurrentVersion\Run`
+	appName    = "GoMuseTool"
+
+	// 任务管理器 "启动" 页中关闭的项目记录在这里，第一个字节为奇数表示已关闭
+	approvedKeyPath = `Software\Microsoft\Windows\CurrentVersion\Explorer\StartupApproved\Run`
+)
+
+// Enable starts exePath when the user logs in
+func Enable(exePath string, opts Options) error {
+	k, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+
+	// 路径总是加引号，参数按需要加引号
+	command := `"` + exePath + `"`
+	if len(opts.Args) > 0 {
+		command += " " + windows.ComposeCommandLine(opts.Args)
+	}
+	if err := k.SetStringValue(appName, command); err != nil {
+		return err
+	}
+	// 清除任务管理器中的 "已禁用" 状态
+	if approved, err := registry.OpenKey(registry.CURRENT_USER, approvedKeyPath, registry.SET_VALUE); err == nil {
+		approved.DeleteValue(appName)
+		approved.Close()
+	}
+	log.Printf("Auto-start enabled: %s", exePath)
+	return nil
+}
+
+// Disable removes the Run value
+func Disable() error {
+	k, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+
+	if err := k.DeleteValue(appName); err != nil && !errors.Is(err, registry.ErrNotExist) {
+		return err
+	}
+	log.Printf("Auto-start disabled")
+	return nil
+}
+
+// Read returns the installed Run value
+func Read() (Entry, error) {
+	k, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer k.Close()
+
+	value, _, err := k.GetStringValue(appName)
+	if errors.Is(err, registry.ErrNotExist) {
+		return Entry{}, ErrNotInstalled
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+	entry := Entry{Enabled: !disabledInTaskManager()}
+	if !strings.HasPrefix(value, `"`) {
+		// 旧版本写入的是不带引号的路径，没有参数
+		entry.Path = value
+		return entry, nil
+	}
+	args, err := windows.DecomposeCommandLine(value)
+	if err != nil || len(args) == 0 {
+		return entry, err
+	}
+	entry.Path, entry.Args = args[0], args[1:]
+	return entry, nil
+}
+
+func disabledInTaskManager() bool {
+	k, err := registry.OpenKey(registry.CURRENT_USER, approvedKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer k.Close()
+	data, _, err := k.GetBinaryValue(appName)
+	return err == nil && len(data) > 0 && data[0]&1 != 0
+}
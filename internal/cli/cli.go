@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"go-musetool/internal/ipc"
+	"go-musetool/internal/launcher"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK       = 0 // 成功
+	ExitError    = 1 // 一般错误（读写配置失败、启动失败等）
+	ExitUsage    = 2 // 参数错误
+	ExitNotFound = 3 // 分组或快捷方式不存在
+)
+
+// command describes a single headless subcommand
+type command struct {
+	usage string
+	help  string
+	run   func(ctx *context, args []string) error
+}
+
+// context carries the state shared by all subcommands
+type context struct {
+	configPath string
+	config     *model.Config
+	stdout     io.Writer
+	stderr     io.Writer
+	json       bool
+}
+
+// usageError marks errors caused by invalid arguments
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, v ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, v...)}
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"list":      {usage: "list [--group G] [--json]", help: "List groups and shortcuts", run: runList},
+		"add":       {usage: "add --group G --name N (--path P | --command C | --text T | --steps JSON) [--kind K] [--keyword W] [--args A] [--workdir D] [--icon I] [--json]", help: "Add a shortcut to a group", run: runAdd},
+		"remove":    {usage: "remove \"Group/Name\" | --group G --name N [--json]", help: "Remove a shortcut", run: runRemove},
+		"move":      {usage: "move \"Group/Name\" --to T | --group G --name N --to T [--json]", help: "Move a shortcut to another group", run: runMove},
+		"launch":    {usage: "launch \"Group/Name\" | --group G --name N [--input Name=Value]... [--json]", help: "Launch a shortcut", run: runLaunch},
+		"workspace": {usage: "workspace GROUP [--json]", help: "Launch every shortcut of a group using its \"Launch all\" settings", run: runWorkspace},
+		"export":    {usage: "export FILE.zip [--json]", help: "Export configuration and icons to a zip archive", run: runExport},
+		"import":    {usage: "import FILE.zip [--json]", help: "Import configuration and icons from a zip archive", run: runImport},
+		"groups":    {usage: "groups [list | add NAME | rename OLD NEW | delete NAME] [--json]", help: "Manage groups", run: runGroups},
+		"help":      {usage: "help", help: "Show this help", run: runHelp},
+	}
+}
+
+// IsCommand reports whether name is a headless subcommand.
+// main uses it to decide whether to skip the GUI entirely.
+func IsCommand(name string) bool {
+	switch name {
+	case "-h", "--help":
+		return true
+	}
+	_, ok := commands[name]
+	return ok
+}
+
+// Run executes a headless subcommand against the config at configPath and
+// returns the process exit code. args[0] is the subcommand name.
+func Run(configPath string, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return ExitUsage
+	}
+
+	name := args[0]
+	if name == "-h" || name == "--help" {
+		name = "help"
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command: %s\n", name)
+		printUsage(stderr)
+		return ExitUsage
+	}
+
+	ctx := &context{
+		configPath: configPath,
+		stdout:     stdout,
+		stderr:     stderr,
+	}
+
+	if name != "help" {
+		config, err := storage.LoadConfig(configPath)
+		if err != nil {
+			return ctx.fail(fmt.Errorf("failed to load config: %w", err))
+		}
+		ctx.config = config
+		launcher.SetTerminal(config.Terminal)
+		launcher.SetRoutes(config.Routes)
+	}
+
+	if err := cmd.run(ctx, args[1:]); err != nil {
+		code := ctx.fail(err)
+		if code == ExitUsage && !ctx.json {
+			fmt.Fprintf(stderr, "usage: %s\n", cmd.usage)
+		}
+		return code
+	}
+	return ExitOK
+}
+
+// fail reports err and maps it to an exit code
+func (ctx *context) fail(err error) int {
+	code := ExitError
+	var ue *usageError
+	switch {
+	case errors.As(err, &ue), errors.Is(err, flag.ErrHelp):
+		code = ExitUsage
+	case errors.Is(err, storage.ErrGroupNotFound), errors.Is(err, storage.ErrShortcutNotFound):
+		code = ExitNotFound
+	}
+
+	if ctx.json {
+		writeJSON(ctx.stderr, map[string]interface{}{"error": err.Error(), "code": code})
+	} else {
+		fmt.Fprintf(ctx.stderr, "error: %v\n", err)
+	}
+	return code
+}
+
+// emit writes v as JSON in --json mode, otherwise the human readable text
+func (ctx *context) emit(v interface{}, text string) {
+	if ctx.json {
+		writeJSON(ctx.stdout, v)
+		return
+	}
+	if text != "" {
+		fmt.Fprintln(ctx.stdout, text)
+	}
+}
+
+// notifyRunning asks the running instance to re-read the configuration;
+// tests replace it
+var notifyRunning = func() error {
+	return ipc.Send(ipc.NewRequest(ipc.ActionReload))
+}
+
+// save persists the modified configuration. A running instance keeps its own
+// copy in memory and would overwrite the file with it on its next save, so it
+// is told to reload.
+func (ctx *context) save() error {
+	if err := storage.SaveConfig(ctx.configPath, ctx.config); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if err := notifyRunning(); err != nil && !errors.Is(err, ipc.ErrNotRunning) {
+		fmt.Fprintf(ctx.stderr, "warning: the running instance did not reload the configuration: %v\n", err)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v interface{}) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false) // 保留中文字符，不转义为Unicode
+	encoder.Encode(v)
+}
+
+// newFlagSet creates a flag set with the shared --json flag bound to ctx
+func newFlagSet(ctx *context, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&ctx.json, "json", false, "machine-readable JSON output")
+	return fs
+}
+
+// parseArgs parses flags that may appear before, between or after positional
+// arguments, e.g. `launch "Dev/Terminal" --json`.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usagef("%v", err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// resolveTarget returns the group and shortcut named either by a single
+// "Group/Name" positional argument or by --group/--name flags
+func resolveTarget(ctx *context, positional []string, group, name string) (string, model.Shortcut, error) {
+	switch {
+	case len(positional) == 1 && group == "" && name == "":
+		groupName, s, err := storage.LookupShortcut(ctx.config, positional[0])
+		if err != nil {
+			return "", s, fmt.Errorf("%w: %s", err, positional[0])
+		}
+		return groupName, s, nil
+	case len(positional) == 0 && group != "" && name != "":
+		s, err := storage.FindShortcut(ctx.config, group, name)
+		if err != nil {
+			return "", s, fmt.Errorf("%w: %s", err, shortcutRef(group, name))
+		}
+		return group, s, nil
+	default:
+		return "", model.Shortcut{}, usagef("specify either \"Group/Name\" or --group and --name")
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: GoMuseTool <command> [arguments]")
+	fmt.Fprintln(w, "Run without a command to start the launcher window.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", name, commands[name].help)
+		fmt.Fprintf(w, "           %s\n", commands[name].usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit codes: 0 success, 1 error, 2 invalid arguments, 3 group or shortcut not found")
+}
+
+func runHelp(ctx *context, args []string) error {
+	printUsage(ctx.stdout)
+	return nil
+}
+
+// shortcutRef formats a "Group/Name" reference
+func shortcutRef(group, name string) string {
+	return group + "/" + name
+}
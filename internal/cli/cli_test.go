@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-musetool/internal/ipc"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+)
+
+func TestMain(m *testing.M) {
+	// 测试中不能连接真正运行中的实例
+	notifyRunning = func() error { return ipc.ErrNotRunning }
+	os.Exit(m.Run())
+}
+
+// writeConfig creates a config with the group Dev holding the URL shortcut Docs
+func writeConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	config := &model.Config{Groups: []model.Group{{
+		Name: "Dev",
+		Shortcuts: []model.Shortcut{
+			{Name: "Docs", Kind: model.KindURL, Path: "https://example.com"},
+		},
+	}}}
+	if err := storage.SaveConfig(path, config); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func run(path string, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = Run(path, args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"no command", nil, ExitUsage},
+		{"unknown command", []string{"frobnicate"}, ExitUsage},
+		{"help", []string{"--help"}, ExitOK},
+		{"list", []string{"list"}, ExitOK},
+		{"list unknown group", []string{"list", "--group", "Nope"}, ExitNotFound},
+		{"unknown flag", []string{"list", "--bogus"}, ExitUsage},
+		{"add without name", []string{"add", "--group", "Dev", "--path", "https://a.example"}, ExitUsage},
+		{"add unknown kind", []string{"add", "--group", "Dev", "--name", "X", "--kind", "toaster"}, ExitUsage},
+		{"add to missing group", []string{"add", "--group", "Nope", "--name", "X", "--path", "https://a.example"}, ExitNotFound},
+		{"add duplicate", []string{"add", "--group", "Dev", "--name", "Docs", "--path", "https://a.example"}, ExitError},
+		{"add", []string{"add", "--group", "Dev", "--name", "Site", "--path", "https://a.example"}, ExitOK},
+		{"remove missing", []string{"remove", "Dev/Nope"}, ExitNotFound},
+		{"remove ambiguous target", []string{"remove", "Dev/Docs", "--group", "Dev"}, ExitUsage},
+		{"remove", []string{"remove", "Dev/Docs"}, ExitOK},
+		{"move without --to", []string{"move", "Dev/Docs"}, ExitUsage},
+		{"move to missing group", []string{"move", "Dev/Docs", "--to", "Nope"}, ExitNotFound},
+		{"groups add", []string{"groups", "add", "Work"}, ExitOK},
+		{"groups delete missing", []string{"groups", "delete", "Nope"}, ExitNotFound},
+		{"groups unknown action", []string{"groups", "shuffle"}, ExitUsage},
+		{"workspace without group", []string{"workspace"}, ExitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := run(writeConfig(t), tt.args...)
+			if code != tt.want {
+				t.Errorf("Run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.want, stderr)
+			}
+		})
+	}
+}
+
+func TestJSONOutput(t *testing.T) {
+	path := writeConfig(t)
+
+	code, stdout, _ := run(path, "list", "--json")
+	if code != ExitOK {
+		t.Fatalf("list --json exited with %d", code)
+	}
+	var groups []model.Group
+	if err := json.Unmarshal([]byte(stdout), &groups); err != nil {
+		t.Fatalf("list --json printed invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(groups) != 1 || groups[0].Name != "Dev" || groups[0].Shortcuts[0].Name != "Docs" {
+		t.Errorf("list --json = %+v", groups)
+	}
+
+	code, stdout, _ = run(path, "add", "--json", "--group", "Dev", "--name", "Site", "--path", "https://a.example")
+	if code != ExitOK {
+		t.Fatalf("add --json exited with %d", code)
+	}
+	var added shortcutResult
+	if err := json.Unmarshal([]byte(stdout), &added); err != nil {
+		t.Fatalf("add --json printed invalid JSON: %v\n%s", err, stdout)
+	}
+	if added.Group != "Dev" || added.Shortcut.Name != "Site" || added.Shortcut.Kind != model.KindURL {
+		t.Errorf("add --json = %+v", added)
+	}
+
+	// 错误以 JSON 写到 stderr，并带上退出码
+	code, stdout, stderr := run(path, "remove", "Dev/Nope", "--json")
+	if code != ExitNotFound {
+		t.Fatalf("remove --json exited with %d", code)
+	}
+	if stdout != "" {
+		t.Errorf("remove --json wrote to stdout: %s", stdout)
+	}
+	var failure struct {
+		Error string `json:"error"`
+		Code  int    `json:"code"`
+	}
+	if err := json.Unmarshal([]byte(stderr), &failure); err != nil {
+		t.Fatalf("error output is not JSON: %v\n%s", err, stderr)
+	}
+	if failure.Code != ExitNotFound || failure.Error == "" {
+		t.Errorf("error output = %+v", failure)
+	}
+}
+
+func TestMutationsAreSavedAndAnnounced(t *testing.T) {
+	notified := 0
+	notifyRunning = func() error { notified++; return nil }
+	defer func() { notifyRunning = func() error { return ipc.ErrNotRunning } }()
+
+	path := writeConfig(t)
+	if code, _, stderr := run(path, "list"); code != ExitOK || notified != 0 {
+		t.Fatalf("list: code %d, notified %d (%s)", code, notified, stderr)
+	}
+	if code, _, stderr := run(path, "move", "Dev/Docs", "--to", "Work"); code != ExitNotFound || notified != 0 {
+		t.Fatalf("failed move: code %d, notified %d (%s)", code, notified, stderr)
+	}
+	run(path, "groups", "add", "Work")
+	if code, _, stderr := run(path, "move", "Dev/Docs", "--to", "Work"); code != ExitOK {
+		t.Fatalf("move: code %d (%s)", code, stderr)
+	}
+	if notified != 2 {
+		t.Errorf("running instance notified %d times, want 2", notified)
+	}
+
+	config, err := storage.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.FindShortcut(config, "Work", "Docs"); err != nil {
+		t.Errorf("moved shortcut not saved: %v", err)
+	}
+}
@@ -0,0 +1,432 @@
+package cli
+
+import (
+	stdctx "context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-musetool/internal/history"
+	"go-musetool/internal/ipc"
+	"go-musetool/internal/launcher"
+	"go-musetool/internal/macro"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+	"go-musetool/internal/workspace"
+)
+
+// shortcutResult is the JSON shape used when a command reports a single shortcut
+type shortcutResult struct {
+	Group    string         `json:"group"`
+	Shortcut model.Shortcut `json:"shortcut"`
+}
+
+func runList(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "list")
+	group := fs.String("group", "", "only list this group")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usagef("unexpected argument: %s", positional[0])
+	}
+
+	groups := ctx.config.Groups
+	if *group != "" {
+		g := storage.FindGroup(ctx.config, *group)
+		if g == nil {
+			return fmt.Errorf("%w: %s", storage.ErrGroupNotFound, *group)
+		}
+		groups = []model.Group{*g}
+	}
+
+	var sb strings.Builder
+	for _, g := range groups {
+		fmt.Fprintf(&sb, "[%s]\n", g.Name)
+		for _, s := range g.Shortcuts {
+			fmt.Fprintf(&sb, "  %s\t%s\t%s\n", s.Name, launcher.KindOf(s), launcher.Target(s))
+		}
+	}
+	ctx.emit(groups, strings.TrimSuffix(sb.String(), "\n"))
+	return nil
+}
+
+func runAdd(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "add")
+	group := fs.String("group", "", "target group")
+	name := fs.String("name", "", "shortcut name")
+	kind := fs.String("kind", "", "application, file, folder, url, command, snippet, macro or search (detected if omitted)")
+	path := fs.String("path", "", "file, folder or URL to launch")
+	icon := fs.String("icon", "", "icon path (optional)")
+	appArgs := fs.String("args", "", "application arguments")
+	workDir := fs.String("workdir", "", "working directory for application or command")
+	command := fs.String("command", "", "command line (kind command)")
+	text := fs.String("text", "", "text copied to the clipboard (kind snippet)")
+	keyword := fs.String("keyword", "", "keyword typed in the search box (kind search, --path is the URL with {query})")
+	autoPaste := fs.Bool("auto-paste", false, "paste the snippet into the previous window after copying")
+	steps := fs.String("steps", "", "JSON array of macro steps (kind macro)")
+	inTerminal := fs.Bool("terminal", false, "run the application or command in a terminal window")
+	keepOpen := fs.Bool("keep-open", false, "keep the terminal open after the program exits")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usagef("unexpected argument: %s", positional[0])
+	}
+	if *group == "" || *name == "" {
+		return usagef("--group and --name are required")
+	}
+
+	shortcut := model.Shortcut{
+		Name:     *name,
+		Kind:     *kind,
+		Path:     *path,
+		IconPath: *icon,
+		Args:     *appArgs,
+		WorkDir:  *workDir,
+		Command:  *command,
+		Text:     *text,
+		Keyword:  *keyword,
+
+		AutoPaste:     *autoPaste,
+		RunInTerminal: *inTerminal || *keepOpen,
+		KeepOpen:      *keepOpen,
+	}
+	if *steps != "" {
+		if err := json.Unmarshal([]byte(*steps), &shortcut.Steps); err != nil {
+			return usagef("invalid --steps: %v", err)
+		}
+		if err := macro.Validate(shortcut.Steps); err != nil {
+			return usagef("invalid --steps: %v", err)
+		}
+	}
+	switch shortcut.Kind {
+	case "":
+		if len(shortcut.Steps) > 0 {
+			shortcut.Kind = model.KindMacro
+		} else if shortcut.Command != "" {
+			shortcut.Kind = model.KindCommand
+		} else if shortcut.Text != "" {
+			shortcut.Kind = model.KindSnippet
+		} else {
+			shortcut.Kind = launcher.DetectKind(shortcut.Path)
+		}
+	case model.KindApplication, model.KindFile, model.KindFolder, model.KindURL, model.KindCommand, model.KindSnippet, model.KindMacro, model.KindSearch:
+	default:
+		return usagef("unknown kind: %s", shortcut.Kind)
+	}
+	if err := launcher.Validate(shortcut); err != nil {
+		required := "--path"
+		switch shortcut.Kind {
+		case model.KindCommand:
+			required = "--command"
+		case model.KindSnippet:
+			required = "--text"
+		case model.KindMacro:
+			required = "--steps"
+		}
+		return usagef("%s shortcuts require %s", shortcut.Kind, required)
+	}
+
+	if _, err := storage.FindShortcut(ctx.config, *group, *name); err == nil {
+		return fmt.Errorf("%w: %s", storage.ErrShortcutExists, shortcutRef(*group, *name))
+	}
+
+	if err := storage.AddShortcut(ctx.config, *group, shortcut); err != nil {
+		return fmt.Errorf("%w: %s", err, *group)
+	}
+	if err := ctx.save(); err != nil {
+		return err
+	}
+	ctx.emit(shortcutResult{Group: *group, Shortcut: shortcut}, "added "+shortcutRef(*group, *name))
+	return nil
+}
+
+func runRemove(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "remove")
+	group := fs.String("group", "", "group containing the shortcut")
+	name := fs.String("name", "", "shortcut name")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	groupName, shortcut, err := resolveTarget(ctx, positional, *group, *name)
+	if err != nil {
+		return err
+	}
+	if err := storage.RemoveShortcut(ctx.config, groupName, shortcut.Name); err != nil {
+		return err
+	}
+	if err := ctx.save(); err != nil {
+		return err
+	}
+	ctx.emit(shortcutResult{Group: groupName, Shortcut: shortcut}, "removed "+shortcutRef(groupName, shortcut.Name))
+	return nil
+}
+
+func runMove(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "move")
+	group := fs.String("group", "", "group containing the shortcut")
+	name := fs.String("name", "", "shortcut name")
+	to := fs.String("to", "", "destination group")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *to == "" {
+		return usagef("--to is required")
+	}
+
+	groupName, shortcut, err := resolveTarget(ctx, positional, *group, *name)
+	if err != nil {
+		return err
+	}
+	if _, err := storage.FindShortcut(ctx.config, *to, shortcut.Name); err == nil && groupName != *to {
+		return fmt.Errorf("%w: %s", storage.ErrShortcutExists, shortcutRef(*to, shortcut.Name))
+	}
+	if err := storage.MoveShortcut(ctx.config, groupName, *to, shortcut.Name); err != nil {
+		return fmt.Errorf("%w: %s", err, *to)
+	}
+	if err := ctx.save(); err != nil {
+		return err
+	}
+	ctx.emit(shortcutResult{Group: *to, Shortcut: shortcut}, "moved "+shortcutRef(groupName, shortcut.Name)+" to "+*to)
+	return nil
+}
+
+func runLaunch(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "launch")
+	group := fs.String("group", "", "group containing the shortcut")
+	name := fs.String("name", "", "shortcut name")
+	inputs := inputFlag{}
+	fs.Var(inputs, "input", "value for an {input:Name} placeholder as Name=Value (repeatable)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	groupName, shortcut, err := resolveTarget(ctx, positional, *group, *name)
+	if err != nil {
+		return err
+	}
+	ref := shortcutRef(groupName, shortcut.Name)
+	if err := launchShortcut(ctx, groupName, shortcut, inputs); err != nil {
+		return fmt.Errorf("failed to launch %s: %w", ref, err)
+	}
+	ctx.emit(shortcutResult{Group: groupName, Shortcut: shortcut}, "launched "+ref)
+	return nil
+}
+
+// inputFlag collects repeated --input Name=Value flags
+type inputFlag map[string]string
+
+func (f inputFlag) String() string { return "" }
+
+func (f inputFlag) Set(value string) error {
+	name, v, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected Name=Value, got %q", value)
+	}
+	f[name] = v
+	return nil
+}
+
+// launchShortcut launches directly, except for shortcuts that need the GUI
+func launchShortcut(ctx *context, groupName string, shortcut model.Shortcut, inputs map[string]string) error {
+	// 剪贴板、输入提示窗口和宏进度窗口属于界面进程，交给正在运行的实例处理
+	needsApp := launcher.UsesClipboard(shortcut)
+	switch launcher.KindOf(shortcut) {
+	case model.KindSnippet, model.KindMacro:
+		needsApp = true
+	}
+	for _, name := range launcher.InputNames(shortcut) {
+		if _, ok := inputs[name]; !ok {
+			needsApp = true
+		}
+	}
+	if needsApp {
+		if err := ipc.Send(ipc.NewRequest(ipc.ActionLaunch, shortcutRef(groupName, shortcut.Name))); err != nil {
+			return fmt.Errorf("needs the running app: %w", err)
+		}
+		return nil
+	}
+
+	resolved, err := launcher.Resolve(shortcut, launcher.Values{Inputs: inputs, SelectedGroup: groupName})
+	if err != nil {
+		return err
+	}
+	err = launcher.Launch(resolved)
+	ctx.recordLaunch(shortcut, err)
+	return err
+}
+
+// recordLaunch appends a direct launch to the history file shared with the app
+func (ctx *context) recordLaunch(shortcut model.Shortcut, launchErr error) {
+	if ctx.config.HistoryDisabled || shortcut.ID == "" {
+		return
+	}
+	entry := history.NewEntry(shortcut.ID, shortcut.Name, time.Now(), launchErr)
+	if err := history.Append(history.Path(filepath.Dir(ctx.configPath)), entry); err != nil {
+		fmt.Fprintf(ctx.stderr, "warning: failed to record launch history: %v\n", err)
+	}
+}
+
+// workspaceResult is the JSON shape of one launch reported by workspace
+type workspaceResult struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+func runWorkspace(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "workspace")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("workspace requires exactly one group name")
+	}
+
+	group := storage.FindGroup(ctx.config, positional[0])
+	if group == nil {
+		return fmt.Errorf("%w: %s", storage.ErrGroupNotFound, positional[0])
+	}
+	summary := workspace.Launch(stdctx.Background(), *group, func(s model.Shortcut) error {
+		return launchShortcut(ctx, group.Name, s, nil)
+	})
+
+	results := make([]workspaceResult, len(summary.Results))
+	var sb strings.Builder
+	for i, r := range summary.Results {
+		results[i] = workspaceResult{Name: r.Name, OK: r.Err == nil}
+		if r.Err != nil {
+			results[i].Error = r.Err.Error()
+			fmt.Fprintf(&sb, "failed   %s: %v\n", shortcutRef(group.Name, r.Name), r.Err)
+		} else {
+			fmt.Fprintf(&sb, "launched %s\n", shortcutRef(group.Name, r.Name))
+		}
+	}
+	ctx.emit(results, strings.TrimSuffix(sb.String(), "\n"))
+	if failed := summary.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%d of %d launches failed", len(failed), len(summary.Results))
+	}
+	return nil
+}
+
+func runExport(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "export")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("export requires exactly one file name")
+	}
+
+	filename := positional[0]
+	if !strings.HasSuffix(strings.ToLower(filename), ".zip") {
+		filename += ".zip"
+	}
+	if err := storage.ExportConfigWithIcons(filename, ctx.config); err != nil {
+		return err
+	}
+	ctx.emit(map[string]string{"file": filename}, "exported to "+filename)
+	return nil
+}
+
+func runImport(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "import")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("import requires exactly one file name")
+	}
+
+	newConfig, err := storage.ImportConfigWithIcons(positional[0], filepath.Dir(ctx.configPath))
+	if err != nil {
+		return err
+	}
+	ctx.config = newConfig
+	if err := ctx.save(); err != nil {
+		return err
+	}
+	ctx.emit(map[string]interface{}{"file": positional[0], "groups": len(newConfig.Groups)},
+		fmt.Sprintf("imported %d groups from %s", len(newConfig.Groups), positional[0]))
+	return nil
+}
+
+func runGroups(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "groups")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	action := "list"
+	if len(positional) > 0 {
+		action, positional = positional[0], positional[1:]
+	}
+
+	switch action {
+	case "list":
+		if len(positional) != 0 {
+			return usagef("groups list takes no arguments")
+		}
+		names := make([]string, 0, len(ctx.config.Groups))
+		for _, g := range ctx.config.Groups {
+			names = append(names, g.Name)
+		}
+		ctx.emit(names, strings.Join(names, "\n"))
+		return nil
+
+	case "add":
+		if len(positional) != 1 {
+			return usagef("groups add requires a group name")
+		}
+		if err := storage.AddGroup(ctx.config, positional[0]); err != nil {
+			return fmt.Errorf("%w: %s", err, positional[0])
+		}
+		if err := ctx.save(); err != nil {
+			return err
+		}
+		ctx.emit(map[string]string{"group": positional[0]}, "added group "+positional[0])
+		return nil
+
+	case "rename":
+		if len(positional) != 2 {
+			return usagef("groups rename requires the old and new group names")
+		}
+		if err := storage.RenameGroup(ctx.config, positional[0], positional[1]); err != nil {
+			return fmt.Errorf("%w: %s", err, positional[0])
+		}
+		if err := ctx.save(); err != nil {
+			return err
+		}
+		ctx.emit(map[string]string{"group": positional[1], "oldName": positional[0]},
+			"renamed group "+positional[0]+" to "+positional[1])
+		return nil
+
+	case "delete":
+		if len(positional) != 1 {
+			return usagef("groups delete requires a group name")
+		}
+		if err := storage.DeleteGroup(ctx.config, positional[0]); err != nil {
+			return fmt.Errorf("%w: %s", err, positional[0])
+		}
+		if err := ctx.save(); err != nil {
+			return err
+		}
+		ctx.emit(map[string]string{"group": positional[0]}, "deleted group "+positional[0])
+		return nil
+	}
+
+	return usagef("unknown groups action: %s", action)
+}
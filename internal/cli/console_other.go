@@ -0,0 +1,6 @@
+//go:build !windows
+
+package cli
+
+// AttachConsole is a no-op outside Windows, where stdout is always inherited.
+func AttachConsole() {}
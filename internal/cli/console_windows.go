@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"os"
+	"syscall"
+)
+
+// AttachConsole attaches the process to the console of the parent shell.
+// The release build uses -H windowsgui, so without this the output of a
+// subcommand run from cmd or PowerShell would be lost. Streams that are
+// already redirected (pipes, files) are left untouched.
+func AttachConsole() {
+	const attachParentProcess = ^uintptr(0) // ATTACH_PARENT_PROCESS (-1)
+
+	stdoutValid := isValidHandle(syscall.STD_OUTPUT_HANDLE)
+	stderrValid := isValidHandle(syscall.STD_ERROR_HANDLE)
+	if stdoutValid && stderrValid {
+		return
+	}
+
+	procAttachConsole := syscall.NewLazyDLL("kernel32.dll").NewProc("AttachConsole")
+	if ret, _, _ := procAttachConsole.Call(attachParentProcess); ret == 0 {
+		return
+	}
+
+	conout, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	if !stdoutValid {
+		os.Stdout = conout
+	}
+	if !stderrValid {
+		os.Stderr = conout
+	}
+}
+
+func isValidHandle(std int) bool {
+	h, err := syscall.GetStdHandle(std)
+	return err == nil && h != 0 && h != syscall.InvalidHandle
+}
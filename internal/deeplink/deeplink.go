@@ -0,0 +1,172 @@
+// Package deeplink parses musetool:// URLs into typed actions.
+//
+// Supported forms:
+//
+//	musetool://show                  -> show the main window
+//	musetool://show?group=Games      -> show the main window on group "Games"
+//	musetool://group/Games           -> same as above
+//	musetool://launch/Dev/Terminal   -> launch shortcut "Terminal" in group "Dev"
+//	musetool://launch?ref=Dev/Terminal
+//	musetool://launch?group=Dev&name=Terminal
+//
+// Path segments and query values are percent-decoded, so names containing
+// spaces can be written as %20. A "/" in a group name needs no escaping: it is
+// resolved against the existing groups by storage.LookupShortcut, and %2F
+// means the same as a literal "/".
+package deeplink
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go-musetool/internal/ipc"
+)
+
+// Scheme is the URL scheme registered with the operating system
+const Scheme = "musetool"
+
+// Action identifies what a link asks the launcher to do
+type Action string
+
+const (
+	ActionShow   Action = "show"
+	ActionGroup  Action = "group"
+	ActionLaunch Action = "launch"
+)
+
+var (
+	ErrNotDeepLink    = errors.New("not a musetool:// link")
+	ErrUnknownAction  = errors.New("unknown link action")
+	ErrMissingTarget  = errors.New("link is missing its target")
+	ErrInvalidLinkRef = errors.New("launch link must be Group/Name")
+)
+
+// Link is a parsed deep link
+type Link struct {
+	Action Action
+	Group  string // ActionGroup: 分组名; ActionShow: 可选
+	Ref    string // ActionLaunch: "Group/Name"，组名中的 "/" 由 storage.LookupShortcut 解析
+}
+
+// IsLink reports whether arg looks like a musetool:// URL
+func IsLink(arg string) bool {
+	return strings.HasPrefix(strings.ToLower(arg), Scheme+":")
+}
+
+// Parse converts a musetool:// URL into a Link
+func Parse(raw string) (Link, error) {
+	raw = strings.TrimSpace(raw)
+	if !IsLink(raw) {
+		return Link{}, ErrNotDeepLink
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link %q: %w", raw, err)
+	}
+
+	// musetool://launch/Dev/Terminal 中 "launch" 被解析为 Host；
+	// musetool:launch/Dev/Terminal 这种不规范写法则落在 Opaque 中
+	action := u.Host
+	rest := u.EscapedPath()
+	if u.Opaque != "" {
+		action, rest, _ = strings.Cut(u.Opaque, "/")
+	} else {
+		rest = strings.TrimPrefix(rest, "/")
+	}
+	// 浏览器和 Windows 经常会在末尾补一个 "/"
+	rest = strings.TrimSuffix(rest, "/")
+
+	segments, err := splitPath(rest)
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link %q: %w", raw, err)
+	}
+	query := u.Query()
+
+	switch Action(strings.ToLower(action)) {
+	case ActionShow:
+		link := Link{Action: ActionShow, Group: query.Get("group")}
+		if link.Group == "" && len(segments) > 0 {
+			link.Group = strings.Join(segments, "/")
+		}
+		return link, nil
+
+	case ActionGroup:
+		group := query.Get("name")
+		if group == "" {
+			group = strings.Join(segments, "/")
+		}
+		if group == "" {
+			return Link{}, fmt.Errorf("%w: %q", ErrMissingTarget, raw)
+		}
+		return Link{Action: ActionGroup, Group: group}, nil
+
+	case ActionLaunch:
+		ref := query.Get("ref")
+		if g, n := query.Get("group"), query.Get("name"); ref == "" && g != "" && n != "" {
+			ref = g + "/" + n
+		}
+		if ref == "" {
+			ref = strings.Join(segments, "/")
+		}
+		if ref == "" {
+			return Link{}, fmt.Errorf("%w: %q", ErrMissingTarget, raw)
+		}
+		if idx := strings.Index(ref, "/"); idx <= 0 || strings.HasSuffix(ref, "/") {
+			return Link{}, fmt.Errorf("%w: %q", ErrInvalidLinkRef, ref)
+		}
+		return Link{Action: ActionLaunch, Ref: ref}, nil
+	}
+	return Link{}, fmt.Errorf("%w: %q", ErrUnknownAction, action)
+}
+
+// splitPath splits an escaped path on "/" and decodes each segment, dropping
+// empty ones. Callers join the segments with "/" again, so the split only
+// collapses duplicate slashes and catches invalid escapes.
+func splitPath(escaped string) ([]string, error) {
+	if escaped == "" {
+		return nil, nil
+	}
+	parts := strings.Split(escaped, "/")
+	segments := make([]string, 0, len(parts))
+	for _, p := range parts {
+		s, err := url.PathUnescape(p)
+		if err != nil {
+			return nil, err
+		}
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments, nil
+}
+
+// String formats the link back into its canonical URL form
+func (l Link) String() string {
+	switch l.Action {
+	case ActionLaunch:
+		return fmt.Sprintf("%s://launch?ref=%s", Scheme, url.QueryEscape(l.Ref))
+	case ActionGroup:
+		return fmt.Sprintf("%s://group/%s", Scheme, url.PathEscape(l.Group))
+	}
+	if l.Group != "" {
+		return fmt.Sprintf("%s://show?group=%s", Scheme, url.QueryEscape(l.Group))
+	}
+	return Scheme + "://show"
+}
+
+// Request maps the link onto the request understood by the running instance
+func (l Link) Request() ipc.Request {
+	switch l.Action {
+	case ActionLaunch:
+		return ipc.NewRequest(ipc.ActionLaunch, l.Ref)
+	case ActionGroup:
+		return ipc.NewRequest(ipc.ActionGroup, l.Group)
+	}
+	if l.Group != "" {
+		// 切换分组时会同时显示主窗口
+		return ipc.NewRequest(ipc.ActionGroup, l.Group)
+	}
+	return ipc.NewRequest(ipc.ActionShow)
+}
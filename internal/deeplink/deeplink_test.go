@@ -0,0 +1,109 @@
+package deeplink
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go-musetool/internal/ipc"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Link
+	}{
+		{"musetool://show", Link{Action: ActionShow}},
+		{"musetool://show/", Link{Action: ActionShow}},
+		{"MuseTool://SHOW", Link{Action: ActionShow}},
+		{"musetool://show?group=Games", Link{Action: ActionShow, Group: "Games"}},
+		{"musetool://group/Games", Link{Action: ActionGroup, Group: "Games"}},
+		{"musetool://group/My%20Games/", Link{Action: ActionGroup, Group: "My Games"}},
+		{"musetool://group?name=%E5%B7%A5%E5%85%B7", Link{Action: ActionGroup, Group: "工具"}},
+		{"musetool://group/Work/Tools", Link{Action: ActionGroup, Group: "Work/Tools"}},
+		{"musetool://group/Work%2FTools", Link{Action: ActionGroup, Group: "Work/Tools"}},
+		{"musetool://launch/Dev/Terminal", Link{Action: ActionLaunch, Ref: "Dev/Terminal"}},
+		{"musetool://launch//Dev//Terminal/", Link{Action: ActionLaunch, Ref: "Dev/Terminal"}},
+		{"musetool://launch/Dev/Visual%20Studio%20Code", Link{Action: ActionLaunch, Ref: "Dev/Visual Studio Code"}},
+		{"musetool://launch?ref=Dev/Terminal", Link{Action: ActionLaunch, Ref: "Dev/Terminal"}},
+		{"musetool://launch?group=Dev&name=Terminal", Link{Action: ActionLaunch, Ref: "Dev/Terminal"}},
+		{"musetool://launch?ref=Dev%2FTerminal", Link{Action: ActionLaunch, Ref: "Dev/Terminal"}},
+		{"musetool:launch/Dev/Terminal", Link{Action: ActionLaunch, Ref: "Dev/Terminal"}},
+		{"  musetool://show  ", Link{Action: ActionShow}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.raw)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want error
+	}{
+		{"https://example.com", ErrNotDeepLink},
+		{"C:\\Tools\\app.exe", ErrNotDeepLink},
+		{"musetool://explode", ErrUnknownAction},
+		{"musetool://group", ErrMissingTarget},
+		{"musetool://group/", ErrMissingTarget},
+		{"musetool://launch", ErrMissingTarget},
+		{"musetool://launch/Terminal", ErrInvalidLinkRef},
+		{"musetool://launch?ref=/Terminal", ErrInvalidLinkRef},
+		{"musetool://launch?group=Dev", ErrMissingTarget},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.raw)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.raw, err, tt.want)
+		}
+	}
+
+	if _, err := Parse("musetool://launch/Dev/%zz"); err == nil {
+		t.Error("Parse accepted an invalid escape")
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	links := []Link{
+		{Action: ActionShow},
+		{Action: ActionShow, Group: "My Games"},
+		{Action: ActionGroup, Group: "工具"},
+		{Action: ActionGroup, Group: "Work/Tools"},
+		{Action: ActionLaunch, Ref: "Dev/Visual Studio Code"},
+		{Action: ActionLaunch, Ref: "Work/Tools/a&b=c"},
+	}
+	for _, link := range links {
+		got, err := Parse(link.String())
+		if err != nil {
+			t.Errorf("Parse(%q): %v", link.String(), err)
+			continue
+		}
+		if got != link {
+			t.Errorf("Parse(%q) = %+v, want %+v", link.String(), got, link)
+		}
+	}
+}
+
+func TestRequest(t *testing.T) {
+	tests := []struct {
+		link Link
+		want ipc.Request
+	}{
+		{Link{Action: ActionShow}, ipc.NewRequest(ipc.ActionShow)},
+		{Link{Action: ActionShow, Group: "Games"}, ipc.NewRequest(ipc.ActionGroup, "Games")},
+		{Link{Action: ActionGroup, Group: "Games"}, ipc.NewRequest(ipc.ActionGroup, "Games")},
+		{Link{Action: ActionLaunch, Ref: "Dev/Terminal"}, ipc.NewRequest(ipc.ActionLaunch, "Dev/Terminal")},
+	}
+	for _, tt := range tests {
+		if got := tt.link.Request(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v.Request() = %+v, want %+v", tt.link, got, tt.want)
+		}
+	}
+}
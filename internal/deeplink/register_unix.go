@@ -0,0 +1,207 @@
+//go:build !windows
+
+package deeplink
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go-musetool/internal/desktopentry"
+)
+
+const (
+	desktopFileName = "gomusetool-url-handler.desktop"
+	mimeType        = "x-scheme-handler/" + Scheme
+	defaultsSection = "[Default Applications]"
+)
+
+func dataHome() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "share")
+}
+
+func configHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config")
+}
+
+func desktopFilePath() string {
+	return filepath.Join(dataHome(), "applications", desktopFileName)
+}
+
+func mimeAppsPath() string {
+	return filepath.Join(configHome(), "mimeapps.list")
+}
+
+// Register installs an x-scheme-handler .desktop entry for exePath and makes
+// it the default handler in mimeapps.list
+func Register(exePath string) error {
+	path := desktopFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	content := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Go MuseTool
+Exec=%s %%u
+NoDisplay=true
+Terminal=false
+MimeType=%s;
+`, desktopentry.QuoteExec([]string{exePath}), mimeType)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	if err := setMimeDefault(desktopFileName); err != nil {
+		return err
+	}
+	// 刷新桌面数据库缓存（工具不存在时忽略）
+	_ = exec.Command("update-desktop-database", filepath.Dir(path)).Run()
+
+	log.Printf("URL handler registered: %s", exePath)
+	return nil
+}
+
+// Unregister removes the .desktop entry and our mimeapps.list default. A
+// default that another application has taken over is left alone.
+func Unregister() error {
+	if err := os.Remove(desktopFilePath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := removeMimeDefault(desktopFileName); err != nil {
+		return err
+	}
+	log.Printf("URL handler unregistered")
+	return nil
+}
+
+// IsRegistered checks whether the .desktop entry is installed
+func IsRegistered() bool {
+	_, err := os.Stat(desktopFilePath())
+	return err == nil
+}
+
+// setMimeDefault makes desktop the only default handler for the scheme
+func setMimeDefault(desktop string) error {
+	return editMimeDefault(func([]string) []string { return []string{desktop} })
+}
+
+// removeMimeDefault drops desktop from the default handlers for the scheme,
+// keeping handlers set by other applications
+func removeMimeDefault(desktop string) error {
+	return editMimeDefault(func(handlers []string) []string {
+		var kept []string
+		for _, h := range handlers {
+			if h != desktop {
+				kept = append(kept, h)
+			}
+		}
+		return kept
+	})
+}
+
+// editMimeDefault replaces the scheme's handler list in the [Default
+// Applications] section of mimeapps.list with edit's result, removing the
+// entry when the result is empty. Every other line is left untouched and the
+// file is only written when something changed.
+func editMimeDefault(edit func(handlers []string) []string) error {
+	path := mimeAppsPath()
+	var lines []string
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	var out []string
+	inDefaults, done := false, false
+	// apply writes the edited entry in place of line, or at the end of the
+	// section before its trailing blank lines when line is empty
+	apply := func(line string) {
+		done = true
+		current := splitHandlers(strings.TrimPrefix(strings.TrimSpace(line), mimeType+"="))
+		handlers := edit(current)
+		if len(handlers) == 0 {
+			return
+		}
+		if line != "" && slices.Equal(handlers, current) {
+			out = append(out, line)
+			return
+		}
+		entry := mimeType + "=" + strings.Join(handlers, ";") + ";"
+		if line != "" {
+			out = append(out, entry)
+			return
+		}
+		i := len(out)
+		for i > 0 && strings.TrimSpace(out[i-1]) == "" {
+			i--
+		}
+		out = slices.Insert(out, i, entry)
+	}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			// 离开 [Default Applications] 时补写尚未写入的条目
+			if inDefaults && !done {
+				apply("")
+			}
+			inDefaults = trimmed == defaultsSection
+		} else if inDefaults && strings.HasPrefix(trimmed, mimeType+"=") {
+			// 重复的条目只保留第一条
+			if !done {
+				apply(line)
+			}
+			continue
+		}
+		out = append(out, line)
+	}
+	if !done && inDefaults {
+		apply("")
+	} else if !done {
+		// 没有 [Default Applications] 段时只在需要写入条目时添加
+		n := len(out)
+		out = append(out, defaultsSection)
+		if apply(""); len(out) == n+1 {
+			out = out[:n]
+		}
+	}
+	if slices.Equal(out, lines) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(out, "\n")+"\n"), 0644)
+}
+
+// splitHandlers splits a mimeapps.list value such as "a.desktop;b.desktop;"
+func splitHandlers(value string) []string {
+	var handlers []string
+	for _, h := range strings.Split(value, ";") {
+		if h = strings.TrimSpace(h); h != "" {
+			handlers = append(handlers, h)
+		}
+	}
+	return handlers
+}
@@ -0,0 +1,115 @@
+//go:build !windows
+
+package deeplink
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useTempXDG points the XDG directories at a temporary directory and returns
+// the path of mimeapps.list
+func useTempXDG(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	return filepath.Join(dir, "config", "mimeapps.list")
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRegisterAndUnregister(t *testing.T) {
+	mimeApps := useTempXDG(t)
+	const others = "[Default Applications]\ntext/html=firefox.desktop\n\n[Added Associations]\nimage/png=gimp.desktop;\n"
+	os.MkdirAll(filepath.Dir(mimeApps), 0755)
+	os.WriteFile(mimeApps, []byte(others), 0644)
+
+	if err := Register("/opt/Go MuseTool/musetool"); err != nil {
+		t.Fatal(err)
+	}
+	if !IsRegistered() {
+		t.Error("IsRegistered = false after Register")
+	}
+	desktop := readFile(t, desktopFilePath())
+	if !strings.Contains(desktop, `Exec="/opt/Go MuseTool/musetool" %u`) || !strings.Contains(desktop, "MimeType="+mimeType+";") {
+		t.Errorf(".desktop file:\n%s", desktop)
+	}
+	want := "[Default Applications]\ntext/html=firefox.desktop\n" + mimeType + "=" + desktopFileName + ";\n\n[Added Associations]\nimage/png=gimp.desktop;\n"
+	if got := readFile(t, mimeApps); got != want {
+		t.Errorf("mimeapps.list after Register:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := Unregister(); err != nil {
+		t.Fatal(err)
+	}
+	if IsRegistered() {
+		t.Error("IsRegistered = true after Unregister")
+	}
+	if got := readFile(t, mimeApps); got != others {
+		t.Errorf("mimeapps.list after Unregister:\n%s\nwant:\n%s", got, others)
+	}
+}
+
+func TestUnregisterKeepsOtherHandlers(t *testing.T) {
+	tests := []struct {
+		name, before, after string
+	}{
+		{
+			"another application",
+			"[Default Applications]\n" + mimeType + "=other.desktop\n",
+			"[Default Applications]\n" + mimeType + "=other.desktop\n",
+		},
+		{
+			"shared entry",
+			"[Default Applications]\n" + mimeType + "=" + desktopFileName + ";other.desktop;\n",
+			"[Default Applications]\n" + mimeType + "=other.desktop;\n",
+		},
+		{
+			"no entry",
+			"[Default Applications]\ntext/html=firefox.desktop\n",
+			"[Default Applications]\ntext/html=firefox.desktop\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mimeApps := useTempXDG(t)
+			os.MkdirAll(filepath.Dir(mimeApps), 0755)
+			os.WriteFile(mimeApps, []byte(tt.before), 0644)
+			if err := Unregister(); err != nil {
+				t.Fatal(err)
+			}
+			if got := readFile(t, mimeApps); got != tt.after {
+				t.Errorf("mimeapps.list:\n%s\nwant:\n%s", got, tt.after)
+			}
+		})
+	}
+}
+
+func TestRegisterCreatesMimeApps(t *testing.T) {
+	mimeApps := useTempXDG(t)
+	if err := Register("/usr/bin/musetool"); err != nil {
+		t.Fatal(err)
+	}
+	want := "[Default Applications]\n" + mimeType + "=" + desktopFileName + ";\n"
+	if got := readFile(t, mimeApps); got != want {
+		t.Errorf("mimeapps.list:\n%s\nwant:\n%s", got, want)
+	}
+
+	// 未注册时注销不应创建文件
+	os.Remove(mimeApps)
+	if err := Unregister(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(mimeApps); !os.IsNotExist(err) {
+		t.Errorf("Unregister created mimeapps.list: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package deeplink
+
+import (
+	"fmt"
+	"log"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// classesKeyPath is the per-user protocol registration; no elevation needed
+const classesKeyPath = `Software\Classes\` + Scheme
+
+// Register associates musetool:// with exePath for the current user
+func Register(exePath string) error {
+	k, _, err := registry.CreateKey(registry.CURRENT_USER, classesKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+
+	if err := k.SetStringValue("", "URL:Go MuseTool Protocol"); err != nil {
+		return err
+	}
+	// 空的 "URL Protocol" 值告诉 Shell 这是一个 URL 协议
+	if err := k.SetStringValue("URL Protocol", ""); err != nil {
+		return err
+	}
+
+	iconKey, _, err := registry.CreateKey(k, "DefaultIcon", registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer iconKey.Close()
+	if err := iconKey.SetStringValue("", fmt.Sprintf(`"%s",0`, exePath)); err != nil {
+		return err
+	}
+
+	cmdKey, _, err := registry.CreateKey(k, `shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer cmdKey.Close()
+	if err := cmdKey.SetStringValue("", fmt.Sprintf(`"%s" "%%1"`, exePath)); err != nil {
+		return err
+	}
+
+	log.Printf("URL handler registered: %s", exePath)
+	return nil
+}
+
+// Unregister removes the musetool:// association
+func Unregister() error {
+	// DeleteKey 不能删除含子键的键，按从深到浅的顺序删除
+	for _, path := range []string{
+		classesKeyPath + `\shell\open\command`,
+		classesKeyPath + `\shell\open`,
+		classesKeyPath + `\shell`,
+		classesKeyPath + `\DefaultIcon`,
+		classesKeyPath,
+	} {
+		if err := registry.DeleteKey(registry.CURRENT_USER, path); err != nil && err != registry.ErrNotExist {
+			return err
+		}
+	}
+	log.Printf("URL handler unregistered")
+	return nil
+}
+
+// IsRegistered checks whether musetool:// is currently associated
+func IsRegistered() bool {
+	k, err := registry.OpenKey(registry.CURRENT_USER, classesKeyPath+`\shell\open\command`, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer k.Close()
+
+	_, _, err = k.GetStringValue("")
+	return err == nil
+}
@@ -0,0 +1,87 @@
+// Package desktopentry quotes and parses the Exec key of freedesktop Desktop
+// Entry files (.desktop), as used by autostart entries, URL handlers and
+// application launchers.
+package desktopentry
+
+import "strings"
+
+// QuoteExec builds an Exec value from args: every argument is double-quoted
+// with ", `, $ and \ escaped, then the string-value escaping doubles the
+// backslashes again and % becomes %% so it is not read as a field code.
+// Field codes such as %u are appended by the caller after quoting.
+func QuoteExec(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		arg = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`).Replace(arg)
+		quoted[i] = `"` + arg + `"`
+	}
+	line := strings.Join(quoted, " ")
+	line = strings.ReplaceAll(line, `\`, `\\`)
+	return strings.ReplaceAll(line, "%", "%%")
+}
+
+// ParseExec splits an Exec value into arguments, undoing QuoteExec and
+// dropping field codes such as %u
+func ParseExec(value string) []string {
+	value = unescapeString(value)
+
+	var args []string
+	var arg strings.Builder
+	hasArg, inQuotes := false, false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case inQuotes && c == '\\' && i+1 < len(value):
+			i++
+			arg.WriteByte(value[i])
+		case c == '"':
+			inQuotes = !inQuotes
+			hasArg = true
+		case !inQuotes && (c == ' ' || c == '\t'):
+			if hasArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				hasArg = false
+			}
+		case c == '%' && i+1 < len(value):
+			i++
+			if value[i] == '%' {
+				arg.WriteByte('%')
+				hasArg = true
+			}
+			// 其它字段代码（%u %f ...）启动时才会替换，这里忽略
+		default:
+			arg.WriteByte(c)
+			hasArg = true
+		}
+	}
+	if hasArg {
+		args = append(args, arg.String())
+	}
+	return args
+}
+
+// unescapeString undoes the string-value escaping (\\ \s \n \t \r)
+func unescapeString(value string) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+			switch value[i] {
+			case 's':
+				sb.WriteByte(' ')
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			default:
+				sb.WriteByte(value[i])
+			}
+			continue
+		}
+		sb.WriteByte(value[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,66 @@
+package desktopentry
+
+import (
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestQuoteExecRoundTrip(t *testing.T) {
+	tests := [][]string{
+		{"/usr/bin/musetool"},
+		{"/opt/Go MuseTool/musetool", "--tray"},
+		{`/home/me/bin/a"b`, `back\slash`, `two\\slashes`, "$HOME", "`id`", "50%", "%u", ""},
+	}
+	for _, args := range tests {
+		line := QuoteExec(args)
+		if got := ParseExec(line); !reflect.DeepEqual(got, args) {
+			t.Errorf("ParseExec(QuoteExec(%q)) = %q (line %s)", args, got, line)
+		}
+	}
+}
+
+func TestParseExec(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{`musetool %u --tray`, []string{"musetool", "--tray"}}, // 字段代码不是参数
+		{`firefox %U`, []string{"firefox"}},
+		{`"/opt/My App/app" --name "a b"`, []string{"/opt/My App/app", "--name", "a b"}},
+		{`printf "100%%"`, []string{"printf", "100%"}},
+		{`sh -c "echo \\"hi\\""`, []string{"sh", "-c", `echo "hi"`}},
+		{`"C:\\\\Tools\\\\app"`, []string{`C:\Tools\app`}},
+		{`"app\sname"`, []string{"app name"}}, // \s 是字符串值中的空格
+		{`  app   --flag  `, []string{"app", "--flag"}},
+		{``, nil},
+	}
+	for _, tt := range tests {
+		if got := ParseExec(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseExec(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// TestQuoteExecShell checks the Exec quoting against a real shell, which
+// follows the same quoting rules once the string-value escaping is undone
+func TestQuoteExecShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no POSIX shell")
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	args := []string{"printf", `%s\n`, `a"b`, `c\d`, "$HOME", "`id`"}
+	line := strings.ReplaceAll(unescapeString(QuoteExec(args)), "%%", "%")
+	out, err := exec.Command(sh, "-c", line).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Join(args[2:], "\n") + "\n"; string(out) != want {
+		t.Errorf("sh printed %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,369 @@
+// Package dock decides where a launcher window docked to a screen edge
+// belongs: flush against the edge, hidden behind it, or back on screen when
+// the cursor reaches the edge. It works on plain rectangles and has no
+// window-system dependencies; the UI feeds it the window rect, the work area
+// and the cursor position and moves the window to the positions it returns.
+package dock
+
+import (
+	"strings"
+	"time"
+)
+
+// Edge is the screen edge a window is docked to
+type Edge int
+
+const (
+	None Edge = iota
+	Top
+	Bottom
+	Left
+	Right
+)
+
+var edgeNames = [...]string{None: "none", Top: "top", Bottom: "bottom", Left: "left", Right: "right"}
+
+func (e Edge) String() string {
+	if e < None || e > Right {
+		return "unknown"
+	}
+	return edgeNames[e]
+}
+
+// Edges is a set of edges
+type Edges uint8
+
+// AllEdges allows docking to every edge
+const AllEdges Edges = 1<<Top | 1<<Bottom | 1<<Left | 1<<Right
+
+// Has reports whether e is in the set
+func (s Edges) Has(e Edge) bool {
+	return e != None && s&(1<<e) != 0
+}
+
+// ParseEdges reads edge names ("top", "bottom", "left", "right"); unknown
+// names are ignored
+func ParseEdges(names []string) Edges {
+	var s Edges
+	for _, name := range names {
+		for e := Top; e <= Right; e++ {
+			if strings.EqualFold(strings.TrimSpace(name), edgeNames[e]) {
+				s |= 1 << e
+			}
+		}
+	}
+	return s
+}
+
+// Names returns the edge names in the set, in the order top, bottom, left, right
+func (s Edges) Names() []string {
+	var names []string
+	for e := Top; e <= Right; e++ {
+		if s.Has(e) {
+			names = append(names, edgeNames[e])
+		}
+	}
+	return names
+}
+
+const (
+	// SnapDistance 窗口与工作区边缘的距离小于该值时停靠到该边缘
+	SnapDistance = 100
+	// CornerSnapDistance 停靠在左右边缘时，窗口底部离工作区底部小于该值则贴到底部
+	CornerSnapDistance = 80
+	// VisualAdjustment 视觉校正偏移量 (用于消除 Windows 窗口阴影带来的视觉间隙)
+	VisualAdjustment = 8
+	// hideOffset 隐藏时额外移出的距离，足够大以确保阴影也完全不可见
+	hideOffset = 300
+	// revealTolerance 左右停靠时，鼠标在窗口上下各该范围内也能唤出窗口
+	revealTolerance = 50
+
+	// BottomCenterZone 定义底部角落区域的大小（单位：像素）
+	// 用于区分底部的"角落区域"和"中间区域"，以解决任务栏预览窗口导致的闪烁问题
+	//
+	// 工作原理：
+	//   - 左下角：窗口左边缘距离屏幕左边缘 < BottomCenterZone
+	//   - 右下角：窗口右边缘距离屏幕右边缘 < BottomCenterZone
+	//   - 中间区域：不在左下角或右下角的底部区域
+	//
+	// 中间区域的隐藏延迟至少为 TaskbarHideDelay（任务栏图标密集，避免预览窗口干扰），
+	// 角落区域使用设置中的隐藏延迟。
+	BottomCenterZone = 200
+	TaskbarHideDelay = 300 * time.Millisecond
+)
+
+// Settings control docking. The zero value disables it; DefaultSettings
+// returns the behavior of earlier versions.
+type Settings struct {
+	Edges         Edges         // 允许停靠的边缘，为空表示不停靠
+	AutoHide      bool          // 鼠标离开后隐藏到边缘外
+	HideDelay     time.Duration // 鼠标离开到隐藏的延迟
+	ShowDelay     time.Duration // 鼠标停在热区多久后显示
+	RevealZone    int           // 边缘热区的宽度（像素）
+	RevealOnHover bool          // false 时隐藏的窗口只能通过热键或托盘显示
+}
+
+// Defaults used for settings that are not configured
+const (
+	DefaultHideDelay  = 50 * time.Millisecond
+	DefaultRevealZone = 1
+)
+
+// DefaultSettings docks to every edge, hides 50ms after the cursor leaves and
+// shows again when the cursor touches the edge
+func DefaultSettings() Settings {
+	return Settings{
+		Edges:         AllEdges,
+		AutoHide:      true,
+		HideDelay:     DefaultHideDelay,
+		RevealZone:    DefaultRevealZone,
+		RevealOnHover: true,
+	}
+}
+
+// Point is a screen position
+type Point struct {
+	X, Y int
+}
+
+// Rect is a screen rectangle
+type Rect struct {
+	X, Y, W, H int
+}
+
+// WorkArea builds a Rect from the left, top, right and bottom coordinates
+func WorkArea(left, top, right, bottom int) Rect {
+	return Rect{X: left, Y: top, W: right - left, H: bottom - top}
+}
+
+func (r Rect) Right() int  { return r.X + r.W }
+func (r Rect) Bottom() int { return r.Y + r.H }
+
+// Contains reports whether p lies in r, including its border
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.X && p.X <= r.Right() && p.Y >= r.Y && p.Y <= r.Bottom()
+}
+
+// Machine is the dock state of one window. It starts undocked; Moved docks
+// it when the user drags it near an allowed edge, Hide hides it behind that
+// edge and Show brings it back. Machine is not safe for concurrent use.
+type Machine struct {
+	settings Settings
+	edge     Edge
+	hidden   bool
+}
+
+// NewMachine returns an undocked machine
+func NewMachine(settings Settings) *Machine {
+	return &Machine{settings: settings}
+}
+
+// Settings returns the current settings
+func (m *Machine) Settings() Settings {
+	return m.settings
+}
+
+// SetSettings replaces the settings. A window docked to an edge that is no
+// longer allowed is undocked; call Show first if it is hidden.
+func (m *Machine) SetSettings(settings Settings) {
+	m.settings = settings
+	if !settings.Edges.Has(m.edge) {
+		m.Undock()
+	}
+}
+
+// Edge returns the edge the window is docked to
+func (m *Machine) Edge() Edge {
+	return m.edge
+}
+
+// Hidden reports whether the window is hidden behind its edge
+func (m *Machine) Hidden() bool {
+	return m.hidden
+}
+
+// AutoHides reports whether the window is docked, visible and hides when the
+// cursor leaves it
+func (m *Machine) AutoHides() bool {
+	return m.edge != None && !m.hidden && m.settings.AutoHide
+}
+
+// Undock forgets the edge, e.g. when the window is maximized
+func (m *Machine) Undock() {
+	m.edge = None
+	m.hidden = false
+}
+
+// Moved records that the user moved or resized the window and docks it to
+// the allowed edge it is near (left and right before top and bottom, so tall
+// windows dock to the sides). It returns the new edge.
+func (m *Machine) Moved(window, workArea Rect) Edge {
+	m.hidden = false
+	m.edge = m.nearestEdge(window, workArea, SnapDistance)
+	return m.edge
+}
+
+// nearestEdge returns the first allowed edge within dist of the window
+func (m *Machine) nearestEdge(window, workArea Rect, dist int) Edge {
+	allowed := m.settings.Edges
+	switch {
+	case allowed.Has(Left) && window.X < workArea.X+dist:
+		return Left
+	case allowed.Has(Right) && window.Right() > workArea.Right()-dist:
+		return Right
+	case allowed.Has(Top) && window.Y < workArea.Y+dist:
+		return Top
+	case allowed.Has(Bottom) && window.Bottom() > workArea.Bottom()-dist:
+		return Bottom
+	}
+	return None
+}
+
+// Settle returns where a visible window that stopped moving belongs: flush
+// against its edge when docked (and in the bottom corner when close to it),
+// inside the work area otherwise. ok is false when it is already there.
+func (m *Machine) Settle(window, workArea Rect) (target Point, ok bool) {
+	if m.hidden {
+		return Point{}, false
+	}
+	target = Point{window.X, window.Y}
+	bottom := workArea.Bottom() - window.H + VisualAdjustment
+	switch m.edge {
+	case Left:
+		target.X = workArea.X - VisualAdjustment
+		if window.Bottom() >= workArea.Bottom()-CornerSnapDistance {
+			target.Y = bottom
+		}
+	case Right:
+		// 已经移出右边缘的窗口（隐藏过程中）不拉回
+		if window.X < workArea.Right() {
+			target.X = workArea.Right() - window.W + VisualAdjustment
+		}
+		if window.Bottom() >= workArea.Bottom()-CornerSnapDistance {
+			target.Y = bottom
+		}
+	case Top:
+		target.Y = workArea.Y - VisualAdjustment
+	case Bottom:
+		target.Y = bottom
+	default:
+		target = clamp(window, workArea)
+	}
+	return target, target != Point{window.X, window.Y}
+}
+
+// clamp keeps an undocked window inside the work area
+func clamp(window, workArea Rect) Point {
+	p := Point{window.X, window.Y}
+	if window.Y < workArea.Y {
+		p.Y = workArea.Y
+	}
+	if window.Bottom() > workArea.Bottom() {
+		p.Y = workArea.Bottom() - window.H
+	}
+	if window.X < workArea.X {
+		p.X = workArea.X
+	}
+	if window.Right() > workArea.Right() {
+		p.X = workArea.Right() - window.W
+	}
+	return p
+}
+
+// HideDelay returns how long to wait after the cursor left the window before
+// hiding it. Windows docked in the middle of the bottom edge wait at least
+// TaskbarHideDelay, so that taskbar previews do not make them flicker.
+func (m *Machine) HideDelay(window, workArea Rect) time.Duration {
+	delay := m.settings.HideDelay
+	if m.edge == Bottom && delay < TaskbarHideDelay {
+		leftCorner := window.X < workArea.X+BottomCenterZone
+		rightCorner := window.Right() > workArea.Right()-BottomCenterZone
+		if !leftCorner && !rightCorner {
+			delay = TaskbarHideDelay
+		}
+	}
+	return delay
+}
+
+// Hide returns the position that hides the window behind its edge. ok is
+// false when the window stays: auto-hide is off, it is not docked, or the
+// cursor is over it. A window that was never moved docks to the allowed
+// edge it touches.
+func (m *Machine) Hide(window, workArea Rect, cursor Point) (target Point, ok bool) {
+	if !m.settings.AutoHide || m.hidden || window.Contains(cursor) {
+		return Point{}, false
+	}
+	if m.edge == None {
+		m.edge = m.nearestEdge(window, workArea, 1)
+	}
+	switch m.edge {
+	case Top:
+		target = Point{window.X, workArea.Y - window.H - hideOffset}
+	case Bottom:
+		target = Point{window.X, workArea.Bottom() + hideOffset}
+	case Left:
+		target = Point{workArea.X - window.W - hideOffset, window.Y}
+	case Right:
+		target = Point{workArea.Right() + hideOffset, window.Y}
+	default:
+		return Point{}, false
+	}
+	m.hidden = true
+	return target, true
+}
+
+// InRevealZone reports whether the cursor is in the hot zone of the hidden
+// window: within RevealZone pixels of its edge and, for the left and right
+// edges, level with the window. It is always false when the window is only
+// revealed by hotkey.
+func (m *Machine) InRevealZone(window, workArea Rect, cursor Point) bool {
+	if !m.hidden || !m.settings.RevealOnHover {
+		return false
+	}
+	zone := m.settings.RevealZone
+	if zone < 1 {
+		zone = 1
+	}
+	level := cursor.Y >= window.Y-revealTolerance && cursor.Y <= window.Bottom()+revealTolerance
+	switch m.edge {
+	case Top:
+		return cursor.Y <= workArea.Y+zone
+	case Bottom:
+		return cursor.Y >= workArea.Bottom()-zone
+	case Left:
+		return cursor.X <= workArea.X+zone && level
+	case Right:
+		return cursor.X >= workArea.Right()-zone && level
+	}
+	return false
+}
+
+// Show returns the visible position of the window: flush against its edge
+// when docked, moved back into the work area when it is partly outside. ok
+// is false when it does not need to move.
+func (m *Machine) Show(window, workArea Rect) (target Point, ok bool) {
+	m.hidden = false
+	target = Point{window.X, window.Y}
+	switch m.edge {
+	case Top:
+		target.Y = workArea.Y - VisualAdjustment
+	case Bottom:
+		target.Y = workArea.Bottom() - window.H + VisualAdjustment
+	case Left:
+		target.X = workArea.X - VisualAdjustment
+	case Right:
+		target.X = workArea.Right() - window.W + VisualAdjustment
+	default:
+		switch {
+		case window.Y < workArea.Y:
+			target.Y = workArea.Y
+		case window.Bottom() > workArea.Bottom():
+			target.Y = workArea.Bottom() - window.H
+		case window.X < workArea.X:
+			target.X = workArea.X - VisualAdjustment
+		case window.Right() > workArea.Right():
+			target.X = workArea.Right() - window.W + VisualAdjustment
+		}
+	}
+	return target, target != Point{window.X, window.Y}
+}
@@ -0,0 +1,340 @@
+package dock
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 1920x1080 屏幕，底部任务栏 40 像素；窗口 400x600
+var (
+	workArea = WorkArea(0, 0, 1920, 1040)
+	winW     = 400
+	winH     = 600
+)
+
+func window(x, y int) Rect {
+	return Rect{X: x, Y: y, W: winW, H: winH}
+}
+
+// docked returns a machine docked to edge with the default settings
+func docked(edge Edge) *Machine {
+	m := NewMachine(DefaultSettings())
+	m.edge = edge
+	return m
+}
+
+func TestParseEdges(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  Edges
+	}{
+		{nil, 0},
+		{[]string{"top", "bottom", "left", "right"}, AllEdges},
+		{[]string{" Left ", "RIGHT", "left"}, 1<<Left | 1<<Right},
+		{[]string{"middle", "bottom"}, 1 << Bottom},
+	}
+	for _, tt := range tests {
+		got := ParseEdges(tt.names)
+		if got != tt.want {
+			t.Errorf("ParseEdges(%q) = %b, want %b", tt.names, got, tt.want)
+		}
+		if back := ParseEdges(got.Names()); back != got {
+			t.Errorf("ParseEdges(%q.Names()) = %b", tt.names, back)
+		}
+	}
+	if got := AllEdges.Names(); !reflect.DeepEqual(got, []string{"top", "bottom", "left", "right"}) {
+		t.Errorf("AllEdges.Names() = %q", got)
+	}
+	if AllEdges.Has(None) {
+		t.Error("AllEdges.Has(None)")
+	}
+}
+
+func TestMoved(t *testing.T) {
+	tests := []struct {
+		name   string
+		edges  Edges
+		window Rect
+		area   Rect
+		want   Edge
+	}{
+		{"left", AllEdges, window(50, 200), workArea, Left},
+		{"right", AllEdges, window(1470, 200), workArea, Right},
+		{"top", AllEdges, window(700, 30), workArea, Top},
+		{"bottom", AllEdges, window(700, 390), workArea, Bottom},
+		{"middle", AllEdges, window(700, 200), workArea, None},
+		{"just outside snap distance", AllEdges, window(SnapDistance, 200), workArea, None},
+		{"top-left corner prefers the side", AllEdges, window(10, 10), workArea, Left},
+		{"bottom-right corner prefers the side", AllEdges, window(1500, 430), workArea, Right},
+		{"left disabled", 1<<Top | 1<<Bottom | 1<<Right, window(10, 10), workArea, Top},
+		{"only bottom", 1 << Bottom, window(10, 10), workArea, None},
+		{"docking disabled", 0, window(10, 10), workArea, None},
+		{"second monitor", AllEdges, window(1930, 200), WorkArea(1920, 0, 3840, 1080), Left},
+		{"second monitor, right of first", AllEdges, window(1930, 200), workArea, Right},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMachine(Settings{Edges: tt.edges, AutoHide: true})
+			m.hidden = true
+			if got := m.Moved(tt.window, tt.area); got != tt.want || m.Edge() != tt.want {
+				t.Errorf("Moved = %v, want %v", got, tt.want)
+			}
+			if m.Hidden() {
+				t.Error("Moved left the window hidden")
+			}
+		})
+	}
+}
+
+func TestSettle(t *testing.T) {
+	bottomY := workArea.Bottom() - winH + VisualAdjustment
+	tests := []struct {
+		name   string
+		edge   Edge
+		window Rect
+		want   Point
+		ok     bool
+	}{
+		{"left", Left, window(50, 200), Point{-VisualAdjustment, 200}, true},
+		{"left, bottom corner", Left, window(50, 400), Point{-VisualAdjustment, bottomY}, true},
+		{"right", Right, window(1470, 200), Point{1920 - winW + VisualAdjustment, 200}, true},
+		{"right, bottom corner", Right, window(1470, 380), Point{1920 - winW + VisualAdjustment, bottomY}, true},
+		{"right, moving out while hiding", Right, window(1925, 200), Point{1925, 200}, false},
+		{"top", Top, window(700, 30), Point{700, -VisualAdjustment}, true},
+		{"bottom", Bottom, window(700, 390), Point{700, bottomY}, true},
+		{"already flush", Top, window(700, -VisualAdjustment), Point{700, -VisualAdjustment}, false},
+		{"undocked inside", None, window(700, 200), Point{700, 200}, false},
+		{"undocked above", None, window(700, -20), Point{700, 0}, true},
+		{"undocked below", None, window(700, 500), Point{700, 1040 - winH}, true},
+		{"undocked past the right", None, window(1700, 200), Point{1920 - winW, 200}, true},
+		{"undocked past the left", None, window(-30, 200), Point{0, 200}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := docked(tt.edge).Settle(tt.window, workArea)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Settle = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	m := docked(Left)
+	m.hidden = true
+	if _, ok := m.Settle(window(-700, 200), workArea); ok {
+		t.Error("Settle moved a hidden window")
+	}
+}
+
+func TestHideDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		edge   Edge
+		delay  time.Duration
+		window Rect
+		want   time.Duration
+	}{
+		{"bottom middle", Bottom, DefaultHideDelay, window(700, 448), TaskbarHideDelay},
+		{"bottom left corner", Bottom, DefaultHideDelay, window(BottomCenterZone-1, 448), DefaultHideDelay},
+		{"bottom right corner", Bottom, DefaultHideDelay, window(1920-BottomCenterZone-winW+1, 448), DefaultHideDelay},
+		{"bottom middle, longer setting", Bottom, time.Second, window(700, 448), time.Second},
+		{"top middle", Top, DefaultHideDelay, window(700, -8), DefaultHideDelay},
+		{"left", Left, 0, window(-8, 200), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMachine(Settings{Edges: AllEdges, AutoHide: true, HideDelay: tt.delay})
+			m.edge = tt.edge
+			if got := m.HideDelay(tt.window, workArea); got != tt.want {
+				t.Errorf("HideDelay = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHide(t *testing.T) {
+	away := Point{1800, 900}
+	tests := []struct {
+		name     string
+		edge     Edge
+		window   Rect
+		cursor   Point
+		want     Point
+		ok       bool
+		wantEdge Edge
+	}{
+		{"top", Top, window(700, -8), away, Point{700, -winH - hideOffset}, true, Top},
+		{"bottom", Bottom, window(700, 448), Point{960, 100}, Point{700, 1040 + hideOffset}, true, Bottom},
+		{"left", Left, window(-8, 200), away, Point{-winW - hideOffset, 200}, true, Left},
+		{"right", Right, window(1528, 200), away, Point{1920 + hideOffset, 200}, true, Right},
+		{"cursor over the window", Left, window(-8, 200), Point{100, 300}, Point{}, false, Left},
+		{"cursor on the border", Left, window(-8, 200), Point{392, 800}, Point{}, false, Left},
+		{"undocked", None, window(700, 200), Point{10, 10}, Point{}, false, None},
+		{"never moved, touching the left", None, window(0, 200), away, Point{-winW - hideOffset, 200}, true, Left},
+		{"never moved, touching the top", None, window(700, 0), away, Point{700, -winH - hideOffset}, true, Top},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := docked(tt.edge)
+			got, ok := m.Hide(tt.window, workArea, tt.cursor)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Hide = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+			if m.Hidden() != tt.ok || m.Edge() != tt.wantEdge {
+				t.Errorf("after Hide: hidden %v, edge %v, want %v, %v", m.Hidden(), m.Edge(), tt.ok, tt.wantEdge)
+			}
+		})
+	}
+}
+
+func TestHideDisabled(t *testing.T) {
+	settings := DefaultSettings()
+	settings.AutoHide = false
+	m := NewMachine(settings)
+	m.Moved(window(50, 200), workArea)
+	if m.AutoHides() {
+		t.Error("AutoHides with AutoHide off")
+	}
+	if _, ok := m.Hide(window(-8, 200), workArea, Point{960, 540}); ok || m.Hidden() {
+		t.Error("Hide hid the window with AutoHide off")
+	}
+
+	// 没有允许的边缘时，贴边的窗口也不会隐藏
+	m = NewMachine(Settings{AutoHide: true})
+	if _, ok := m.Hide(window(0, 0), workArea, Point{960, 540}); ok {
+		t.Error("Hide hid the window with docking disabled")
+	}
+
+	// 已隐藏的窗口不再移动
+	m = docked(Left)
+	m.Hide(window(-8, 200), workArea, Point{960, 540})
+	if _, ok := m.Hide(window(-700, 200), workArea, Point{960, 540}); ok {
+		t.Error("Hide moved a hidden window again")
+	}
+}
+
+func TestInRevealZone(t *testing.T) {
+	tests := []struct {
+		name   string
+		edge   Edge
+		zone   int
+		cursor Point
+		want   bool
+	}{
+		{"top edge", Top, 1, Point{100, 0}, true},
+		{"top within zone", Top, 1, Point{100, 1}, true},
+		{"top outside zone", Top, 1, Point{100, 2}, false},
+		{"bottom edge", Bottom, 1, Point{1800, 1040}, true},
+		{"bottom outside zone", Bottom, 1, Point{1800, 1038}, false},
+		{"left level with the window", Left, 1, Point{0, 500}, true},
+		{"left within tolerance above", Left, 1, Point{0, 200 - revealTolerance}, true},
+		{"left too far above", Left, 1, Point{0, 200 - revealTolerance - 1}, false},
+		{"left too far below", Left, 1, Point{0, 800 + revealTolerance + 1}, false},
+		{"left away from the edge", Left, 1, Point{5, 500}, false},
+		{"right edge", Right, 1, Point{1920, 500}, true},
+		{"right outside zone", Right, 1, Point{1918, 500}, false},
+		{"wider zone", Left, 10, Point{10, 500}, true},
+		{"zero zone is one pixel", Left, 0, Point{1, 500}, true},
+		{"zero zone outside", Left, 0, Point{2, 500}, false},
+		{"top-left corner hides on the left", Left, 1, Point{0, 0}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultSettings()
+			settings.RevealZone = tt.zone
+			m := NewMachine(settings)
+			m.edge = tt.edge
+			m.hidden = true
+			// 隐藏时窗口的位置：左右边缘保持原来的 Y
+			if got := m.InRevealZone(window(-700, 200), workArea, tt.cursor); got != tt.want {
+				t.Errorf("InRevealZone(%v) = %v, want %v", tt.cursor, got, tt.want)
+			}
+		})
+	}
+
+	m := docked(Top)
+	if m.InRevealZone(window(700, -8), workArea, Point{100, 0}) {
+		t.Error("InRevealZone is true for a visible window")
+	}
+	settings := DefaultSettings()
+	settings.RevealOnHover = false
+	m = NewMachine(settings)
+	m.edge, m.hidden = Top, true
+	if m.InRevealZone(window(700, -900), workArea, Point{100, 0}) {
+		t.Error("InRevealZone is true with RevealOnHover off")
+	}
+}
+
+func TestShow(t *testing.T) {
+	tests := []struct {
+		name   string
+		edge   Edge
+		window Rect
+		want   Point
+		ok     bool
+	}{
+		{"top", Top, window(700, -winH-hideOffset), Point{700, -VisualAdjustment}, true},
+		{"bottom", Bottom, window(700, 1040+hideOffset), Point{700, 1040 - winH + VisualAdjustment}, true},
+		{"left", Left, window(-winW-hideOffset, 200), Point{-VisualAdjustment, 200}, true},
+		{"right", Right, window(1920+hideOffset, 200), Point{1920 - winW + VisualAdjustment, 200}, true},
+		{"already shown", Left, window(-VisualAdjustment, 200), Point{-VisualAdjustment, 200}, false},
+		{"undocked above", None, window(700, -50), Point{700, 0}, true},
+		{"undocked below", None, window(700, 600), Point{700, 1040 - winH}, true},
+		{"undocked past the left", None, window(-50, 200), Point{-VisualAdjustment, 200}, true},
+		{"undocked past the right", None, window(1700, 200), Point{1920 - winW + VisualAdjustment, 200}, true},
+		{"undocked inside", None, window(700, 200), Point{700, 200}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := docked(tt.edge)
+			m.hidden = tt.edge != None
+			got, ok := m.Show(tt.window, workArea)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Show = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+			if m.Hidden() {
+				t.Error("Show left the window hidden")
+			}
+		})
+	}
+}
+
+// TestHideShowCycle runs the sequence the UI goes through for each edge
+func TestHideShowCycle(t *testing.T) {
+	for _, start := range []Rect{window(50, 200), window(1470, 200), window(700, 30), window(700, 390)} {
+		m := NewMachine(DefaultSettings())
+		edge := m.Moved(start, workArea)
+		settled, _ := m.Settle(start, workArea)
+		visible := window(settled.X, settled.Y)
+		if !m.AutoHides() {
+			t.Fatalf("%v: not auto-hiding after docking", edge)
+		}
+
+		hiddenAt, ok := m.Hide(visible, workArea, Point{1800, 900})
+		if !ok {
+			t.Fatalf("%v: Hide did nothing", edge)
+		}
+		hidden := window(hiddenAt.X, hiddenAt.Y)
+		if hidden.X+hidden.W > workArea.X && hidden.X < workArea.Right() &&
+			hidden.Y+hidden.H > workArea.Y && hidden.Y < workArea.Bottom() {
+			t.Errorf("%v: hidden window %v overlaps the work area", edge, hidden)
+		}
+
+		shownAt, _ := m.Show(hidden, workArea)
+		if shownAt != settled {
+			t.Errorf("%v: Show = %v, want the settled position %v", edge, shownAt, settled)
+		}
+	}
+}
+
+func TestSetSettings(t *testing.T) {
+	m := docked(Left)
+	m.SetSettings(Settings{Edges: 1<<Left | 1<<Top, AutoHide: true})
+	if m.Edge() != Left {
+		t.Errorf("edge %v after keeping left allowed", m.Edge())
+	}
+	m.SetSettings(Settings{Edges: 1 << Top, AutoHide: true})
+	if m.Edge() != None || m.Hidden() {
+		t.Errorf("edge %v, hidden %v after disallowing left", m.Edge(), m.Hidden())
+	}
+}
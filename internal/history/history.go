@@ -0,0 +1,292 @@
+// Package history records shortcut launches in an append-only JSON Lines
+// file in the data directory and derives usage statistics from it.
+package history
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FileName is the file in the data directory holding the launch history
+const FileName = "history.jsonl"
+
+// Path returns the location of the history file inside dataDir
+func Path(dataDir string) string {
+	return filepath.Join(dataDir, FileName)
+}
+
+// Entry is one launch, stored as a single JSON line
+type Entry struct {
+	ID    string `json:"id"`             // 快捷方式 ID
+	Name  string `json:"name,omitempty"` // 启动时的名称，快捷方式删除后导出仍可读
+	At    int64  `json:"t"`              // Unix 秒
+	OK    bool   `json:"ok"`
+	Error string `json:"err,omitempty"`
+}
+
+// NewEntry builds the entry for a launch that finished with err
+func NewEntry(id, name string, at time.Time, err error) Entry {
+	e := Entry{ID: id, Name: name, At: at.Unix(), OK: err == nil}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// Time returns when the launch happened
+func (e Entry) Time() time.Time {
+	return time.Unix(e.At, 0)
+}
+
+// Append adds one entry to the history file without loading it, so other
+// processes (the CLI) can record launches while the app keeps the file open.
+func Append(path string, e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	// 单次 write 追加整行，多个进程同时写入也不会交错
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Stat summarises the launches of one shortcut
+type Stat struct {
+	ID       string
+	Name     string    // 最近一次启动时的名称
+	Launches int       // 成功次数
+	Failures int       // 失败次数
+	Last     time.Time // 最近一次成功启动，从未成功时为零值
+}
+
+// Store keeps the history in memory and mirrors every change to disk
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	entries []Entry // 按时间顺序
+}
+
+// Open loads the history file. A missing file is an empty history and
+// malformed lines (e.g. a write cut short by a crash) are skipped.
+func Open(path string) (*Store, error) {
+	entries, err := readEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{path: path, entries: entries}, nil
+}
+
+// readEntries parses the history file in time order
+func readEntries(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil && e.ID != "" {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// 其他进程追加的记录可能乱序
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].At < entries[j].At })
+	return entries, nil
+}
+
+// Record appends an entry to the file and the in-memory history
+func (s *Store) Record(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := Append(s.path, e); err != nil {
+		return err
+	}
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+// Clear deletes the whole history
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	s.entries = nil
+	return nil
+}
+
+// Prune drops the entries older than before and rewrites the file. The file
+// is read again first, so launches the CLI appended since Open are kept.
+func (s *Store) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := readEntries(s.path)
+	if err != nil {
+		return err
+	}
+	s.entries = entries
+	cut := sort.Search(len(entries), func(i int) bool { return entries[i].At >= before.Unix() })
+	if cut == 0 {
+		return nil
+	}
+	kept := append([]Entry{}, entries[cut:]...)
+
+	// 先写临时文件再替换，避免中途失败丢失历史
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	for _, e := range kept {
+		if err := encoder.Encode(e); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	s.entries = kept
+	return nil
+}
+
+// Stats returns the statistics of every shortcut in the history, by ID
+func (s *Store) Stats() map[string]Stat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := map[string]Stat{}
+	for _, e := range s.entries {
+		st := stats[e.ID]
+		st.ID = e.ID
+		if e.Name != "" {
+			st.Name = e.Name
+		}
+		if e.OK {
+			st.Launches++
+			st.Last = e.Time()
+		} else {
+			st.Failures++
+		}
+		stats[e.ID] = st
+	}
+	return stats
+}
+
+// Recent returns the IDs of the most recently launched shortcuts, newest
+// first, at most limit of them (0 means no limit)
+func (s *Store) Recent(limit int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	seen := map[string]bool{}
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		e := s.entries[i]
+		if !e.OK || seen[e.ID] {
+			continue
+		}
+		seen[e.ID] = true
+		ids = append(ids, e.ID)
+		if limit > 0 && len(ids) == limit {
+			break
+		}
+	}
+	return ids
+}
+
+// MostUsed returns the IDs with the most successful launches, ties broken by
+// the most recent launch, at most limit of them (0 means no limit)
+func (s *Store) MostUsed(limit int) []string {
+	stats := SortedStats(s.Stats())
+	var ids []string
+	for _, st := range stats {
+		if st.Launches == 0 || (limit > 0 && len(ids) == limit) {
+			break
+		}
+		ids = append(ids, st.ID)
+	}
+	return ids
+}
+
+// SortedStats orders statistics by launches, then by the last launch
+func SortedStats(stats map[string]Stat) []Stat {
+	list := make([]Stat, 0, len(stats))
+	for _, st := range stats {
+		list = append(list, st)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Launches != list[j].Launches {
+			return list[i].Launches > list[j].Launches
+		}
+		if !list[i].Last.Equal(list[j].Last) {
+			return list[i].Last.After(list[j].Last)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// WriteCSV exports the statistics, most used first. group reports the group
+// a shortcut currently belongs to ("" once it has been deleted).
+func WriteCSV(w io.Writer, stats map[string]Stat, group func(id string) string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"group", "name", "id", "launches", "failures", "last_launched"}); err != nil {
+		return err
+	}
+	for _, st := range SortedStats(stats) {
+		last := ""
+		if !st.Last.IsZero() {
+			last = st.Last.Format(time.RFC3339)
+		}
+		record := []string{group(st.ID), st.Name, st.ID, strconv.Itoa(st.Launches), strconv.Itoa(st.Failures), last}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
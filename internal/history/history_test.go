@@ -0,0 +1,230 @@
+package history
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var base = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// at returns base plus the given number of minutes
+func at(minutes int) time.Time {
+	return base.Add(time.Duration(minutes) * time.Minute)
+}
+
+// openTemp opens a store on a fresh file holding entries, written with Append
+func openTemp(t *testing.T, entries ...Entry) *Store {
+	t.Helper()
+	path := Path(t.TempDir())
+	for _, e := range entries {
+		if err := Append(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAppendOpen(t *testing.T) {
+	path := Path(t.TempDir())
+	s, err := Open(path)
+	if err != nil || len(s.entries) != 0 {
+		t.Fatalf("Open of a missing file = %+v, %v, want an empty store", s, err)
+	}
+
+	want := []Entry{
+		NewEntry("a", "Alpha", at(0), nil),
+		NewEntry("b", "Beta", at(1), errors.New("exit status 1")),
+		NewEntry("a", "Alpha", at(2), nil),
+	}
+	// 乱序追加、半行和空 ID 的记录
+	for _, e := range []Entry{want[0], want[2], want[1]} {
+		if err := Append(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":"","t":5}` + "\n" + `{"id":"c","t":`)
+	f.Close()
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.entries, want) {
+		t.Errorf("entries = %+v, want %+v", s.entries, want)
+	}
+	if !want[1].Time().Equal(at(1)) || want[1].OK || want[1].Error != "exit status 1" {
+		t.Errorf("failed entry = %+v", want[1])
+	}
+}
+
+func TestRecordClear(t *testing.T) {
+	s := openTemp(t, NewEntry("a", "Alpha", at(0), nil))
+	if err := s.Record(NewEntry("b", "Beta", at(1), nil)); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := Open(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reopened.entries) != 2 || len(s.entries) != 2 {
+		t.Errorf("after Record: %d entries on disk, %d in memory, want 2", len(reopened.entries), len(s.entries))
+	}
+
+	if err := s.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.path); !os.IsNotExist(err) || len(s.entries) != 0 {
+		t.Errorf("after Clear: stat = %v, %d entries", err, len(s.entries))
+	}
+	if err := s.Clear(); err != nil {
+		t.Errorf("Clear of a missing file: %v", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s := openTemp(t,
+		NewEntry("a", "Alpha", at(0), nil),
+		NewEntry("b", "Beta", at(10), nil),
+		NewEntry("c", "Gamma", at(20), nil),
+	)
+	// CLI 在 Open 之后追加的记录
+	if err := Append(s.path, NewEntry("d", "Delta", at(30), nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(s.path, NewEntry("old", "Old", at(-5), nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Prune(at(10)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"b", "c", "d"}
+	if got := ids(s.entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("in memory = %q, want %q", got, want)
+	}
+	reopened, err := Open(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(reopened.entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("on disk = %q, want %q", got, want)
+	}
+	if _, err := os.Stat(s.path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	// 没有过期记录时不改写文件，但仍读入新追加的记录
+	if err := Append(s.path, NewEntry("e", "Epsilon", at(40), nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Prune(at(0)); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(s.entries); !reflect.DeepEqual(got, []string{"b", "c", "d", "e"}) {
+		t.Errorf("after a no-op Prune = %q", got)
+	}
+}
+
+func ids(entries []Entry) []string {
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.ID)
+	}
+	return out
+}
+
+// usage is a history where a is launched three times, b and c twice (c more
+// recently) and d only fails
+func usage(t *testing.T) *Store {
+	return openTemp(t,
+		NewEntry("a", "Alpha", at(0), nil),
+		NewEntry("b", "Beta", at(1), nil),
+		NewEntry("a", "Alpha", at(2), nil),
+		NewEntry("c", "Gamma", at(3), nil),
+		NewEntry("d", "Delta", at(4), errors.New("not found")),
+		NewEntry("b", "Beta", at(5), nil),
+		NewEntry("c", "Gamma 2", at(6), nil), // 改名后启动
+		NewEntry("a", "Alpha", at(7), nil),
+		NewEntry("b", "Beta", at(8), errors.New("exit status 2")),
+	)
+}
+
+func TestStats(t *testing.T) {
+	want := map[string]Stat{
+		"a": {ID: "a", Name: "Alpha", Launches: 3, Last: at(7)},
+		"b": {ID: "b", Name: "Beta", Launches: 2, Failures: 1, Last: at(5)},
+		"c": {ID: "c", Name: "Gamma 2", Launches: 2, Last: at(6)},
+		"d": {ID: "d", Name: "Delta", Failures: 1},
+	}
+	got := usage(t).Stats()
+	if len(got) != len(want) {
+		t.Fatalf("Stats = %+v, want %+v", got, want)
+	}
+	for id, w := range want {
+		g := got[id]
+		if g.ID != w.ID || g.Name != w.Name || g.Launches != w.Launches || g.Failures != w.Failures || !g.Last.Equal(w.Last) {
+			t.Errorf("Stats[%q] = %+v, want %+v", id, g, w)
+		}
+	}
+}
+
+func TestRecentMostUsed(t *testing.T) {
+	s := usage(t)
+	tests := []struct {
+		name  string
+		fn    func(int) []string
+		limit int
+		want  []string
+	}{
+		{"Recent", s.Recent, 0, []string{"a", "c", "b"}}, // 失败的启动不算
+		{"Recent", s.Recent, 2, []string{"a", "c"}},
+		{"MostUsed", s.MostUsed, 0, []string{"a", "c", "b"}}, // 次数相同时最近的在前
+		{"MostUsed", s.MostUsed, 1, []string{"a"}},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.limit); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s(%d) = %q, want %q", tt.name, tt.limit, got, tt.want)
+		}
+	}
+
+	empty := openTemp(t)
+	if got := empty.Recent(5); got != nil {
+		t.Errorf("Recent of an empty history = %q", got)
+	}
+	if got := empty.MostUsed(5); got != nil {
+		t.Errorf("MostUsed of an empty history = %q", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	groups := map[string]string{"a": "Dev", "b": "Web, Docs", "c": "Dev"}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, usage(t).Stats(), func(id string) string { return groups[id] }); err != nil {
+		t.Fatal(err)
+	}
+	last := func(m int) string { return at(m).Local().Format(time.RFC3339) }
+	want := strings.Join([]string{
+		"group,name,id,launches,failures,last_launched",
+		"Dev,Alpha,a,3,0," + last(7),
+		"Dev,Gamma 2,c,2,0," + last(6),
+		`"Web, Docs",Beta,b,2,1,` + last(5),
+		",Delta,d,0,1,", // 已删除的快捷方式，从未成功启动
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV =\n%s\nwant\n%s", got, want)
+	}
+}
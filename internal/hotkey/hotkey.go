@@ -0,0 +1,161 @@
+// Package hotkey registers system-wide keyboard shortcuts. Parsing, conflict
+// checks and dispatch have no platform dependencies; grabbing the keys goes
+// through a Backend (RegisterHotKey on Windows, the XDG GlobalShortcuts
+// portal on Linux).
+package hotkey
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalid     = errors.New("invalid hotkey")
+	ErrDuplicate   = errors.New("hotkey is assigned twice")
+	ErrInUse       = errors.New("hotkey is used by another program")
+	ErrUnsupported = errors.New("global hotkeys are not supported on this desktop")
+)
+
+// Modifier is a set of modifier keys
+type Modifier uint8
+
+const (
+	ModCtrl Modifier = 1 << iota
+	ModAlt
+	ModShift
+	ModSuper // Windows 键 / Super
+)
+
+// modifierNames 按显示顺序排列
+var modifierNames = []struct {
+	mod  Modifier
+	name string
+}{
+	{ModCtrl, "Ctrl"},
+	{ModAlt, "Alt"},
+	{ModShift, "Shift"},
+	{ModSuper, "Super"},
+}
+
+// modifierAliases 解析时接受的修饰键写法（小写）
+var modifierAliases = map[string]Modifier{
+	"ctrl": ModCtrl, "control": ModCtrl,
+	"alt": ModAlt, "option": ModAlt,
+	"shift": ModShift,
+	"super": ModSuper, "win": ModSuper, "meta": ModSuper, "cmd": ModSuper,
+}
+
+// namedKeys are the non-alphanumeric keys, by their canonical name
+var namedKeys = []string{
+	"Space", "Enter", "Tab", "Escape", "Backspace", "Insert", "Delete",
+	"Home", "End", "PageUp", "PageDown", "Up", "Down", "Left", "Right",
+}
+
+// keyAliases 解析时接受的其它按键写法（小写）
+var keyAliases = map[string]string{
+	"esc": "Escape", "return": "Enter", "del": "Delete", "ins": "Insert",
+	"pgup": "PageUp", "pgdn": "PageDown", "pgdown": "PageDown",
+}
+
+// Hotkey is a key combined with modifiers. Key is a canonical name: "A"-"Z",
+// "0"-"9", "F1"-"F24" or one of the named keys such as "Space".
+type Hotkey struct {
+	Mods Modifier
+	Key  string
+}
+
+// Parse reads a hotkey such as "Ctrl+Alt+T" or "super + space". Names are
+// case-insensitive. Apart from the function keys a hotkey needs Ctrl, Alt or
+// Super, so that it does not take over ordinary typing.
+func Parse(s string) (Hotkey, error) {
+	var h Hotkey
+	parts := strings.Split(s, "+")
+	for i, part := range parts {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			return Hotkey{}, fmt.Errorf("%w: %q", ErrInvalid, s)
+		}
+		if i < len(parts)-1 {
+			mod, ok := modifierAliases[name]
+			if !ok {
+				return Hotkey{}, fmt.Errorf("%w: unknown modifier %q", ErrInvalid, strings.TrimSpace(part))
+			}
+			if h.Mods&mod != 0 {
+				return Hotkey{}, fmt.Errorf("%w: %s is repeated", ErrInvalid, strings.TrimSpace(part))
+			}
+			h.Mods |= mod
+			continue
+		}
+		key, ok := canonicalKey(name)
+		if !ok {
+			return Hotkey{}, fmt.Errorf("%w: unknown key %q", ErrInvalid, strings.TrimSpace(part))
+		}
+		h.Key = key
+	}
+	if h.Mods&^ModShift == 0 && !isFunctionKey(h.Key) {
+		return Hotkey{}, fmt.Errorf("%w: %q needs Ctrl, Alt or Super", ErrInvalid, s)
+	}
+	return h, nil
+}
+
+// canonicalKey returns the canonical name of a lower-case key name
+func canonicalKey(name string) (string, bool) {
+	if len(name) == 1 {
+		c := name[0]
+		if c >= 'a' && c <= 'z' {
+			return strings.ToUpper(name), true
+		}
+		if c >= '0' && c <= '9' {
+			return name, true
+		}
+		return "", false
+	}
+	if alias, ok := keyAliases[name]; ok {
+		return alias, true
+	}
+	for _, key := range namedKeys {
+		if strings.ToLower(key) == name {
+			return key, true
+		}
+	}
+	if key := strings.ToUpper(name); isFunctionKey(key) {
+		return key, true
+	}
+	return "", false
+}
+
+// isFunctionKey reports whether key is F1-F24
+func isFunctionKey(key string) bool {
+	n, ok := functionKeyNumber(key)
+	return ok && n >= 1 && n <= 24
+}
+
+func functionKeyNumber(key string) (int, bool) {
+	if len(key) < 2 || key[0] != 'F' {
+		return 0, false
+	}
+	n, err := strconv.Atoi(key[1:])
+	return n, err == nil
+}
+
+// String formats the hotkey in canonical form, e.g. "Ctrl+Alt+T"
+func (h Hotkey) String() string {
+	var parts []string
+	for _, m := range modifierNames {
+		if h.Mods&m.mod != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	return strings.Join(append(parts, h.Key), "+")
+}
+
+// Normalize returns s in canonical form, or s unchanged if it does not parse
+func Normalize(s string) string {
+	h, err := Parse(s)
+	if err != nil {
+		return s
+	}
+	return h.String()
+}
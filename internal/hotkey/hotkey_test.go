@@ -0,0 +1,92 @@
+package hotkey
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Hotkey
+	}{
+		{"Ctrl+Alt+T", Hotkey{ModCtrl | ModAlt, "T"}},
+		{"ctrl + alt + t", Hotkey{ModCtrl | ModAlt, "T"}},
+		{"Alt+Ctrl+T", Hotkey{ModCtrl | ModAlt, "T"}},
+		{"Control+Option+Shift+1", Hotkey{ModCtrl | ModAlt | ModShift, "1"}},
+		{"Win+Space", Hotkey{ModSuper, "Space"}},
+		{"meta+esc", Hotkey{ModSuper, "Escape"}},
+		{"Cmd+PgDn", Hotkey{ModSuper, "PageDown"}},
+		{"Ctrl+pageup", Hotkey{ModCtrl, "PageUp"}},
+		{"F12", Hotkey{0, "F12"}},
+		{"Shift+f24", Hotkey{ModShift, "F24"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"T",              // 没有修饰键
+		"Shift+T",        // Shift 不够
+		"Ctrl+",          // 没有按键
+		"Ctrl++T",        // 空的修饰键
+		"Hyper+T",        // 未知修饰键
+		"Ctrl+Ctrl+T",    // 重复的修饰键
+		"Ctrl+Control+T", // 别名也算重复
+		"Ctrl+Alt+Tee",   // 未知按键
+		"Ctrl+F25",
+		"Ctrl+F0",
+		"Ctrl+#",
+		"T+Ctrl", // 按键必须在最后
+	} {
+		if h, err := Parse(in); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) = %+v, %v, want %v", in, h, err, ErrInvalid)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		h    Hotkey
+		want string
+	}{
+		{Hotkey{ModSuper | ModShift | ModAlt | ModCtrl, "A"}, "Ctrl+Alt+Shift+Super+A"},
+		{Hotkey{ModAlt, "Space"}, "Alt+Space"},
+		{Hotkey{0, "F5"}, "F5"},
+	}
+	for _, tt := range tests {
+		if got := tt.h.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.h, got, tt.want)
+		}
+		// 规范形式可以原样解析回来
+		if back, err := Parse(tt.want); err != nil || back != tt.h {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", tt.want, back, err, tt.h)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"alt+ctrl+t", "Ctrl+Alt+T"},
+		{" super + return ", "Super+Enter"},
+		{"Win+del", "Super+Delete"},
+		{"f3", "F3"},
+		{"Ctrl+Alt+Tee", "Ctrl+Alt+Tee"}, // 无法解析时原样返回
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+//go:build !windows
+
+package hotkey
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	portalBus      = "org.freedesktop.portal.Desktop"
+	portalPath     = "/org/freedesktop/portal/desktop"
+	shortcutsIface = "org.freedesktop.portal.GlobalShortcuts"
+	requestIface   = "org.freedesktop.portal.Request"
+	sessionIface   = "org.freedesktop.portal.Session"
+
+	// requestTimeout 绑定时桌面可能弹出确认对话框，等待用户操作
+	requestTimeout = 2 * time.Minute
+)
+
+// xdgKeys 命名按键对应的 XKB keysym 名称
+var xdgKeys = map[string]string{
+	"Space": "space", "Enter": "Return", "Backspace": "BackSpace",
+	"PageUp": "Page_Up", "PageDown": "Page_Down",
+}
+
+// portalBackend binds hotkeys through the XDG GlobalShortcuts portal, which
+// works on Wayland as well as X11 (KDE Plasma, GNOME 48+, Hyprland...). The
+// desktop may ask the user to confirm or change the keys, and lists them in
+// its own shortcut settings.
+type portalBackend struct {
+	conn      *dbus.Conn
+	portal    dbus.BusObject
+	sender    string // 唯一连接名，用于拼出请求对象路径
+	signals   chan *dbus.Signal
+	triggered chan int
+
+	mu      sync.Mutex
+	session dbus.ObjectPath
+	ids     map[string]int // 门户中的快捷键 ID -> Key.ID
+	pending map[dbus.ObjectPath]chan portalResponse
+	token   int
+}
+
+type portalResponse struct {
+	code    uint32 // 0 成功，1 用户取消，2 其它错误
+	results map[string]dbus.Variant
+}
+
+// portalShortcut is the (sa{sv}) struct of BindShortcuts
+type portalShortcut struct {
+	ID      string
+	Options map[string]dbus.Variant
+}
+
+// NewBackend connects to the GlobalShortcuts portal. It returns
+// ErrUnsupported when the desktop does not provide it.
+func NewBackend() (Backend, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	portal := conn.Object(portalBus, portalPath)
+	if _, err := portal.GetProperty(shortcutsIface + ".version"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	b := &portalBackend{
+		conn:      conn,
+		portal:    portal,
+		sender:    strings.ReplaceAll(strings.TrimPrefix(conn.Names()[0], ":"), ".", "_"),
+		signals:   make(chan *dbus.Signal, 16),
+		triggered: make(chan int, 16),
+		ids:       map[string]int{},
+		pending:   map[dbus.ObjectPath]chan portalResponse{},
+	}
+	if err := conn.AddMatchSignal(dbus.WithMatchInterface(requestIface), dbus.WithMatchMember("Response")); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.AddMatchSignal(dbus.WithMatchInterface(shortcutsIface), dbus.WithMatchMember("Activated")); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.Signal(b.signals)
+	go b.listen()
+	return b, nil
+}
+
+// listen routes request responses and shortcut activations; it ends when
+// the connection is closed
+func (b *portalBackend) listen() {
+	defer close(b.triggered)
+	for sig := range b.signals {
+		switch sig.Name {
+		case requestIface + ".Response":
+			var r portalResponse
+			if len(sig.Body) >= 2 {
+				r.code, _ = sig.Body[0].(uint32)
+				r.results, _ = sig.Body[1].(map[string]dbus.Variant)
+			}
+			b.mu.Lock()
+			ch := b.pending[sig.Path]
+			delete(b.pending, sig.Path)
+			b.mu.Unlock()
+			if ch != nil {
+				ch <- r
+			}
+		case shortcutsIface + ".Activated":
+			if len(sig.Body) < 2 {
+				continue
+			}
+			session, _ := sig.Body[0].(dbus.ObjectPath)
+			name, _ := sig.Body[1].(string)
+			b.mu.Lock()
+			id, ok := b.ids[name]
+			ok = ok && session == b.session
+			b.mu.Unlock()
+			if ok {
+				select {
+				case b.triggered <- id:
+				default: // 处理不过来时丢弃
+				}
+			}
+		}
+	}
+}
+
+// request calls a portal method that answers through a Request object and
+// waits for the response. options is passed as the last argument.
+func (b *portalBackend) request(method string, options map[string]dbus.Variant, args ...interface{}) (map[string]dbus.Variant, error) {
+	b.mu.Lock()
+	b.token++
+	token := fmt.Sprintf("musetool%d", b.token)
+	path := dbus.ObjectPath(fmt.Sprintf("%s/request/%s/%s", portalPath, b.sender, token))
+	ch := make(chan portalResponse, 1)
+	b.pending[path] = ch
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.pending, path)
+		b.mu.Unlock()
+	}()
+
+	options["handle_token"] = dbus.MakeVariant(token)
+	if err := b.portal.Call(shortcutsIface+"."+method, 0, append(args, options)...).Err; err != nil {
+		return nil, err
+	}
+	select {
+	case r := <-ch:
+		if r.code != 0 {
+			return nil, fmt.Errorf("%s was declined by the desktop (response %d)", method, r.code)
+		}
+		return r.results, nil
+	case <-time.After(requestTimeout):
+		return nil, fmt.Errorf("%s timed out", method)
+	}
+}
+
+// Bind closes the previous session and binds keys in a new one: version 1
+// of the portal cannot remove shortcuts from a session
+func (b *portalBackend) Bind(keys []Key) []error {
+	errs := make([]error, len(keys))
+	fail := func(err error) []error {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	b.closeSession()
+	if len(keys) == 0 {
+		return errs
+	}
+
+	results, err := b.request("CreateSession", map[string]dbus.Variant{
+		"session_handle_token": dbus.MakeVariant(fmt.Sprintf("musetool%d", time.Now().UnixNano())),
+	})
+	if err != nil {
+		return fail(err)
+	}
+	session := sessionHandle(results["session_handle"])
+	if session == "" {
+		return fail(errors.New("the portal did not return a session"))
+	}
+
+	shortcuts := make([]portalShortcut, len(keys))
+	names := make([]string, len(keys))
+	ids := map[string]int{}
+	for i, k := range keys {
+		names[i] = portalID(k.Description, ids)
+		ids[names[i]] = k.ID
+		shortcuts[i] = portalShortcut{ID: names[i], Options: map[string]dbus.Variant{
+			"description":       dbus.MakeVariant(k.Description),
+			"preferred_trigger": dbus.MakeVariant(xdgTrigger(k.Hotkey)),
+		}}
+	}
+	b.mu.Lock()
+	b.session = session
+	b.ids = ids
+	b.mu.Unlock()
+
+	results, err = b.request("BindShortcuts", map[string]dbus.Variant{}, session, shortcuts, "")
+	if err != nil {
+		return fail(err)
+	}
+	// 结果中没有的快捷键没有绑定（通常是与其它程序冲突）
+	if v, ok := results["shortcuts"]; ok {
+		bound := boundShortcuts(v)
+		for i, name := range names {
+			if !bound[name] {
+				errs[i] = ErrInUse
+			}
+		}
+	}
+	return errs
+}
+
+func (b *portalBackend) closeSession() {
+	b.mu.Lock()
+	session := b.session
+	b.session = ""
+	b.ids = map[string]int{}
+	b.mu.Unlock()
+	if session != "" {
+		b.conn.Object(portalBus, session).Call(sessionIface+".Close", 0)
+	}
+}
+
+func (b *portalBackend) Triggered() <-chan int {
+	return b.triggered
+}
+
+// Close releases the shortcuts and disconnects from the bus
+func (b *portalBackend) Close() error {
+	b.closeSession()
+	return b.conn.Close()
+}
+
+// sessionHandle reads session_handle, which older portals send as a string
+func sessionHandle(v dbus.Variant) dbus.ObjectPath {
+	switch h := v.Value().(type) {
+	case dbus.ObjectPath:
+		return h
+	case string:
+		return dbus.ObjectPath(h)
+	}
+	return ""
+}
+
+// boundShortcuts returns the IDs in the a(sa{sv}) result of BindShortcuts
+func boundShortcuts(v dbus.Variant) map[string]bool {
+	bound := map[string]bool{}
+	list, _ := v.Value().([][]interface{})
+	for _, item := range list {
+		if len(item) > 0 {
+			if id, ok := item[0].(string); ok {
+				bound[id] = true
+			}
+		}
+	}
+	return bound
+}
+
+// portalID derives a stable shortcut ID from the description, so the
+// desktop keeps any trigger the user changed across sessions
+func portalID(description string, taken map[string]int) string {
+	id := description
+	if id == "" {
+		id = "hotkey"
+	}
+	unique := id
+	for n := 2; ; n++ {
+		if _, ok := taken[unique]; !ok {
+			return unique
+		}
+		unique = fmt.Sprintf("%s (%d)", id, n)
+	}
+}
+
+// xdgTrigger formats the hotkey for preferred_trigger (the XDG shortcuts
+// format, e.g. "CTRL+ALT+t")
+func xdgTrigger(h Hotkey) string {
+	var parts []string
+	for _, m := range []struct {
+		mod  Modifier
+		name string
+	}{{ModCtrl, "CTRL"}, {ModAlt, "ALT"}, {ModShift, "SHIFT"}, {ModSuper, "LOGO"}} {
+		if h.Mods&m.mod != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	key := h.Key
+	if len(key) == 1 {
+		key = strings.ToLower(key)
+	} else if name, ok := xdgKeys[key]; ok {
+		key = name
+	}
+	return strings.Join(append(parts, key), "+")
+}
@@ -0,0 +1,200 @@
+package hotkey
+
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32                 = windows.NewLazySystemDLL("user32.dll")
+	procRegisterHotKey     = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey   = user32.NewProc("UnregisterHotKey")
+	procGetMessageW        = user32.NewProc("GetMessageW")
+	procPeekMessageW       = user32.NewProc("PeekMessageW")
+	procPostThreadMessageW = user32.NewProc("PostThreadMessageW")
+)
+
+const (
+	modAlt      = 0x0001 // MOD_ALT
+	modControl  = 0x0002 // MOD_CONTROL
+	modShift    = 0x0004 // MOD_SHIFT
+	modWin      = 0x0008 // MOD_WIN
+	modNoRepeat = 0x4000 // MOD_NOREPEAT：按住不放时只触发一次
+
+	wmQuit     = 0x0012
+	wmHotkey   = 0x0312
+	wmCall     = 0x8000 + 1 // WM_APP+1：在热键线程上执行排队的调用
+	pmNoRemove = 0x0000
+
+	errorHotkeyAlreadyRegistered = 1409
+)
+
+// vkNamed 命名按键的虚拟键码
+var vkNamed = map[string]uintptr{
+	"Space": 0x20, "Enter": 0x0D, "Tab": 0x09, "Escape": 0x1B, "Backspace": 0x08,
+	"Insert": 0x2D, "Delete": 0x2E, "Home": 0x24, "End": 0x23,
+	"PageUp": 0x21, "PageDown": 0x22, "Up": 0x26, "Down": 0x28, "Left": 0x25, "Right": 0x27,
+}
+
+type msg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+}
+
+// windowsBackend registers thread hotkeys: RegisterHotKey posts WM_HOTKEY to
+// the thread that registered it, so one locked OS thread owns every hotkey
+// and runs a message loop. Other goroutines queue calls to it.
+type windowsBackend struct {
+	threadID  uint32
+	calls     chan func()
+	triggered chan int
+	stopped   chan struct{} // 线程退出后关闭
+	ids       []int         // 当前注册的 ID
+}
+
+// NewBackend starts the hotkey thread
+func NewBackend() (Backend, error) {
+	b := &windowsBackend{calls: make(chan func(), 16), triggered: make(chan int, 16), stopped: make(chan struct{})}
+	ready := make(chan struct{})
+	go b.loop(ready)
+	<-ready
+	return b, nil
+}
+
+func (b *windowsBackend) loop(ready chan<- struct{}) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(b.stopped)
+	defer close(b.triggered)
+
+	b.threadID = windows.GetCurrentThreadId()
+	// PeekMessage 为线程创建消息队列，之后 PostThreadMessage 才能成功
+	var m msg
+	procPeekMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0, pmNoRemove)
+	close(ready)
+
+	for {
+		r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+		if int32(r) <= 0 { // WM_QUIT 或出错
+			b.unregisterAll()
+			return
+		}
+		switch m.message {
+		case wmHotkey:
+			select {
+			case b.triggered <- int(m.wParam):
+			default: // 处理不过来时丢弃
+			}
+		case wmCall:
+			for pending := true; pending; {
+				select {
+				case f := <-b.calls:
+					f()
+				default:
+					pending = false
+				}
+			}
+		}
+	}
+}
+
+// call runs f on the hotkey thread and waits for it. It returns false
+// without running f once the thread has stopped.
+func (b *windowsBackend) call(f func()) bool {
+	done := make(chan struct{})
+	select {
+	case b.calls <- func() { f(); close(done) }:
+	case <-b.stopped:
+		return false
+	}
+	procPostThreadMessageW.Call(uintptr(b.threadID), wmCall, 0, 0)
+	select {
+	case <-done:
+		return true
+	case <-b.stopped:
+		return false
+	}
+}
+
+func (b *windowsBackend) Bind(keys []Key) []error {
+	errs := make([]error, len(keys))
+	ok := b.call(func() {
+		b.unregisterAll()
+		for i, k := range keys {
+			if errs[i] = register(k); errs[i] == nil {
+				b.ids = append(b.ids, k.ID)
+			}
+		}
+	})
+	if !ok {
+		for i := range errs {
+			errs[i] = ErrUnsupported
+		}
+	}
+	return errs
+}
+
+func (b *windowsBackend) unregisterAll() {
+	for _, id := range b.ids {
+		procUnregisterHotKey.Call(0, uintptr(id))
+	}
+	b.ids = nil
+}
+
+func register(k Key) error {
+	vk, ok := virtualKey(k.Hotkey.Key)
+	if !ok {
+		return ErrInvalid
+	}
+	mods := uintptr(modNoRepeat)
+	if k.Hotkey.Mods&ModCtrl != 0 {
+		mods |= modControl
+	}
+	if k.Hotkey.Mods&ModAlt != 0 {
+		mods |= modAlt
+	}
+	if k.Hotkey.Mods&ModShift != 0 {
+		mods |= modShift
+	}
+	if k.Hotkey.Mods&ModSuper != 0 {
+		mods |= modWin
+	}
+	r, _, err := procRegisterHotKey.Call(0, uintptr(k.ID), mods, vk)
+	if r != 0 {
+		return nil
+	}
+	if errors.Is(err, windows.Errno(errorHotkeyAlreadyRegistered)) {
+		return ErrInUse
+	}
+	return err
+}
+
+// virtualKey returns the virtual-key code of a canonical key name
+func virtualKey(key string) (uintptr, bool) {
+	if len(key) == 1 {
+		// 字母和数字的虚拟键码就是大写 ASCII
+		return uintptr(key[0]), true
+	}
+	if n, ok := functionKeyNumber(key); ok {
+		return uintptr(0x70 + n - 1), true // VK_F1 = 0x70
+	}
+	vk, ok := vkNamed[key]
+	return vk, ok
+}
+
+func (b *windowsBackend) Triggered() <-chan int {
+	return b.triggered
+}
+
+// Close unregisters every hotkey and stops the thread
+func (b *windowsBackend) Close() error {
+	procPostThreadMessageW.Call(uintptr(b.threadID), wmQuit, 0, 0)
+	return nil
+}
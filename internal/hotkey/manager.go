@@ -0,0 +1,161 @@
+package hotkey
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Key is a hotkey handed to a Backend. ID is reported back when it is pressed.
+type Key struct {
+	ID          int
+	Hotkey      Hotkey
+	Description string
+}
+
+// Backend grabs hotkeys from the desktop. The platform provides the real
+// implementation (NewBackend); tests can provide a fake.
+type Backend interface {
+	// Bind replaces every hotkey grabbed before with keys and returns one
+	// error per key, nil when it was registered
+	Bind(keys []Key) []error
+	// Triggered delivers the ID of each pressed hotkey; it is closed by Close
+	Triggered() <-chan int
+	Close() error
+}
+
+// Binding assigns a hotkey to an action
+type Binding struct {
+	Name   string // 显示给用户的名称，用于冲突提示
+	Hotkey string // 空表示不使用
+	Action func()
+}
+
+// Conflict is a binding that could not be registered
+type Conflict struct {
+	Name   string
+	Hotkey string
+	Err    error // ErrInvalid, ErrDuplicate, ErrInUse...
+}
+
+func (c Conflict) Error() string {
+	return fmt.Sprintf("%s (%s): %v", c.Name, c.Hotkey, c.Err)
+}
+
+func (c Conflict) Unwrap() error {
+	return c.Err
+}
+
+// FormatConflicts lists conflicts one per line for an error message
+func FormatConflicts(conflicts []Conflict) string {
+	lines := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		lines[i] = "• " + c.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// planned is a binding that parsed and is not a duplicate
+type planned struct {
+	key     Key
+	binding Binding
+}
+
+// plan parses the bindings, skipping empty hotkeys, and numbers the keys
+// from firstID. When two bindings use the same hotkey the first one keeps it.
+func plan(bindings []Binding, firstID int) ([]planned, []Conflict) {
+	var keys []planned
+	var conflicts []Conflict
+	owners := map[Hotkey]string{}
+	for _, b := range bindings {
+		if strings.TrimSpace(b.Hotkey) == "" {
+			continue
+		}
+		h, err := Parse(b.Hotkey)
+		if err != nil {
+			conflicts = append(conflicts, Conflict{Name: b.Name, Hotkey: b.Hotkey, Err: err})
+			continue
+		}
+		if owner, taken := owners[h]; taken {
+			conflicts = append(conflicts, Conflict{Name: b.Name, Hotkey: h.String(),
+				Err: fmt.Errorf("%w (%s)", ErrDuplicate, owner)})
+			continue
+		}
+		owners[h] = b.Name
+		keys = append(keys, planned{key: Key{ID: firstID + len(keys), Hotkey: h, Description: b.Name}, binding: b})
+	}
+	return keys, conflicts
+}
+
+// Check reports invalid and duplicate hotkeys without registering anything
+func Check(bindings []Binding) []Conflict {
+	_, conflicts := plan(bindings, 1)
+	return conflicts
+}
+
+// Manager registers bindings with a Backend and runs their actions when the
+// hotkeys are pressed. Actions run on the Manager's goroutine.
+type Manager struct {
+	backend Backend
+	setMu   sync.Mutex // 串行化 Set
+	nextID  int        // 每次 Set 使用新的 ID，重新注册期间旧 ID 不会触发新动作
+
+	mu      sync.Mutex
+	actions map[int]func()
+}
+
+func NewManager(backend Backend) *Manager {
+	m := &Manager{backend: backend, actions: map[int]func(){}}
+	go func() {
+		for id := range backend.Triggered() {
+			m.dispatch(id)
+		}
+	}()
+	return m
+}
+
+// Set replaces the registered hotkeys with bindings and returns the ones
+// that could not be registered. It may block while the desktop asks the user
+// to confirm, so call it off the UI thread.
+func (m *Manager) Set(bindings []Binding) []Conflict {
+	m.setMu.Lock()
+	defer m.setMu.Unlock()
+	// RegisterHotKey 的 ID 不能超过 0xBFFF
+	if m.nextID == 0 || m.nextID+len(bindings) > 0xBFFF {
+		m.nextID = 1
+	}
+	keys, conflicts := plan(bindings, m.nextID)
+	m.nextID += len(keys)
+	backendKeys := make([]Key, len(keys))
+	for i, k := range keys {
+		backendKeys[i] = k.key
+	}
+	errs := m.backend.Bind(backendKeys)
+
+	actions := map[int]func(){}
+	for i, k := range keys {
+		if i < len(errs) && errs[i] != nil {
+			conflicts = append(conflicts, Conflict{Name: k.binding.Name, Hotkey: k.key.Hotkey.String(), Err: errs[i]})
+			continue
+		}
+		actions[k.key.ID] = k.binding.Action
+	}
+	m.mu.Lock()
+	m.actions = actions
+	m.mu.Unlock()
+	return conflicts
+}
+
+func (m *Manager) dispatch(id int) {
+	m.mu.Lock()
+	action := m.actions[id]
+	m.mu.Unlock()
+	if action != nil {
+		action()
+	}
+}
+
+// Close releases every hotkey
+func (m *Manager) Close() error {
+	return m.backend.Close()
+}
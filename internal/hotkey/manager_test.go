@@ -0,0 +1,291 @@
+package hotkey
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBackend records the keys it is asked to grab and lets tests press them
+type fakeBackend struct {
+	mu        sync.Mutex
+	bound     []Key
+	inUse     map[Hotkey]bool // 被其它程序占用的热键
+	triggered chan int
+	closed    bool
+}
+
+func newFakeBackend(inUse ...string) *fakeBackend {
+	b := &fakeBackend{inUse: map[Hotkey]bool{}, triggered: make(chan int)}
+	for _, s := range inUse {
+		h, _ := Parse(s)
+		b.inUse[h] = true
+	}
+	return b
+}
+
+func (b *fakeBackend) Bind(keys []Key) []error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bound = nil
+	errs := make([]error, len(keys))
+	for i, k := range keys {
+		if b.inUse[k.Hotkey] {
+			errs[i] = ErrInUse
+			continue
+		}
+		b.bound = append(b.bound, k)
+	}
+	return errs
+}
+
+func (b *fakeBackend) Triggered() <-chan int {
+	return b.triggered
+}
+
+func (b *fakeBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		close(b.triggered)
+	}
+	return nil
+}
+
+// press delivers the ID currently bound to hotkey, as the desktop would
+func (b *fakeBackend) press(t *testing.T, hotkey string) {
+	t.Helper()
+	h, err := Parse(hotkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.mu.Lock()
+	id := 0
+	for _, k := range b.bound {
+		if k.Hotkey == h {
+			id = k.ID
+		}
+	}
+	b.mu.Unlock()
+	if id == 0 {
+		t.Fatalf("%s is not bound", hotkey)
+	}
+	b.triggered <- id
+}
+
+func (b *fakeBackend) boundHotkeys() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var names []string
+	for _, k := range b.bound {
+		names = append(names, k.Hotkey.String()+"="+k.Description)
+	}
+	return names
+}
+
+// recorder collects the names of the actions that ran
+type recorder struct {
+	ran chan string
+}
+
+func newRecorder() *recorder {
+	return &recorder{ran: make(chan string, 10)}
+}
+
+func (r *recorder) action(name string) func() {
+	return func() { r.ran <- name }
+}
+
+func (r *recorder) expect(t *testing.T, want string) {
+	t.Helper()
+	select {
+	case got := <-r.ran:
+		if got != want {
+			t.Errorf("ran %q, want %q", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("%q did not run", want)
+	}
+}
+
+// expectNone checks that no other action ran. The Manager dispatches presses
+// in order, so call it after expect for a later press.
+func (r *recorder) expectNone(t *testing.T) {
+	t.Helper()
+	select {
+	case got := <-r.ran:
+		t.Errorf("%q ran unexpectedly", got)
+	default:
+	}
+}
+
+func TestManagerRegistersAndDispatches(t *testing.T) {
+	backend := newFakeBackend()
+	m := NewManager(backend)
+	defer m.Close()
+	rec := newRecorder()
+
+	conflicts := m.Set([]Binding{
+		{Name: "Toggle", Hotkey: "ctrl+alt+space", Action: rec.action("toggle")},
+		{Name: "Search", Hotkey: "", Action: rec.action("search")}, // 空表示不使用
+		{Name: "Dev", Hotkey: "Super+1", Action: rec.action("dev")},
+	})
+	if len(conflicts) != 0 {
+		t.Fatalf("conflicts: %v", conflicts)
+	}
+	if got, want := strings.Join(backend.boundHotkeys(), ","), "Ctrl+Alt+Space=Toggle,Super+1=Dev"; got != want {
+		t.Errorf("bound %s, want %s", got, want)
+	}
+
+	backend.press(t, "Super+1")
+	rec.expect(t, "dev")
+	backend.press(t, "Ctrl+Alt+Space")
+	rec.expect(t, "toggle")
+}
+
+func TestManagerSetReplacesBindings(t *testing.T) {
+	backend := newFakeBackend()
+	m := NewManager(backend)
+	defer m.Close()
+	rec := newRecorder()
+
+	m.Set([]Binding{{Name: "Old", Hotkey: "Ctrl+Alt+O", Action: rec.action("old")}})
+	backend.mu.Lock()
+	oldID := backend.bound[0].ID
+	backend.mu.Unlock()
+
+	m.Set([]Binding{{Name: "New", Hotkey: "Ctrl+Alt+N", Action: rec.action("new")}})
+	if got := backend.boundHotkeys(); len(got) != 1 || got[0] != "Ctrl+Alt+N=New" {
+		t.Errorf("bound %v after Set, want only the new hotkey", got)
+	}
+
+	// 重新注册前按下的旧热键不会触发新动作
+	backend.triggered <- oldID
+	backend.press(t, "Ctrl+Alt+N")
+	rec.expect(t, "new")
+	rec.expectNone(t)
+
+	// 清空后不再注册任何热键
+	m.Set(nil)
+	if got := backend.boundHotkeys(); len(got) != 0 {
+		t.Errorf("bound %v after Set(nil)", got)
+	}
+}
+
+func TestManagerReportsConflicts(t *testing.T) {
+	backend := newFakeBackend("Ctrl+Alt+Delete")
+	m := NewManager(backend)
+	defer m.Close()
+	rec := newRecorder()
+
+	conflicts := m.Set([]Binding{
+		{Name: "Toggle", Hotkey: "Ctrl+Alt+T", Action: rec.action("toggle")},
+		{Name: "Typo", Hotkey: "Ctrl+Alt+Tee"},
+		{Name: "Plain", Hotkey: "T"},
+		{Name: "Terminal", Hotkey: "alt+ctrl+t"},
+		{Name: "Taken", Hotkey: "Ctrl+Alt+Del"},
+	})
+	want := []struct {
+		name, hotkey string
+		err          error
+	}{
+		{"Typo", "Ctrl+Alt+Tee", ErrInvalid},
+		{"Plain", "T", ErrInvalid},
+		{"Terminal", "Ctrl+Alt+T", ErrDuplicate},
+		{"Taken", "Ctrl+Alt+Delete", ErrInUse},
+	}
+	if len(conflicts) != len(want) {
+		t.Fatalf("conflicts:\n%s", FormatConflicts(conflicts))
+	}
+	for i, w := range want {
+		c := conflicts[i]
+		if c.Name != w.name || c.Hotkey != w.hotkey || !errors.Is(c, w.err) {
+			t.Errorf("conflict %d = %v, want %s (%s): %v", i, c, w.name, w.hotkey, w.err)
+		}
+	}
+	// 重复的热键提示占用者
+	if !strings.Contains(conflicts[2].Error(), "Toggle") {
+		t.Errorf("duplicate conflict %q does not name the owner", conflicts[2].Error())
+	}
+
+	// 冲突的绑定不影响其它热键
+	backend.press(t, "Ctrl+Alt+T")
+	rec.expect(t, "toggle")
+}
+
+func TestCheckMatchesSet(t *testing.T) {
+	bindings := []Binding{
+		{Name: "A", Hotkey: "Ctrl+Alt+A"},
+		{Name: "B", Hotkey: "ctrl+alt+a"},
+		{Name: "C", Hotkey: "Ctrl+Nope"},
+	}
+	m := NewManager(newFakeBackend())
+	defer m.Close()
+	if got, want := FormatConflicts(Check(bindings)), FormatConflicts(m.Set(bindings)); got != want {
+		t.Errorf("Check:\n%s\nSet:\n%s", got, want)
+	}
+}
+
+func TestFormatConflicts(t *testing.T) {
+	got := FormatConflicts([]Conflict{
+		{Name: "Toggle", Hotkey: "Ctrl+Alt+T", Err: ErrInUse},
+		{Name: "Dev", Hotkey: "Super+1", Err: ErrUnsupported},
+	})
+	want := "• Toggle (Ctrl+Alt+T): " + ErrInUse.Error() + "\n• Dev (Super+1): " + ErrUnsupported.Error()
+	if got != want {
+		t.Errorf("FormatConflicts = %q, want %q", got, want)
+	}
+}
+
+func TestManagerClose(t *testing.T) {
+	backend := newFakeBackend()
+	m := NewManager(backend)
+	m.Set([]Binding{{Name: "Toggle", Hotkey: "Ctrl+Alt+T", Action: func() {}}})
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if !backend.closed {
+		t.Error("Close did not close the backend")
+	}
+}
+
+// TestShortcutHotkeyConflicts follows the order the UI builds bindings in:
+// app-level hotkeys first, then one per shortcut, so a shortcut never takes
+// a hotkey from the window toggle or a group
+func TestShortcutHotkeyConflicts(t *testing.T) {
+	backend := newFakeBackend()
+	m := NewManager(backend)
+	defer m.Close()
+	rec := newRecorder()
+
+	conflicts := m.Set([]Binding{
+		{Name: "Show/hide window", Hotkey: "Ctrl+Alt+Space", Action: rec.action("toggle")},
+		{Name: "Group Dev", Hotkey: "Ctrl+Alt+D", Action: rec.action("group")},
+		{Name: "Shortcut Terminal", Hotkey: "Ctrl+Alt+T", Action: rec.action("terminal")},
+		{Name: "Shortcut Docs", Hotkey: "ctrl+alt+d", Action: rec.action("docs")},
+		{Name: "Shortcut Shell", Hotkey: "Alt+Ctrl+T", Action: rec.action("shell")},
+	})
+	want := []string{
+		"Shortcut Docs (Ctrl+Alt+D): " + ErrDuplicate.Error() + " (Group Dev)",
+		"Shortcut Shell (Ctrl+Alt+T): " + ErrDuplicate.Error() + " (Shortcut Terminal)",
+	}
+	if len(conflicts) != len(want) {
+		t.Fatalf("conflicts:\n%s", FormatConflicts(conflicts))
+	}
+	for i := range want {
+		if conflicts[i].Error() != want[i] {
+			t.Errorf("conflict %d = %q, want %q", i, conflicts[i].Error(), want[i])
+		}
+	}
+
+	backend.press(t, "Ctrl+Alt+D")
+	rec.expect(t, "group")
+	backend.press(t, "Ctrl+Alt+T")
+	rec.expect(t, "terminal")
+	rec.expectNone(t)
+}
@@ -0,0 +1,9 @@
+// Package instance keeps MuseTool to one running process per user. On Linux
+// and other Unix systems this is an flock on a lock file next to the IPC
+// socket; Windows uses a named mutex (see the ui package).
+package instance
+
+import "errors"
+
+// ErrLocked means another running instance holds the lock
+var ErrLocked = errors.New("single-instance lock is held by another process")
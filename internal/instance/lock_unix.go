@@ -0,0 +1,129 @@
+//go:build !windows
+
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"go-musetool/internal/logger"
+)
+
+// LockPath returns the per-user lock location, next to the IPC socket.
+// $XDG_RUNTIME_DIR is private to the user; the temp dir fallback includes
+// the uid to avoid collisions.
+func LockPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gomusetool.lock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gomusetool-%d.lock", os.Getuid()))
+}
+
+// Acquire locks path and records our PID in it. The kernel drops a
+// flock when its holder exits, even after a crash, so a busy lock whose
+// recorded PID no longer exists is stale: it is held by a process that
+// inherited the descriptor. Such a file is unlinked and a fresh one locked.
+// On filesystems without flock support the recorded PID alone decides.
+//
+// On success the caller owns the file and must keep it open (and reachable)
+// until Release; on error nothing is held.
+func Acquire(path string) (*os.File, error) {
+	const maxAttempts = 3
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, err
+		}
+		err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			// 另一个实例可能刚删除了残留的锁文件，此时锁住的是已被删除的文件
+			if !isLockFile(f, path) {
+				f.Close()
+				continue
+			}
+			// 已持有 flock：PID 只用于识别残留的锁，写入失败不影响加锁
+			if err := writeLockPID(f); err != nil {
+				logger.Error("Failed to record pid in %s: %v", path, err)
+			}
+			return f, nil
+		}
+
+		// PID 为空说明持有者刚加锁、还没写入 PID
+		pid := readLockPID(f)
+		stale := pid > 0 && pid != os.Getpid() && !processExists(pid)
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			f.Close()
+			if !stale {
+				return nil, ErrLocked
+			}
+			logger.Info("Removing stale single-instance lock (pid %d)", pid)
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			continue
+		}
+
+		// 文件系统不支持 flock（ENOLCK、EOPNOTSUPP 等），退回到 PID 检查
+		logger.Debug("flock unavailable on %s: %v", path, err)
+		if pid > 0 && pid != os.Getpid() && !stale {
+			f.Close()
+			return nil, ErrLocked
+		}
+		// 没有 flock 时锁只靠 PID，写入失败等于没有加锁
+		if err := writeLockPID(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return f, nil
+	}
+	return nil, ErrLocked
+}
+
+// isLockFile reports whether f is still the file at path
+func isLockFile(f *os.File, path string) bool {
+	opened, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	current, err := os.Stat(path)
+	return err == nil && os.SameFile(opened, current)
+}
+
+// Release unlinks the lock file before unlocking it, so that a starting
+// instance never locks a file that is about to disappear
+func Release(f *os.File) {
+	os.Remove(f.Name())
+	syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	f.Close()
+}
+
+func writeLockPID(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	return err
+}
+
+// readLockPID returns the PID recorded in the lock file, or 0
+func readLockPID(f *os.File) int {
+	data, err := io.ReadAll(io.NewSectionReader(f, 0, 32))
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
+
+// processExists reports whether pid is running; EPERM means it exists but
+// belongs to another user
+func processExists(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,148 @@
+//go:build !windows
+
+package instance
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// helperEnv makes the test binary act as a second instance: it locks the
+// path in the variable, prints "locked" and holds the lock until stdin closes
+const helperEnv = "MUSETOOL_LOCK_HELPER"
+
+func TestMain(m *testing.M) {
+	if path := os.Getenv(helperEnv); path != "" {
+		f, err := Acquire(path)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println("locked")
+		bufio.NewReader(os.Stdin).ReadString('\n')
+		Release(f)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// startHolder runs another process that holds the lock on path
+func startHolder(t *testing.T, path string) (*exec.Cmd, func()) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), helperEnv+"="+path)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	line, _ := bufio.NewReader(stdout).ReadString('\n')
+	if strings.TrimSpace(line) != "locked" {
+		cmd.Process.Kill()
+		cmd.Wait()
+		t.Fatalf("helper: %q", line)
+	}
+	release := func() {
+		stdin.Close()
+		cmd.Wait()
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	return cmd, release
+}
+
+func TestSecondProcessIsLockedOut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+	holder, release := startHolder(t, path)
+
+	if f, err := Acquire(path); !errors.Is(err, ErrLocked) {
+		if f != nil {
+			Release(f)
+		}
+		t.Fatalf("Acquire while another process holds the lock = %v, want %v", err, ErrLocked)
+	}
+	data, _ := os.ReadFile(path)
+	if pid, _ := strconv.Atoi(strings.TrimSpace(string(data))); pid != holder.Process.Pid {
+		t.Errorf("lock file records pid %q, want %d", data, holder.Process.Pid)
+	}
+
+	release()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("lock file still exists after Release: %v", err)
+	}
+	f, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire after the holder released the lock: %v", err)
+	}
+	Release(f)
+}
+
+func TestLockIsFreedWhenHolderCrashes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+	holder, _ := startHolder(t, path)
+
+	holder.Process.Kill()
+	holder.Wait()
+
+	f, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire after the holder was killed: %v", err)
+	}
+	defer Release(f)
+	data, _ := os.ReadFile(path)
+	if want := strconv.Itoa(os.Getpid()); strings.TrimSpace(string(data)) != want {
+		t.Errorf("lock file records pid %q, want %s", data, want)
+	}
+}
+
+// TestStaleLockIsReplaced covers a lock still held through a descriptor
+// inherited by a child, after the process that took it has exited
+func TestStaleLockIsReplaced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	// 一个已经退出的进程的 PID
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Skip("true not found")
+	}
+
+	stale, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stale.Close()
+	if err := syscall.Flock(int(stale.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		t.Skipf("flock unavailable: %v", err)
+	}
+	fmt.Fprintf(stale, "%d\n", exited.Process.Pid)
+
+	f, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire with a stale lock: %v", err)
+	}
+	defer Release(f)
+	if !isLockFile(f, path) {
+		t.Error("Acquire did not lock the file at path")
+	}
+
+	// 正在运行的进程（这里是自己）持有的锁不是残留的
+	if _, err := Acquire(path); !errors.Is(err, ErrLocked) {
+		t.Errorf("Acquire while this process holds the lock = %v, want %v", err, ErrLocked)
+	}
+}
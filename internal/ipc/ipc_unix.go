@@ -0,0 +1,76 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// socketPath returns the per-user socket location. $XDG_RUNTIME_DIR is private
+// to the user; the temp dir fallback includes the uid to avoid collisions.
+func socketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gomusetool.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gomusetool-%d.sock", os.Getuid()))
+}
+
+type unixListener struct {
+	l      net.Listener
+	closed atomic.Bool
+}
+
+func listen() (listener, error) {
+	path := socketPath()
+
+	// 崩溃后残留的 socket 文件会导致 Listen 失败，只有在无人应答时才删除它
+	if conn, err := net.DialTimeout("unix", path, dialTimeout); err == nil {
+		conn.Close()
+		return nil, errors.New("another instance is already listening on " + path)
+	}
+	os.Remove(path)
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+	return &unixListener{l: l}, nil
+}
+
+func (u *unixListener) Accept() (io.ReadWriteCloser, error) {
+	conn, err := u.l.Accept()
+	if err != nil {
+		if u.closed.Load() {
+			return nil, errListenerClosed
+		}
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(ioTimeout))
+	return conn, nil
+}
+
+// Close stops the listener; the socket file is unlinked by net.UnixListener
+func (u *unixListener) Close() error {
+	u.closed.Store(true)
+	return u.l.Close()
+}
+
+func dial() (io.ReadWriteCloser, error) {
+	conn, err := net.DialTimeout("unix", socketPath(), dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	conn.SetDeadline(time.Now().Add(ioTimeout))
+	return conn, nil
+}
@@ -0,0 +1,268 @@
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const pipeBufferSize = 4096
+
+// pipeName returns the per-session pipe. Pipe names are machine-wide, so the
+// user name and session ID keep several logged-in users, or one user signed in
+// twice, from talking to each other's instance.
+func pipeName() string {
+	var session uint32
+	windows.ProcessIdToSessionId(windows.GetCurrentProcessId(), &session)
+	return fmt.Sprintf(`\\.\pipe\GoMuseTool-%s-%d`, os.Getenv("USERNAME"), session)
+}
+
+// currentUserSecurity restricts the pipe to the current user. The default
+// pipe DACL would also grant read access to Everyone.
+func currentUserSecurity() (*windows.SecurityAttributes, error) {
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token user: %w", err)
+	}
+	sd, err := windows.SecurityDescriptorFromString("D:P(A;;GA;;;" + user.User.Sid.String() + ")")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pipe security descriptor: %w", err)
+	}
+	return &windows.SecurityAttributes{
+		Length:             uint32(unsafe.Sizeof(windows.SecurityAttributes{})),
+		SecurityDescriptor: sd,
+	}, nil
+}
+
+type pipeListener struct {
+	name   string
+	sa     *windows.SecurityAttributes
+	mu     sync.Mutex
+	next   windows.Handle // 预先创建、尚未连接的管道实例
+	closed atomic.Bool
+	done   windows.Handle // Close 时触发的事件，唤醒等待连接的 Accept
+}
+
+func listen() (listener, error) {
+	sa, err := currentUserSecurity()
+	if err != nil {
+		return nil, err
+	}
+	done, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	l := &pipeListener{name: pipeName(), sa: sa, done: done}
+
+	// FILE_FLAG_FIRST_PIPE_INSTANCE makes creation fail if another process
+	// already owns the pipe, so only the first instance becomes the server
+	h, err := l.createPipe(true)
+	if err != nil {
+		windows.CloseHandle(done)
+		return nil, fmt.Errorf("failed to create pipe %s: %w", l.name, err)
+	}
+	l.next = h
+	return l, nil
+}
+
+func (l *pipeListener) createPipe(first bool) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(l.name)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+	flags := uint32(windows.PIPE_ACCESS_DUPLEX | windows.FILE_FLAG_OVERLAPPED)
+	if first {
+		flags |= windows.FILE_FLAG_FIRST_PIPE_INSTANCE
+	}
+	mode := uint32(windows.PIPE_TYPE_BYTE | windows.PIPE_READMODE_BYTE | windows.PIPE_WAIT | windows.PIPE_REJECT_REMOTE_CLIENTS)
+	return windows.CreateNamedPipe(name, flags, mode, windows.PIPE_UNLIMITED_INSTANCES, pipeBufferSize, pipeBufferSize, 0, l.sa)
+}
+
+func (l *pipeListener) Accept() (io.ReadWriteCloser, error) {
+	if l.closed.Load() {
+		return nil, errListenerClosed
+	}
+
+	l.mu.Lock()
+	h := l.next
+	l.next = 0
+	l.mu.Unlock()
+
+	if h == 0 {
+		var err error
+		if h, err = l.createPipe(false); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := l.connect(h); err != nil {
+		windows.CloseHandle(h)
+		return nil, err
+	}
+	l.prepareNext()
+	return newPipeConn(h, true), nil
+}
+
+// prepareNext creates the next listening instance as soon as h is taken, so a
+// client dialing while this connection is served gets PIPE_BUSY at worst and
+// retries, instead of FILE_NOT_FOUND, which looks like no instance is running.
+func (l *pipeListener) prepareNext() {
+	h, err := l.createPipe(false)
+	if err != nil {
+		// 下一次 Accept 会重试
+		log.Printf("ipc: failed to create next pipe instance: %v", err)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed.Load() || l.next != 0 {
+		windows.CloseHandle(h)
+		return
+	}
+	l.next = h
+}
+
+// connect waits until a client connects to h or the listener is closed
+func (l *pipeListener) connect(h windows.Handle) error {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(event)
+	ov := windows.Overlapped{HEvent: event}
+
+	switch err := windows.ConnectNamedPipe(h, &ov); err {
+	case nil, windows.ERROR_PIPE_CONNECTED:
+		return nil
+	case windows.ERROR_IO_PENDING:
+	default:
+		return err
+	}
+
+	which, err := windows.WaitForMultipleObjects([]windows.Handle{event, l.done}, false, windows.INFINITE)
+	if err != nil {
+		return err
+	}
+	if which != windows.WAIT_OBJECT_0 {
+		windows.CancelIoEx(h, &ov)
+		var n uint32
+		windows.GetOverlappedResult(h, &ov, &n, true)
+		return errListenerClosed
+	}
+	var n uint32
+	return windows.GetOverlappedResult(h, &ov, &n, false)
+}
+
+// Close marks the listener closed and wakes up a blocked Accept
+func (l *pipeListener) Close() error {
+	if l.closed.Swap(true) {
+		return nil
+	}
+	windows.SetEvent(l.done)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.next != 0 {
+		windows.CloseHandle(l.next)
+		l.next = 0
+	}
+	return nil
+}
+
+// pipeConn adapts an overlapped pipe handle to io.ReadWriteCloser. Reads and
+// writes fail once ioTimeout has passed since the connection was made, so a
+// peer that stops talking cannot hold the connection forever.
+type pipeConn struct {
+	h        windows.Handle
+	server   bool
+	deadline time.Time
+}
+
+// errTimeout 读写超过 ioTimeout
+var errTimeout = errors.New("ipc i/o timeout")
+
+func newPipeConn(h windows.Handle, server bool) *pipeConn {
+	return &pipeConn{h: h, server: server, deadline: time.Now().Add(ioTimeout)}
+}
+
+// io starts an overlapped read or write and waits for it until the deadline
+func (c *pipeConn) io(start func(*windows.Overlapped) error) (int, error) {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(event)
+	ov := windows.Overlapped{HEvent: event}
+
+	var n uint32
+	err = start(&ov)
+	if err != nil && err != windows.ERROR_IO_PENDING {
+		return 0, err
+	}
+	if err == windows.ERROR_IO_PENDING {
+		wait := max(time.Until(c.deadline), 0)
+		if r, _ := windows.WaitForSingleObject(event, uint32(wait/time.Millisecond)); r != windows.WAIT_OBJECT_0 {
+			windows.CancelIoEx(c.h, &ov)
+			// 等待取消完成后才能释放 ov；取消前刚好完成的操作照常返回
+			if err := windows.GetOverlappedResult(c.h, &ov, &n, true); err != nil {
+				return int(n), errTimeout
+			}
+			return int(n), nil
+		}
+	}
+	err = windows.GetOverlappedResult(c.h, &ov, &n, false)
+	return int(n), err
+}
+
+func (c *pipeConn) Read(p []byte) (int, error) {
+	n, err := c.io(func(ov *windows.Overlapped) error { return windows.ReadFile(c.h, p, nil, ov) })
+	if err == windows.ERROR_BROKEN_PIPE {
+		return n, io.EOF
+	}
+	if err != nil && err != windows.ERROR_MORE_DATA {
+		return n, err
+	}
+	return n, nil
+}
+
+func (c *pipeConn) Write(p []byte) (int, error) {
+	return c.io(func(ov *windows.Overlapped) error { return windows.WriteFile(c.h, p, nil, ov) })
+}
+
+func (c *pipeConn) Close() error {
+	if c.server {
+		// 确保客户端读到完整响应后再断开
+		windows.FlushFileBuffers(c.h)
+		windows.DisconnectNamedPipe(c.h)
+	}
+	return windows.CloseHandle(c.h)
+}
+
+func dial() (io.ReadWriteCloser, error) {
+	name, err := windows.UTF16PtrFromString(pipeName())
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(dialTimeout)
+	for {
+		h, err := windows.CreateFile(name, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING, windows.FILE_FLAG_OVERLAPPED, 0)
+		if err == nil {
+			return newPipeConn(h, false), nil
+		}
+		// 所有实例都在忙（服务端正在创建下一个实例），稍后重试
+		if err == windows.ERROR_PIPE_BUSY && time.Now().Before(deadline) {
+			time.Sleep(50 * time.Millisecond)
+			continue
+		}
+		return nil, fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+}
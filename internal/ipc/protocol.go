@@ -0,0 +1,185 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ProtocolVersion is bumped whenever the request or response shape changes
+// incompatibly. Both sides refuse messages with a different version.
+const ProtocolVersion = 1
+
+// maxMessageSize bounds a single message so a misbehaving client cannot make
+// the running instance allocate without limit
+const maxMessageSize = 64 * 1024
+
+// Action identifies what a later invocation wants the running instance to do
+type Action string
+
+const (
+	ActionShow   Action = "show"   // 显示并激活主窗口
+	ActionAdd    Action = "add"    // Args: 要添加为快捷方式的文件绝对路径
+	ActionLaunch Action = "launch" // Args[0]: "Group/Name"
+	ActionGroup  Action = "group"  // Args[0]: 要切换到的分组名
+
+	ActionWorkspace Action = "workspace" // Args[0]: 要全部启动的分组名
+	ActionReload    Action = "reload"    // 配置文件被命令行修改，重新读取
+)
+
+var (
+	ErrNotRunning      = errors.New("no running instance")
+	ErrVersionMismatch = errors.New("ipc protocol version mismatch")
+	ErrUnknownAction   = errors.New("unknown ipc action")
+)
+
+// Request is sent by a second instance to the running one
+type Request struct {
+	Version int      `json:"version"`
+	Action  Action   `json:"action"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// Response acknowledges a Request
+type Response struct {
+	Version int    `json:"version"`
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NewRequest creates a request for the current protocol version
+func NewRequest(action Action, args ...string) Request {
+	return Request{Version: ProtocolVersion, Action: action, Args: args}
+}
+
+// Validate checks the version and the arguments required by the action
+func (r Request) Validate() error {
+	if r.Version != ProtocolVersion {
+		return fmt.Errorf("%w: got %d, want %d", ErrVersionMismatch, r.Version, ProtocolVersion)
+	}
+	switch r.Action {
+	case ActionShow, ActionReload:
+		return nil
+	case ActionAdd:
+		if len(r.Args) == 0 {
+			return fmt.Errorf("%s requires at least one path", r.Action)
+		}
+		return nil
+	case ActionLaunch, ActionGroup, ActionWorkspace:
+		if len(r.Args) != 1 || r.Args[0] == "" {
+			return fmt.Errorf("%s requires exactly one argument", r.Action)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownAction, r.Action)
+}
+
+// ParseArgs turns the command line of a later invocation into a request.
+//
+//	(no arguments)        -> show
+//	--show                -> show
+//	--group NAME          -> group
+//	--launch "Group/Name" -> launch
+//	--workspace NAME      -> workspace (launch every shortcut of the group)
+//	FILE...               -> add (paths are made absolute here, because the
+//	                         running instance has a different working directory)
+//
+// Unrecognised flags are ignored so that startup-only flags (--tray,
+// --minimized, --delay N) do not turn into file paths.
+func ParseArgs(args []string) (Request, error) {
+	var files []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--show":
+			continue
+		case "--delay":
+			i++ // 启动参数，值不是文件
+			continue
+		case "--group", "--launch", "--workspace":
+			if i+1 >= len(args) {
+				return Request{}, fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			switch arg {
+			case "--group":
+				return NewRequest(ActionGroup, args[i]), nil
+			case "--workspace":
+				return NewRequest(ActionWorkspace, args[i]), nil
+			}
+			return NewRequest(ActionLaunch, args[i]), nil
+		}
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		abs, err := filepath.Abs(arg)
+		if err != nil {
+			return Request{}, err
+		}
+		files = append(files, abs)
+	}
+
+	if len(files) > 0 {
+		return NewRequest(ActionAdd, files...), nil
+	}
+	return NewRequest(ActionShow), nil
+}
+
+// WriteRequest encodes a request as a single JSON line
+func WriteRequest(w io.Writer, req Request) error {
+	return writeMessage(w, req)
+}
+
+// ReadRequest decodes and validates a single request
+func ReadRequest(r io.Reader) (Request, error) {
+	var req Request
+	if err := readMessage(r, &req); err != nil {
+		return Request{}, err
+	}
+	return req, req.Validate()
+}
+
+// WriteResponse encodes a response as a single JSON line
+func WriteResponse(w io.Writer, resp Response) error {
+	resp.Version = ProtocolVersion
+	return writeMessage(w, resp)
+}
+
+// ReadResponse decodes a response and converts a failure into an error
+func ReadResponse(r io.Reader) error {
+	var resp Response
+	if err := readMessage(r, &resp); err != nil {
+		return err
+	}
+	if resp.Version != ProtocolVersion {
+		return fmt.Errorf("%w: got %d, want %d", ErrVersionMismatch, resp.Version, ProtocolVersion)
+	}
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+func writeMessage(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func readMessage(r io.Reader, v interface{}) error {
+	line, err := bufio.NewReader(io.LimitReader(r, maxMessageSize)).ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		return fmt.Errorf("failed to read ipc message: %w", err)
+	}
+	if err := json.Unmarshal(line, v); err != nil {
+		return fmt.Errorf("failed to decode ipc message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package ipc
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRequestRoundTrip(t *testing.T) {
+	requests := []Request{
+		NewRequest(ActionShow),
+		NewRequest(ActionReload),
+		NewRequest(ActionAdd, "/tmp/a.txt", "/tmp/b c.txt"),
+		NewRequest(ActionLaunch, "开发/终端"),
+		NewRequest(ActionGroup, "Dev"),
+		NewRequest(ActionWorkspace, "Morning"),
+	}
+	for _, want := range requests {
+		client, server := net.Pipe()
+		go func() {
+			defer client.Close()
+			WriteRequest(client, want)
+		}()
+		got, err := ReadRequest(server)
+		server.Close()
+		if err != nil {
+			t.Fatalf("ReadRequest(%v): %v", want.Action, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestResponseRoundTrip(t *testing.T) {
+	tests := []struct {
+		resp    Response
+		wantErr string
+	}{
+		{Response{OK: true}, ""},
+		{Response{OK: false, Error: "group not found: Dev"}, "group not found: Dev"},
+	}
+	for _, tt := range tests {
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			WriteResponse(server, tt.resp)
+		}()
+		err := ReadResponse(client)
+		client.Close()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("ReadResponse(%+v) = %v, want nil", tt.resp, err)
+		}
+		if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("ReadResponse(%+v) = %v, want %q", tt.resp, err, tt.wantErr)
+		}
+	}
+}
+
+func TestReadRequestRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    error // nil 表示只要求出错
+	}{
+		{"old version", `{"version":0,"action":"show"}`, ErrVersionMismatch},
+		{"unknown action", `{"version":1,"action":"explode"}`, ErrUnknownAction},
+		{"launch without target", `{"version":1,"action":"launch"}`, nil},
+		{"add without paths", `{"version":1,"action":"add"}`, nil},
+		{"not json", `hello`, nil},
+		{"too large", `{"version":1,"action":"add","args":["` + strings.Repeat("x", maxMessageSize) + `"]}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadRequest(strings.NewReader(tt.message + "\n"))
+			if err == nil {
+				t.Fatal("ReadRequest succeeded")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("ReadRequest = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadResponseVersionMismatch(t *testing.T) {
+	err := ReadResponse(strings.NewReader(`{"version":2,"ok":true}` + "\n"))
+	if !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("ReadResponse = %v, want %v", err, ErrVersionMismatch)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	abs, _ := filepath.Abs("notes.txt")
+	tests := []struct {
+		args []string
+		want Request
+	}{
+		{nil, NewRequest(ActionShow)},
+		{[]string{"--show"}, NewRequest(ActionShow)},
+		{[]string{"--tray", "--delay", "5"}, NewRequest(ActionShow)},
+		{[]string{"--group", "Dev"}, NewRequest(ActionGroup, "Dev")},
+		{[]string{"--launch", "Dev/Terminal"}, NewRequest(ActionLaunch, "Dev/Terminal")},
+		{[]string{"--workspace", "Morning"}, NewRequest(ActionWorkspace, "Morning")},
+		{[]string{"--minimized", "notes.txt"}, NewRequest(ActionAdd, abs)},
+	}
+	for _, tt := range tests {
+		got, err := ParseArgs(tt.args)
+		if err != nil {
+			t.Errorf("ParseArgs(%q): %v", tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+		}
+	}
+
+	if _, err := ParseArgs([]string{"--launch"}); err == nil {
+		t.Error("ParseArgs(--launch) without a value succeeded")
+	}
+}
@@ -0,0 +1,125 @@
+package ipc
+
+import (
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	dialTimeout = 2 * time.Second  // 等待运行中实例接受连接的时间
+	ioTimeout   = 10 * time.Second // 单次请求/响应的读写超时
+)
+
+var errListenerClosed = errors.New("ipc listener closed")
+
+// Handler executes a request received from another instance. The returned
+// error is reported back to the sender.
+type Handler func(Request) error
+
+// Server accepts requests from later invocations on the platform channel
+// (a named pipe on Windows, a Unix domain socket elsewhere).
+type Server struct {
+	listener listener
+	handler  Handler
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+// listener is implemented per platform
+type listener interface {
+	Accept() (io.ReadWriteCloser, error)
+	Close() error
+}
+
+// Listen creates the channel owned by the first instance and starts serving
+// requests in the background.
+func Listen(handler Handler) (*Server, error) {
+	l, err := listen()
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{listener: l, handler: handler}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Close stops accepting requests and removes the channel
+func (s *Server) Close() error {
+	var err error
+	s.once.Do(func() {
+		err = s.listener.Close()
+		s.wg.Wait()
+	})
+	return err
+}
+
+// acceptRetryDelay 接受连接失败后等待多久再重试
+const acceptRetryDelay = 100 * time.Millisecond
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err == errListenerClosed {
+			return
+		}
+		if err != nil {
+			// 暂时性错误（例如创建管道实例失败）不能让之后的实例再也连不上
+			log.Printf("ipc: accept failed: %v", err)
+			time.Sleep(acceptRetryDelay)
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle serves a single connection on its own goroutine, so a client that
+// connects and never writes only holds its own connection until ioTimeout.
+// The handler still runs requests one at a time on the UI thread.
+func (s *Server) handle(conn io.ReadWriteCloser) {
+	defer conn.Close()
+
+	req, err := ReadRequest(conn)
+	if errors.Is(err, io.EOF) {
+		// 连接后未发送任何内容（例如探测是否已有实例在监听）
+		return
+	}
+	if err == nil {
+		err = s.handler(req)
+	}
+
+	resp := Response{OK: err == nil}
+	if err != nil {
+		resp.Error = err.Error()
+		log.Printf("ipc: request %q failed: %v", req.Action, err)
+	}
+	if err := WriteResponse(conn, resp); err != nil {
+		log.Printf("ipc: failed to write response: %v", err)
+	}
+}
+
+// Send forwards a request to the running instance and waits for its answer.
+// ErrNotRunning is returned when no instance is listening.
+func Send(req Request) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	conn, err := dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := WriteRequest(conn, req); err != nil {
+		return err
+	}
+	return ReadResponse(conn)
+}
@@ -0,0 +1,133 @@
+package ipc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeListener hands out the server ends of net.Pipe connections
+type fakeListener struct {
+	conns  chan io.ReadWriteCloser
+	errs   chan error
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newFakeListener() *fakeListener {
+	return &fakeListener{conns: make(chan io.ReadWriteCloser), errs: make(chan error, 1), closed: make(chan struct{})}
+}
+
+func (l *fakeListener) Accept() (io.ReadWriteCloser, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case err := <-l.errs:
+		return nil, err
+	case <-l.closed:
+		return nil, errListenerClosed
+	}
+}
+
+func (l *fakeListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+// dial connects a new client to the server
+func (l *fakeListener) dial() net.Conn {
+	client, server := net.Pipe()
+	l.conns <- server
+	return client
+}
+
+func startServer(l listener, handler Handler) *Server {
+	s := &Server{listener: l, handler: handler}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+func send(conn net.Conn, req Request) error {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if err := WriteRequest(conn, req); err != nil {
+		return err
+	}
+	return ReadResponse(conn)
+}
+
+func TestServerHandlesRequests(t *testing.T) {
+	l := newFakeListener()
+	var got []Request
+	var mu sync.Mutex
+	s := startServer(l, func(req Request) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, req)
+		if req.Action == ActionGroup {
+			return errors.New("group not found: Nope")
+		}
+		return nil
+	})
+	defer s.Close()
+
+	if err := send(l.dial(), NewRequest(ActionLaunch, "Dev/Terminal")); err != nil {
+		t.Errorf("launch: %v", err)
+	}
+	if err := send(l.dial(), NewRequest(ActionGroup, "Nope")); err == nil || err.Error() != "group not found: Nope" {
+		t.Errorf("group: %v, want the handler's error", err)
+	}
+	if err := send(l.dial(), Request{Version: 99, Action: ActionShow}); err == nil {
+		t.Error("request with another version succeeded")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 2 {
+		t.Errorf("handler ran for %d requests, want 2 (invalid requests are rejected before it)", len(got))
+	}
+}
+
+func TestStalledClientDoesNotBlockOthers(t *testing.T) {
+	l := newFakeListener()
+	s := startServer(l, func(Request) error { return nil })
+
+	// 连接后一直不发送
+	stalled := l.dial()
+
+	done := make(chan error, 1)
+	go func() { done <- send(l.dial(), NewRequest(ActionShow)) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("second client: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("second client was blocked by the stalled one")
+	}
+
+	stalled.Close()
+	s.Close()
+}
+
+func TestServerSurvivesAcceptErrors(t *testing.T) {
+	l := newFakeListener()
+	s := startServer(l, func(Request) error { return nil })
+	defer s.Close()
+
+	l.errs <- errors.New("transient failure")
+	done := make(chan error, 1)
+	go func() { done <- send(l.dial(), NewRequest(ActionShow)) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("request after accept error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("server stopped after a failed Accept")
+	}
+}
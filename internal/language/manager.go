@@ -13,7 +13,8 @@ var languageFiles embed.FS
 // Translations holds all translatable strings
 type Translations struct {
 	// Window
-	WindowTitle string
+	WindowTitle    string
+	AlreadyRunning string
 
 	// Toolbar
 	ToolbarAddShortcut string
@@ -32,6 +33,10 @@ type Translations struct {
 	SettingsLanguage             string
 	SettingsDebugLog             string
 	SettingsAutoStart            string
+	SettingsAutoStartLogin       string
+	SettingsStartHidden          string
+	SettingsStartDelay           string
+	SettingsStartDelayInvalid    string
 	SettingsMinimizeToTray       string
 	SettingsResetCloseDialog     string
 	SettingsResetCloseDialogDesc string
@@ -42,10 +47,48 @@ type Translations struct {
 	SettingsImportSuccess        string
 	SettingsImportSuccessRestart string
 	SettingsImportError          string
+	SettingsAPITitle             string
+	SettingsAPIEnable            string
+	SettingsAPIPort              string
+	SettingsAPIPortInvalid       string
+	SettingsAPICopyToken         string
+	SettingsAPITokenCopied       string
+	SettingsURLHandler           string
 	SettingsSave                 string
 	SettingsCancel               string
 	SettingsClose                string
 
+	// Global hotkeys
+	SettingsHotkeysTitle string
+	SettingsHotkeyToggle string
+	SettingsHotkeySearch string
+	SettingsHotkeyGroup  string
+	SettingsHotkeyHint   string
+	HotkeyInvalid        string
+	HotkeyConflicts      string
+	ShortcutHotkey       string
+	HotkeyShortcut       string
+	HotkeyTaken          string
+
+	// Edge docking
+	SettingsDockTitle        string
+	SettingsDockEnable       string
+	SettingsDockAutoHide     string
+	SettingsDockHideDelay    string
+	SettingsDockShowDelay    string
+	SettingsDockRevealZone   string
+	SettingsDockReveal       string
+	SettingsDockRevealHover  string
+	SettingsDockRevealHotkey string
+	SettingsDockNoEdges      string
+	SettingsDockValueInvalid string
+
+	// Always on top
+	SettingsAlwaysOnTop   string
+	SettingsTopmostAlways string
+	SettingsTopmostDocked string
+	SettingsTopmostNever  string
+
 	// Theme Options
 	ThemeSystem string
 	ThemeLight  string
@@ -74,31 +117,141 @@ type Translations struct {
 	GroupNameEmpty     string
 
 	// Shortcut Management
-	ShortcutEdit          string
-	ShortcutDelete        string
-	ShortcutDeleteTitle   string
-	ShortcutDeleteConfirm string
-	ShortcutMoveTo        string
-	ShortcutAdd           string
-	ShortcutAddTitle      string
-	ShortcutEditTitle     string
-	ShortcutName          string
-	ShortcutPath          string
-	ShortcutIcon          string
-	ShortcutBrowse        string
-	ShortcutBrowseExe     string
-	ShortcutBrowseIcon    string
-	ShortcutSave          string
-	ShortcutCancel        string
-	ShortcutNameRequired  string
+	ShortcutEdit           string
+	ShortcutDelete         string
+	ShortcutDeleteTitle    string
+	ShortcutDeleteConfirm  string
+	ShortcutMoveTo         string
+	ShortcutAdd            string
+	ShortcutAddTitle       string
+	ShortcutEditTitle      string
+	ShortcutName           string
+	ShortcutPath           string
+	ShortcutIcon           string
+	ShortcutBrowse         string
+	ShortcutBrowseExe      string
+	ShortcutBrowseIcon     string
+	ShortcutKind           string
+	ShortcutArgs           string
+	ShortcutWorkDir        string
+	ShortcutFocusExisting  string
+	ShortcutCommand        string
+	ShortcutSnippetText    string
+	ShortcutBrowseFolder   string
+	KindApplication        string
+	KindFile               string
+	KindFolder             string
+	KindURL                string
+	KindCommand            string
+	KindSnippet            string
+	KindMacro              string
+	MacroSteps             string
+	MacroAddStep           string
+	MacroStepLaunch        string
+	MacroStepCommand       string
+	MacroStepWait          string
+	MacroStepWaitProcess   string
+	MacroStepOpenURL       string
+	MacroTargetLaunch      string
+	MacroTargetProcess     string
+	MacroSeconds           string
+	MacroOnFailureStop     string
+	MacroOnFailureContinue string
+	MacroRunningTitle      string
+	MacroStepProgress      string
+	MacroFailed            string
+	MacroCancelled         string
+
+	// Schedule
+	ShortcutSchedule        string
+	ShortcutScheduleCron    string
+	ShortcutScheduleAtStart string
+	ShortcutScheduleCatchUp string
+
+	// Terminal
+	ShortcutRunInTerminal string
+	ShortcutKeepOpen      string
+	SettingsTerminal      string
+	SettingsTerminalAuto  string
+
+	// Open Rules
+	RoutesTitle    string
+	RoutesHint     string
+	RoutesScheme   string
+	RoutesHost     string
+	RoutesExt      string
+	RoutesCommand  string
+	RoutesTerminal string
+	RoutesAdd      string
+	RoutesInvalid  string
+
+	// Snippets
+	SnippetCopied             string
+	SnippetPasteFailed        string
+	SnippetsExported          string
+	SnippetsImported          string
+	ContextMenuExportSnippets string
+	ContextMenuImportSnippets string
+	ShortcutAutoPaste         string
+	ShortcutPlaceholderHint   string
+	SearchPlaceholder         string
+	SearchNoResults           string
+
+	// Web Search
+	KindSearch                 string
+	ShortcutKeyword            string
+	ShortcutSearchHint         string
+	SearchKeywordTaken         string
+	SettingsAddDefaultSearches string
+	SettingsImportOpenSearch   string
+	SearchesAdded              string
+	SearchGroupName            string
+
+	// Workspace
+	WorkspaceSettingsTitle string
+	WorkspaceShortcuts     string
+	WorkspaceDelay         string
+	WorkspaceConcurrency   string
+	WorkspaceInvalidNumber string
+	WorkspaceEmpty         string
+	WorkspaceFailed        string
+
+	// Launch history
+	TabRecent                   string
+	TabMostUsed                 string
+	HistoryEmpty                string
+	TooltipLastLaunched         string
+	TooltipNeverLaunched        string
+	ContextMenuShowInGroup      string
+	SettingsHistoryTitle        string
+	SettingsHistoryEnable       string
+	SettingsHistoryRetention    string
+	SettingsHistoryForever      string
+	SettingsHistoryDays         string
+	SettingsSortByUsage         string
+	SettingsHistoryExport       string
+	SettingsHistoryClear        string
+	SettingsHistoryClearConfirm string
+	SettingsHistoryCleared      string
+
+	// Input prompt
+	InputPromptTitle     string
+	InputPromptLaunch    string
+	ShortcutSave         string
+	ShortcutCancel       string
+	ShortcutNameRequired string
 
 	// Context Menu
-	ContextMenuNewGroup     string
-	ContextMenuRenameGroup  string
-	ContextMenuDeleteGroup  string
-	ContextMenuMoveLeft     string
-	ContextMenuMoveRight    string
-	ContextMenuOpenLocation string
+	ContextMenuNewGroup          string
+	ContextMenuRenameGroup       string
+	ContextMenuDeleteGroup       string
+	ContextMenuLaunchAll         string
+	ContextMenuWorkspaceSettings string
+	ContextMenuMoveLeft          string
+	ContextMenuMoveRight         string
+	ContextMenuOpenLocation      string
+	ContextMenuCopyLink          string
+	ContextMenuCopyCommand       string
 
 	// Common
 	Error   string
@@ -111,8 +264,11 @@ type Translations struct {
 	Browse  string
 
 	// Tray
-	TrayShow string
-	TrayExit string
+	TrayShow      string
+	TrayExit      string
+	TrayLaunchAll string
+	TrayRecent    string
+	TraySettings  string
 
 	// Close Dialog
 	CloseDialogTitle    string
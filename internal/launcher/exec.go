@@ -5,7 +5,12 @@ import (
 	"runtime"
 )
 
+// Open opens a URL or path with the matching route from SetRoutes, or with
+// the OS default handler when no route matches
 func Open(path string) error {
+	if routed, err := openRouted(path); routed {
+		return err
+	}
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
 	case "windows":
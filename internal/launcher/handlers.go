@@ -0,0 +1,123 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"go-musetool/internal/model"
+)
+
+var ErrUnsupportedKind = errors.New("no handler for shortcut kind")
+
+// Handler launches shortcuts of one kind
+type Handler func(s model.Shortcut) error
+
+var (
+	handlersMu sync.RWMutex
+	handlers   = map[string]Handler{
+		model.KindApplication: launchApplication,
+		model.KindFile:        openPath,
+		model.KindFolder:      openPath,
+		model.KindURL:         openPath,
+		model.KindSearch:      openPath,
+		model.KindCommand:     runCommand,
+	}
+)
+
+// RegisterHandler installs (or replaces) the handler for a kind. The UI uses
+// this for kinds that need it, e.g. snippets need the clipboard.
+func RegisterHandler(kind string, h Handler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	handlers[kind] = h
+}
+
+// Launch dispatches the shortcut to the handler registered for its kind
+func Launch(s model.Shortcut) error {
+	if err := Validate(s); err != nil {
+		return err
+	}
+	kind := KindOf(s)
+	handlersMu.RLock()
+	h, ok := handlers[kind]
+	handlersMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedKind, kind)
+	}
+	return h(s)
+}
+
+func openPath(s model.Shortcut) error {
+	return Open(s.Path)
+}
+
+// launchApplication starts the program directly when arguments or a working
+// directory are set; otherwise the OS handler resolves .lnk/.desktop files
+func launchApplication(s model.Shortcut) error {
+	if s.RunInTerminal {
+		return runInTerminal(append([]string{s.Path}, SplitArgs(s.Args)...), s.WorkDir, s.KeepOpen)
+	}
+	if s.Args == "" && s.WorkDir == "" {
+		return Open(s.Path)
+	}
+	cmd := exec.Command(s.Path, SplitArgs(s.Args)...)
+	cmd.Dir = s.WorkDir
+	return cmd.Start()
+}
+
+func runCommand(s model.Shortcut) error {
+	if s.RunInTerminal {
+		return runInTerminal(shellArgs(s.Command), s.WorkDir, s.KeepOpen)
+	}
+	cmd := shellCommand(s.Command)
+	cmd.Dir = s.WorkDir
+	return cmd.Start()
+}
+
+// shellArgs runs a command line through the system shell in a terminal
+func shellArgs(command string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{windowsShell, "/C", command}
+	}
+	return []string{"sh", "-c", command}
+}
+
+// SplitArgs splits a command line into arguments. Double and single quotes
+// group words; quotes are removed. Backslashes are kept literally so Windows
+// paths survive.
+func SplitArgs(line string) []string {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args
+}
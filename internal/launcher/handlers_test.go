@@ -0,0 +1,112 @@
+package launcher
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go-musetool/internal/model"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"-a -b", []string{"-a", "-b"}},
+		{"  -a\t-b\n-c  ", []string{"-a", "-b", "-c"}},
+		{`--name "My Project" -v`, []string{"--name", "My Project", "-v"}},
+		{`--title 'a "quoted" word'`, []string{"--title", `a "quoted" word`}},
+		{`"it's"`, []string{"it's"}},
+		{`--dir="C:\Program Files\App"`, []string{`--dir=C:\Program Files\App`}}, // 反斜杠按原样保留
+		{`a""b`, []string{"ab"}},
+		{`"" x`, []string{"", "x"}}, // 空引号是一个空参数
+		{`"unterminated arg`, []string{"unterminated arg"}},
+	}
+	for _, tt := range tests {
+		if got := SplitArgs(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitArgs(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+// stubHandler replaces the handler for kind for the duration of the test and
+// records the shortcuts it receives
+func stubHandler(t *testing.T, kind string) *[]model.Shortcut {
+	t.Helper()
+	var calls []model.Shortcut
+	handlersMu.Lock()
+	old, had := handlers[kind]
+	handlersMu.Unlock()
+	RegisterHandler(kind, func(s model.Shortcut) error {
+		calls = append(calls, s)
+		return nil
+	})
+	t.Cleanup(func() {
+		handlersMu.Lock()
+		defer handlersMu.Unlock()
+		if had {
+			handlers[kind] = old
+		} else {
+			delete(handlers, kind)
+		}
+	})
+	return &calls
+}
+
+func TestLaunch(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        model.Shortcut
+		wantKind string // 应调用的处理程序，为空表示不调用
+		wantErr  error
+	}{
+		{"url", model.Shortcut{Kind: model.KindURL, Path: "https://go.dev"}, model.KindURL, nil},
+		{"detected url", model.Shortcut{Path: "https://go.dev"}, model.KindURL, nil},
+		{"command", model.Shortcut{Kind: model.KindCommand, Command: "make"}, model.KindCommand, nil},
+		{"snippet", model.Shortcut{Kind: model.KindSnippet, Text: "hi"}, model.KindSnippet, nil},
+		{"invalid", model.Shortcut{Kind: model.KindCommand}, "", ErrMissingTarget},
+		{"bad search", model.Shortcut{Kind: model.KindSearch, Path: "https://go.dev"}, "", ErrMissingQuery},
+		{"unknown kind", model.Shortcut{Kind: "bogus", Path: "x"}, "", ErrUnsupportedKind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := map[string]*[]model.Shortcut{}
+			for _, kind := range []string{model.KindURL, model.KindCommand, model.KindSnippet} {
+				calls[kind] = stubHandler(t, kind)
+			}
+			if err := Launch(tt.s); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Launch = %v, want %v", err, tt.wantErr)
+			}
+			for kind, got := range calls {
+				want := 0
+				if kind == tt.wantKind {
+					want = 1
+				}
+				if len(*got) != want {
+					t.Errorf("%s handler called %d times, want %d", kind, len(*got), want)
+				}
+			}
+		})
+	}
+}
+
+func TestLaunchUnregisteredKind(t *testing.T) {
+	// 片段的处理程序由 UI 注册；没有注册时报告 ErrUnsupportedKind
+	handlersMu.Lock()
+	old, had := handlers[model.KindSnippet]
+	delete(handlers, model.KindSnippet)
+	handlersMu.Unlock()
+	t.Cleanup(func() {
+		if had {
+			RegisterHandler(model.KindSnippet, old)
+		}
+	})
+
+	err := Launch(model.Shortcut{Kind: model.KindSnippet, Text: "hi"})
+	if !errors.Is(err, ErrUnsupportedKind) {
+		t.Errorf("Launch = %v, want %v", err, ErrUnsupportedKind)
+	}
+}
@@ -0,0 +1,95 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"go-musetool/internal/model"
+)
+
+var (
+	ErrMissingTarget = errors.New("shortcut has nothing to launch")
+	ErrMissingQuery  = errors.New("search URL has no {query} placeholder")
+)
+
+// applicationExts are treated as programs rather than documents
+var applicationExts = map[string]bool{
+	".exe": true, ".lnk": true, ".bat": true, ".cmd": true, ".com": true,
+	".msi": true, ".ps1": true, ".appimage": true, ".desktop": true, ".sh": true,
+}
+
+// DetectKind guesses the kind of a dropped or browsed path
+func DetectKind(path string) string {
+	if isURL(path) {
+		if IsSearchTemplate(path) {
+			return model.KindSearch
+		}
+		return model.KindURL
+	}
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		return model.KindFolder
+	}
+	if applicationExts[strings.ToLower(filepath.Ext(path))] {
+		return model.KindApplication
+	}
+	// Linux 上没有扩展名的可执行文件
+	if err == nil && runtime.GOOS != "windows" && info.Mode()&0111 != 0 {
+		return model.KindApplication
+	}
+	return model.KindFile
+}
+
+// KindOf returns the shortcut's kind, detecting it for configs written
+// before kinds existed
+func KindOf(s model.Shortcut) string {
+	if s.Kind != "" {
+		return s.Kind
+	}
+	return DetectKind(s.Path)
+}
+
+// Target returns the payload field that the shortcut's kind launches
+func Target(s model.Shortcut) string {
+	switch KindOf(s) {
+	case model.KindCommand:
+		return s.Command
+	case model.KindSnippet:
+		return s.Text
+	case model.KindMacro:
+		return fmt.Sprintf("%d steps", len(s.Steps))
+	}
+	return s.Path
+}
+
+// Validate checks that the payload required by the shortcut's kind is present
+func Validate(s model.Shortcut) error {
+	if KindOf(s) == model.KindMacro {
+		if len(s.Steps) == 0 {
+			return ErrMissingTarget
+		}
+		return nil
+	}
+	if strings.TrimSpace(Target(s)) == "" {
+		return ErrMissingTarget
+	}
+	if KindOf(s) == model.KindSearch && !IsSearchTemplate(s.Path) {
+		return ErrMissingQuery
+	}
+	return nil
+}
+
+// isURL reports whether path has a URL scheme. Single-letter schemes are
+// Windows drive letters ("C:\...").
+func isURL(path string) bool {
+	u, err := url.Parse(path)
+	if err != nil || len(u.Scheme) < 2 {
+		return false
+	}
+	return u.Scheme != "file" || u.Host != ""
+}
@@ -0,0 +1,93 @@
+package launcher
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"go-musetool/internal/model"
+)
+
+func TestDetectKind(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "run")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	doc := filepath.Join(dir, "notes")
+	if err := os.WriteFile(doc, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, want string
+	}{
+		{"https://go.dev", model.KindURL},
+		{"mailto:me@example.com", model.KindURL},
+		{"steam://run/440", model.KindURL},
+		{"file://server/share/a.txt", model.KindURL},
+		{"file:///tmp/a.txt", model.KindFile}, // 本地文件 URL 按文件处理
+		{"https://www.google.com/search?q={query}", model.KindSearch},
+		{"https://example.com/?q={searchTerms}", model.KindSearch},
+		{`C:\Windows\notepad.exe`, model.KindApplication}, // 盘符不是 URL 协议
+		{`C:\Users\me\report.docx`, model.KindFile},
+		{"D:/Games/Game.LNK", model.KindApplication},
+		{"/opt/Tool.AppImage", model.KindApplication},
+		{"/usr/share/applications/app.desktop", model.KindApplication},
+		{"/missing/readme.txt", model.KindFile},
+		{dir, model.KindFolder},
+		{doc, model.KindFile},
+	}
+	if runtime.GOOS != "windows" {
+		tests = append(tests, struct{ path, want string }{script, model.KindApplication})
+	}
+	for _, tt := range tests {
+		if got := DetectKind(tt.path); got != tt.want {
+			t.Errorf("DetectKind(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		s    model.Shortcut
+		want string
+	}{
+		{model.Shortcut{Kind: model.KindCommand, Path: "https://go.dev"}, model.KindCommand},
+		{model.Shortcut{Kind: model.KindSnippet}, model.KindSnippet},
+		{model.Shortcut{Path: "https://go.dev"}, model.KindURL}, // 旧配置没有 Kind
+		{model.Shortcut{Path: `C:\Tools\app.exe`}, model.KindApplication},
+	}
+	for _, tt := range tests {
+		if got := KindOf(tt.s); got != tt.want {
+			t.Errorf("KindOf(%+v) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		s    model.Shortcut
+		want error
+	}{
+		{"application", model.Shortcut{Kind: model.KindApplication, Path: "/usr/bin/app"}, nil},
+		{"empty path", model.Shortcut{Kind: model.KindApplication}, ErrMissingTarget},
+		{"command", model.Shortcut{Kind: model.KindCommand, Command: "make"}, nil},
+		{"blank command", model.Shortcut{Kind: model.KindCommand, Path: "/bin/sh", Command: "  "}, ErrMissingTarget},
+		{"snippet", model.Shortcut{Kind: model.KindSnippet, Text: "hello"}, nil},
+		{"empty snippet", model.Shortcut{Kind: model.KindSnippet, Path: "x"}, ErrMissingTarget},
+		{"macro", model.Shortcut{Kind: model.KindMacro, Steps: []model.MacroStep{{Type: "wait", Seconds: 1}}}, nil},
+		{"empty macro", model.Shortcut{Kind: model.KindMacro}, ErrMissingTarget},
+		{"search", model.Shortcut{Kind: model.KindSearch, Path: "https://duckduckgo.com/?q={query}"}, nil},
+		{"search without query", model.Shortcut{Kind: model.KindSearch, Path: "https://duckduckgo.com/"}, ErrMissingQuery},
+		{"detected search", model.Shortcut{Path: "https://duckduckgo.com/?q={query}"}, nil},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.s); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Validate = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
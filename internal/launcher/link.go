@@ -0,0 +1,76 @@
+package launcher
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go-musetool/internal/desktopentry"
+)
+
+var ErrUnresolvedLink = errors.New("cannot resolve link target")
+
+// ResolveLink returns the file a Windows shortcut (.lnk) or desktop entry
+// (.desktop) points to. Other paths are returned unchanged.
+func ResolveLink(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".lnk":
+		target, err := resolveLnk(path)
+		if err != nil {
+			return "", fmt.Errorf("%w %s: %v", ErrUnresolvedLink, path, err)
+		}
+		if target == "" {
+			// 指向"控制面板"等虚拟位置的快捷方式没有文件路径
+			return "", fmt.Errorf("%w %s", ErrUnresolvedLink, path)
+		}
+		return target, nil
+	case ".desktop":
+		target, err := desktopExec(path)
+		if err != nil {
+			return "", fmt.Errorf("%w %s: %v", ErrUnresolvedLink, path, err)
+		}
+		return target, nil
+	}
+	return path, nil
+}
+
+// desktopExec returns the program started by the Exec key of a desktop
+// entry, looked up on PATH when it is not absolute
+func desktopExec(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	inEntry := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			// 只读取主条目，跳过 [Desktop Action ...]
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		value, ok := strings.CutPrefix(line, "Exec=")
+		if !inEntry || !ok {
+			continue
+		}
+		args := desktopentry.ParseExec(value)
+		if len(args) == 0 {
+			break
+		}
+		if filepath.IsAbs(args[0]) {
+			return args[0], nil
+		}
+		return exec.LookPath(args[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", errors.New("no Exec key")
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package launcher
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveDesktopLink(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"quoted", "[Desktop Entry]\nExec=\"/opt/My App/bin/app\" --new-window %U\n", "/opt/My App/bin/app"},
+		{"escaped", "[Desktop Entry]\nExec=\"/opt/a\\\\\\\\b/app\"\n", `/opt/a\b/app`},
+		{"action first", "[Desktop Action New]\nExec=/usr/bin/other\n[Desktop Entry]\nExec=/usr/bin/app %f\n", "/usr/bin/app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.desktop")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := ResolveLink(path); err != nil || got != tt.want {
+				t.Errorf("ResolveLink = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "empty.desktop")
+	os.WriteFile(path, []byte("[Desktop Entry]\nName=Empty\n"), 0644)
+	if _, err := ResolveLink(path); !errors.Is(err, ErrUnresolvedLink) {
+		t.Errorf("ResolveLink without Exec = %v, want %v", err, ErrUnresolvedLink)
+	}
+}
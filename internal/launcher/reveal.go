@@ -0,0 +1,18 @@
+package launcher
+
+import (
+	"os"
+)
+
+// Reveal opens the file manager at the item path points to and selects it.
+// Shortcuts (.lnk, .desktop) reveal their target rather than themselves.
+func Reveal(path string) error {
+	target, err := ResolveLink(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(target); err != nil {
+		return err
+	}
+	return reveal(target)
+}
@@ -0,0 +1,40 @@
+//go:build !windows
+
+package launcher
+
+import (
+	"errors"
+	"net/url"
+	"path/filepath"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// reveal asks the desktop's file manager to select the item through the
+// freedesktop FileManager1 interface (Nautilus, Dolphin, Nemo, Thunar...)
+// and falls back to opening the containing folder
+func reveal(path string) error {
+	if err := showItems(path); err == nil {
+		return nil
+	}
+	return Open(filepath.Dir(path))
+}
+
+func showItems(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+	uri := (&url.URL{Scheme: "file", Path: abs}).String()
+	obj := conn.Object("org.freedesktop.FileManager1", "/org/freedesktop/FileManager1")
+	return obj.Call("org.freedesktop.FileManager1.ShowItems", 0, []string{uri}, "").Err
+}
+
+// resolveLnk is only supported on Windows
+func resolveLnk(string) (string, error) {
+	return "", errors.ErrUnsupported
+}
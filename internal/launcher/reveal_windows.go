@@ -0,0 +1,39 @@
+package launcher
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// reveal selects the item in Explorer. Explorer parses its command line
+// itself and only accepts the path quoted after "/select,", so the command
+// line is written by hand instead of with the usual argument escaping.
+func reveal(path string) error {
+	cmd := exec.Command("explorer.exe")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CmdLine: `explorer.exe /select,"` + filepath.Clean(path) + `"`,
+	}
+	// explorer 即使成功也常常返回退出码 1，只检查能否启动
+	return cmd.Start()
+}
+
+// resolveLnk reads the target of a Windows shortcut through the WScript.Shell
+// COM object
+func resolveLnk(lnkPath string) (string, error) {
+	psScript := `(New-Object -ComObject WScript.Shell).CreateShortcut('` +
+		strings.ReplaceAll(lnkPath, "'", "''") + `').TargetPath`
+
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", psScript)
+	// 隐藏 PowerShell 窗口
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
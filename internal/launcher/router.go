@@ -0,0 +1,134 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go-musetool/internal/model"
+)
+
+// placeholderTarget in a route command is replaced with the URL or path
+// being opened. Commands without it get the target as the last argument.
+const placeholderTarget = "{target}"
+
+var ErrInvalidRoute = errors.New("invalid route")
+
+var (
+	routesMu sync.RWMutex
+	routes   []model.Route
+)
+
+// SetRoutes replaces the routes consulted by Open
+func SetRoutes(r []model.Route) {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+	routes = append([]model.Route{}, r...)
+}
+
+// ValidateRoute checks that a route can match something and has a command
+func ValidateRoute(r model.Route) error {
+	if r.Scheme == "" && r.Host == "" && r.Ext == "" {
+		return fmt.Errorf("%w: set a scheme, host or extension", ErrInvalidRoute)
+	}
+	if len(SplitArgs(r.Command)) == 0 {
+		return fmt.Errorf("%w: command is empty", ErrInvalidRoute)
+	}
+	if _, err := path.Match(r.Host, ""); err != nil {
+		return fmt.Errorf("%w: host pattern %q: %v", ErrInvalidRoute, r.Host, err)
+	}
+	return nil
+}
+
+// MatchRoute returns the first route matching target (a URL or a file path)
+func MatchRoute(rs []model.Route, target string) (model.Route, bool) {
+	for _, r := range rs {
+		if routeMatches(r, target) {
+			return r, true
+		}
+	}
+	return model.Route{}, false
+}
+
+// routeMatches reports whether every criterion set on r matches target. An
+// extension without a scheme only matches local files, so a "*.md" route
+// does not capture web pages that happen to end in .md.
+func routeMatches(r model.Route, target string) bool {
+	if r.Scheme == "" && r.Host == "" && r.Ext == "" {
+		return false
+	}
+
+	scheme, host, filePath := "", "", target
+	if isURL(target) {
+		u, err := url.Parse(target)
+		if err != nil {
+			return false
+		}
+		scheme, host, filePath = strings.ToLower(u.Scheme), strings.ToLower(u.Hostname()), u.Path
+	}
+
+	if r.Scheme != "" && !strings.EqualFold(strings.TrimSuffix(r.Scheme, ":"), scheme) {
+		return false
+	}
+	if r.Host != "" {
+		if host == "" {
+			return false
+		}
+		if ok, _ := path.Match(strings.ToLower(r.Host), host); !ok {
+			return false
+		}
+	}
+	if r.Ext != "" {
+		if r.Scheme == "" && scheme != "" && scheme != "file" {
+			return false
+		}
+		ext := "." + strings.TrimPrefix(strings.ToLower(r.Ext), ".")
+		if strings.ToLower(filepath.Ext(filePath)) != ext {
+			return false
+		}
+	}
+	return true
+}
+
+// RouteCommand builds the command line that opens target with the route
+func RouteCommand(r model.Route, target string) ([]string, error) {
+	args := SplitArgs(r.Command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: command is empty", ErrInvalidRoute)
+	}
+	found := false
+	for i, arg := range args {
+		if strings.Contains(arg, placeholderTarget) {
+			args[i] = strings.ReplaceAll(arg, placeholderTarget, target)
+			found = true
+		}
+	}
+	if !found {
+		args = append(args, target)
+	}
+	return args, nil
+}
+
+// openRouted opens target with the first matching route, reporting false
+// when no route matches
+func openRouted(target string) (bool, error) {
+	routesMu.RLock()
+	r, ok := MatchRoute(routes, target)
+	routesMu.RUnlock()
+	if !ok {
+		return false, nil
+	}
+	args, err := RouteCommand(r, target)
+	if err != nil {
+		return true, err
+	}
+	if r.Terminal {
+		return true, runInTerminal(args, "", false)
+	}
+	return true, exec.Command(args[0], args[1:]...).Start()
+}
@@ -0,0 +1,117 @@
+package launcher
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go-musetool/internal/model"
+)
+
+func TestRouteMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		route  model.Route
+		target string
+		want   bool
+	}{
+		{"scheme", model.Route{Scheme: "ssh"}, "ssh://build.example", true},
+		{"scheme with colon", model.Route{Scheme: "ssh:"}, "ssh://build.example", true},
+		{"scheme ignores case", model.Route{Scheme: "SSH"}, "ssh://build.example", true},
+		{"other scheme", model.Route{Scheme: "ssh"}, "https://build.example", false},
+		{"scheme and file", model.Route{Scheme: "ssh"}, "/home/ada/notes.md", false},
+
+		{"host glob", model.Route{Host: "*.internal.corp"}, "https://wiki.internal.corp/page", true},
+		{"host glob ignores case", model.Route{Host: "*.Internal.Corp"}, "https://WIKI.internal.corp", true},
+		{"host glob with port", model.Route{Host: "*.internal.corp"}, "https://wiki.internal.corp:8443/", true},
+		{"host glob does not match parent", model.Route{Host: "*.internal.corp"}, "https://internal.corp", false},
+		{"host glob does not match suffix", model.Route{Host: "*.internal.corp"}, "https://evil-internal.corp", false},
+		{"host and file", model.Route{Host: "*"}, "/home/ada/notes.md", false},
+		{"scheme and host", model.Route{Scheme: "https", Host: "jira.example"}, "http://jira.example", false},
+
+		{"extension", model.Route{Ext: ".md"}, "/home/ada/notes.md", true},
+		{"extension without dot", model.Route{Ext: "md"}, `C:\Users\ada\NOTES.MD`, true},
+		{"extension of file URL", model.Route{Ext: ".md"}, "file:///home/ada/notes.md", true},
+		{"extension ignores web pages", model.Route{Ext: ".md"}, "https://example.com/README.md", false},
+		{"extension with scheme", model.Route{Scheme: "https", Ext: ".pdf"}, "https://example.com/a.pdf", true},
+		{"other extension", model.Route{Ext: ".md"}, "/home/ada/notes.txt", false},
+
+		{"empty route", model.Route{Command: "code"}, "/home/ada/notes.md", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := routeMatches(tt.route, tt.target); got != tt.want {
+				t.Errorf("routeMatches(%+v, %q) = %v, want %v", tt.route, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchRouteFirstWins(t *testing.T) {
+	routes := []model.Route{
+		{Host: "*.internal.corp", Command: "firefox -P Work"},
+		{Scheme: "https", Command: "firefox"},
+		{Ext: ".md", Command: "code"},
+	}
+	tests := []struct {
+		target  string
+		want    string
+		matched bool
+	}{
+		{"https://wiki.internal.corp", "firefox -P Work", true},
+		{"https://example.com", "firefox", true},
+		{"/tmp/notes.md", "code", true},
+		{"mailto:ada@example.com", "", false},
+	}
+	for _, tt := range tests {
+		r, ok := MatchRoute(routes, tt.target)
+		if ok != tt.matched || r.Command != tt.want {
+			t.Errorf("MatchRoute(%q) = %q, %v, want %q, %v", tt.target, r.Command, ok, tt.want, tt.matched)
+		}
+	}
+}
+
+func TestValidateRoute(t *testing.T) {
+	tests := []struct {
+		route model.Route
+		ok    bool
+	}{
+		{model.Route{Scheme: "ssh", Command: "ssh"}, true},
+		{model.Route{Host: "*.corp", Command: `"C:\Program Files\Firefox\firefox.exe" -P Work`}, true},
+		{model.Route{Command: "code"}, false},
+		{model.Route{Ext: ".md"}, false},
+		{model.Route{Ext: ".md", Command: "   "}, false},
+		{model.Route{Host: "[a-", Command: "firefox"}, false},
+	}
+	for _, tt := range tests {
+		err := ValidateRoute(tt.route)
+		if (err == nil) != tt.ok || (err != nil && !errors.Is(err, ErrInvalidRoute)) {
+			t.Errorf("ValidateRoute(%+v) = %v", tt.route, err)
+		}
+	}
+}
+
+func TestRouteCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		target  string
+		want    []string
+	}{
+		{"code", "/tmp/a b.md", []string{"code", "/tmp/a b.md"}},
+		{"firefox -P Work", "https://a.example", []string{"firefox", "-P", "Work", "https://a.example"}},
+		{"firefox -P Work --new-tab {target}", "https://a.example", []string{"firefox", "-P", "Work", "--new-tab", "https://a.example"}},
+		{"open --url={target} --again {target}", "x", []string{"open", "--url=x", "--again", "x"}},
+		{`"C:\Program Files\Typora\Typora.exe"`, `C:\notes.md`, []string{`C:\Program Files\Typora\Typora.exe`, `C:\notes.md`}},
+		// 目标中的空格和引号不会拆成多个参数
+		{"ssh {target}", `host "; rm -rf ~`, []string{"ssh", `host "; rm -rf ~`}},
+	}
+	for _, tt := range tests {
+		got, err := RouteCommand(model.Route{Command: tt.command}, tt.target)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RouteCommand(%q, %q) = %q, %v, want %q", tt.command, tt.target, got, err, tt.want)
+		}
+	}
+	if _, err := RouteCommand(model.Route{}, "x"); !errors.Is(err, ErrInvalidRoute) {
+		t.Errorf("RouteCommand without a command = %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package launcher
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"go-musetool/internal/model"
+)
+
+// SearchQueryInput is the input name the query of a search shortcut is
+// collected under, so the prompt and --input query=... work like for
+// {input:Name} placeholders
+const SearchQueryInput = "query"
+
+// Search URL templates contain {query}; the OpenSearch spelling
+// {searchTerms} is accepted as well
+const (
+	placeholderQuery       = "{query}"
+	placeholderSearchTerms = "{searchTerms}"
+)
+
+var ErrNotOpenSearch = errors.New("not an OpenSearch description")
+
+// IsSearchTemplate reports whether a URL contains a query placeholder
+func IsSearchTemplate(s string) bool {
+	return strings.Contains(s, placeholderQuery) || strings.Contains(s, placeholderSearchTerms)
+}
+
+// ExpandSearch fills the query into a search URL template, percent-encoded
+func ExpandSearch(tmpl, query string) string {
+	escaped := EscapeURL(query)
+	tmpl = strings.ReplaceAll(tmpl, placeholderQuery, escaped)
+	return strings.ReplaceAll(tmpl, placeholderSearchTerms, escaped)
+}
+
+// SplitKeywordQuery splits what was typed in the search box into the first
+// word and the rest: "g golang generics" -> "g", "golang generics"
+func SplitKeywordQuery(input string) (keyword, query string) {
+	input = strings.TrimSpace(input)
+	i := strings.IndexFunc(input, unicode.IsSpace)
+	if i < 0 {
+		return input, ""
+	}
+	return input[:i], strings.TrimSpace(input[i:])
+}
+
+// DefaultSearches returns the web searches offered to new users
+func DefaultSearches() []model.Shortcut {
+	return []model.Shortcut{
+		{Name: "Google", Kind: model.KindSearch, Keyword: "g", Path: "https://www.google.com/search?q={query}"},
+		{Name: "DuckDuckGo", Kind: model.KindSearch, Keyword: "ddg", Path: "https://duckduckgo.com/?q={query}"},
+		{Name: "Bing", Kind: model.KindSearch, Keyword: "b", Path: "https://www.bing.com/search?q={query}"},
+		{Name: "Wikipedia", Kind: model.KindSearch, Keyword: "wiki", Path: "https://en.wikipedia.org/wiki/Special:Search?search={query}"},
+		{Name: "GitHub", Kind: model.KindSearch, Keyword: "gh", Path: "https://github.com/search?q={query}"},
+		{Name: "Go Packages", Kind: model.KindSearch, Keyword: "pkg", Path: "https://pkg.go.dev/search?q={query}"},
+		{Name: "YouTube", Kind: model.KindSearch, Keyword: "yt", Path: "https://www.youtube.com/results?search_query={query}"},
+	}
+}
+
+// openSearchDescription is the part of an OpenSearch description document
+// (https://github.com/dewitt/opensearch) needed for a search shortcut
+type openSearchDescription struct {
+	XMLName   xml.Name `xml:"OpenSearchDescription"`
+	ShortName string   `xml:"ShortName"`
+	URLs      []struct {
+		Type     string `xml:"type,attr"`
+		Method   string `xml:"method,attr"`
+		Template string `xml:"template,attr"`
+	} `xml:"Url"`
+}
+
+// openSearchParam matches the template parameters other than searchTerms,
+// e.g. {startPage?} or {inputEncoding}
+var openSearchParam = regexp.MustCompile(`\{[A-Za-z:]+\??\}`)
+
+// ParseOpenSearch reads an OpenSearch description and returns a search
+// shortcut for its HTML results page. The keyword is derived from the short
+// name and can be changed afterwards.
+func ParseOpenSearch(r io.Reader) (model.Shortcut, error) {
+	var desc openSearchDescription
+	if err := xml.NewDecoder(r).Decode(&desc); err != nil || strings.TrimSpace(desc.ShortName) == "" {
+		return model.Shortcut{}, ErrNotOpenSearch
+	}
+	for _, u := range desc.URLs {
+		// 只能打开 GET 方式的网页结果，JSON / RSS 等接口不适用
+		if u.Type != "text/html" || (u.Method != "" && !strings.EqualFold(u.Method, "get")) {
+			continue
+		}
+		tmpl := strings.ReplaceAll(u.Template, placeholderSearchTerms, placeholderQuery)
+		tmpl = openSearchParam.ReplaceAllStringFunc(tmpl, func(param string) string {
+			switch strings.Trim(param, "{}?") {
+			case "query":
+				return param
+			case "inputEncoding", "outputEncoding":
+				return "UTF-8"
+			case "startPage", "startIndex":
+				return "1"
+			case "count":
+				return "20"
+			}
+			return "" // 其它可选参数留空
+		})
+		name := strings.TrimSpace(desc.ShortName)
+		return model.Shortcut{
+			Name:    name,
+			Kind:    model.KindSearch,
+			Keyword: strings.ToLower(strings.Join(strings.Fields(name), "")),
+			Path:    tmpl,
+		}, nil
+	}
+	return model.Shortcut{}, ErrNotOpenSearch
+}
@@ -0,0 +1,115 @@
+package launcher
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-musetool/internal/model"
+)
+
+func TestExpandSearch(t *testing.T) {
+	tests := []struct {
+		tmpl, query, want string
+	}{
+		{"https://www.google.com/search?q={query}", "golang generics", "https://www.google.com/search?q=golang%20generics"},
+		{"https://example.com/?q={query}", "a+b & c=d", "https://example.com/?q=a%2Bb%20%26%20c%3Dd"},
+		{"https://example.com/?q={query}", "中文", "https://example.com/?q=%E4%B8%AD%E6%96%87"},
+		{"https://example.com/?q={query}", "", "https://example.com/?q="},
+		{"https://example.com/wiki/{query}", "a/b?c#d", "https://example.com/wiki/a%2Fb%3Fc%23d"},
+		{"https://example.com/?q={searchTerms}&also={query}", "x y", "https://example.com/?q=x%20y&also=x%20y"},
+	}
+	for _, tt := range tests {
+		if got := ExpandSearch(tt.tmpl, tt.query); got != tt.want {
+			t.Errorf("ExpandSearch(%q, %q) = %q, want %q", tt.tmpl, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSplitKeywordQuery(t *testing.T) {
+	tests := []struct {
+		input, keyword, query string
+	}{
+		{"g golang generics", "g", "golang generics"},
+		{"  jira   PROJ-123  ", "jira", "PROJ-123"},
+		{"g\tx", "g", "x"},
+		{"wiki", "wiki", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if k, q := SplitKeywordQuery(tt.input); k != tt.keyword || q != tt.query {
+			t.Errorf("SplitKeywordQuery(%q) = %q, %q, want %q, %q", tt.input, k, q, tt.keyword, tt.query)
+		}
+	}
+}
+
+func TestResolveSearch(t *testing.T) {
+	s := model.Shortcut{Kind: model.KindSearch, Path: "https://{input:Site}/search?q={query}&g={selectedGroup}"}
+	v := Values{
+		Inputs:        map[string]string{"Site": "jira.example", SearchQueryInput: "bug 42"},
+		SelectedGroup: "Dev Tools",
+	}
+	if names := InputNames(s); !reflect.DeepEqual(names, []string{"Site", SearchQueryInput}) {
+		t.Errorf("InputNames = %q, want the template inputs followed by %q", names, SearchQueryInput)
+	}
+	got, err := Resolve(s, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://jira.example/search?q=bug%2042&g=Dev%20Tools"; got.Path != want {
+		t.Errorf("Resolve = %q, want %q", got.Path, want)
+	}
+	delete(v.Inputs, SearchQueryInput)
+	if _, err := Resolve(s, v); !errors.Is(err, ErrMissingInput) {
+		t.Errorf("Resolve without a query = %v, want %v", err, ErrMissingInput)
+	}
+}
+
+func TestDefaultSearches(t *testing.T) {
+	keywords := map[string]bool{}
+	for _, s := range DefaultSearches() {
+		if err := Validate(s); err != nil {
+			t.Errorf("%s: %v", s.Name, err)
+		}
+		if s.Keyword == "" || keywords[s.Keyword] {
+			t.Errorf("%s: keyword %q is empty or used twice", s.Name, s.Keyword)
+		}
+		keywords[s.Keyword] = true
+	}
+}
+
+const openSearchXML = `<?xml version="1.0" encoding="UTF-8"?>
+<OpenSearchDescription xmlns="http://a9.com/-/spec/opensearch/1.1/">
+  <ShortName>Go Issues</ShortName>
+  <Url type="application/x-suggestions+json" template="https://example.com/suggest?q={searchTerms}"/>
+  <Url type="text/html" method="post" template="https://example.com/post"/>
+  <Url type="text/html" template="https://example.com/issues?q={searchTerms}&amp;page={startPage?}&amp;enc={inputEncoding}&amp;lang={language?}"/>
+</OpenSearchDescription>`
+
+func TestParseOpenSearch(t *testing.T) {
+	s, err := ParseOpenSearch(strings.NewReader(openSearchXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.Shortcut{
+		Name:    "Go Issues",
+		Kind:    model.KindSearch,
+		Keyword: "goissues",
+		Path:    "https://example.com/issues?q={query}&page=1&enc=UTF-8&lang=",
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("ParseOpenSearch = %+v, want %+v", s, want)
+	}
+
+	for _, doc := range []string{
+		"not xml",
+		`<OpenSearchDescription><ShortName>X</ShortName></OpenSearchDescription>`,
+		`<OpenSearchDescription><Url type="text/html" template="https://a/?q={searchTerms}"/></OpenSearchDescription>`,
+		`<rss><ShortName>X</ShortName></rss>`,
+	} {
+		if _, err := ParseOpenSearch(strings.NewReader(doc)); !errors.Is(err, ErrNotOpenSearch) {
+			t.Errorf("ParseOpenSearch(%q) = %v, want %v", doc, err, ErrNotOpenSearch)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package launcher
+
+import "os/exec"
+
+// shellCommand runs a command line through sh
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("sh", "-c", command)
+}
+
+// terminalProcess starts a TerminalCommand result
+func terminalProcess(cmdline []string) *exec.Cmd {
+	return exec.Command(cmdline[0], cmdline[1:]...)
+}
@@ -0,0 +1,29 @@
+package launcher
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// shellCommand runs a command line through cmd.exe. cmd.exe parses its
+// command line itself and does not understand the backslash escaping that
+// exec.Command applies to arguments, so the line is written by hand (see
+// cmdShellLine).
+func shellCommand(command string) *exec.Cmd {
+	cmd := exec.Command(windowsShell)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CmdLine: cmdShellLine(command, false),
+	}
+	return cmd
+}
+
+// terminalProcess starts a TerminalCommand result. The console line at the
+// end would be garbled by exec.Command's escaping, so the command line is
+// written by hand as well.
+func terminalProcess(cmdline []string) *exec.Cmd {
+	cmd := exec.Command(cmdline[0])
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CmdLine: windowsCommandLine(cmdline),
+	}
+	return cmd
+}
@@ -0,0 +1,33 @@
+package launcher
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestShellCommandKeepsQuotes(t *testing.T) {
+	cmd := shellCommand(`"C:\Program Files\app.exe" --name "a b"`)
+	want := `cmd.exe /S /C ""C:\Program Files\app.exe" --name "a b""`
+	if got := cmd.SysProcAttr.CmdLine; got != want {
+		t.Errorf("CmdLine = %s, want %s", got, want)
+	}
+}
+
+func TestEscapeWindowsArgMatchesSyscall(t *testing.T) {
+	for _, arg := range []string{"", "plain", "a b", `C:\Program Files\`, `say "hi"`, `a\"b`, `\\server\share x\`, "tab\there"} {
+		if got, want := escapeWindowsArg(arg), syscall.EscapeArg(arg); got != want {
+			t.Errorf("escapeWindowsArg(%q) = %s, want %s", arg, got, want)
+		}
+	}
+}
+
+func TestTerminalProcessCmdLine(t *testing.T) {
+	cmdline, err := TerminalCommand(TerminalWindows, shellArgs(`echo "a b" ^& dir`), `C:\Work Dir`, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `wt.exe -d "C:\Work Dir" cmd.exe /S /K "echo "a b" ^& dir"`
+	if got := terminalProcess(cmdline).SysProcAttr.CmdLine; got != want {
+		t.Errorf("CmdLine = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,283 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"go-musetool/internal/model"
+)
+
+// Placeholders recognised in Path, Args, WorkDir, Command and Text:
+//
+//	{input:Name}      value typed by the user when launching
+//	{clipboard}       current clipboard text
+//	{date}            current date, 2006-01-02
+//	{date:LAYOUT}     current time in a Go time layout, e.g. {date:15:04}
+//	{env:NAME}        environment variable
+//	{selectedGroup}   the group shown in the main window
+//
+// Anything else in braces is left untouched, so shell snippets such as
+// awk '{print $1}' keep working.
+const (
+	placeholderInput     = "input"
+	placeholderClipboard = "clipboard"
+	placeholderDate      = "date"
+	placeholderEnv       = "env"
+	placeholderGroup     = "selectedGroup"
+
+	defaultDateLayout = "2006-01-02"
+)
+
+var ErrMissingInput = errors.New("missing input value")
+
+// Values supplies the data placeholders expand to
+type Values struct {
+	Inputs        map[string]string
+	Clipboard     string
+	SelectedGroup string
+	Now           time.Time           // 零值表示 time.Now()
+	Getenv        func(string) string // nil 表示 os.Getenv
+}
+
+// Expand replaces the placeholders in tmpl. escape is applied to every
+// substituted value (not to the literal text around it).
+func Expand(tmpl string, v Values, escape func(string) string) (string, error) {
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+	var sb strings.Builder
+	rest := tmpl
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			sb.WriteString(rest)
+			return sb.String(), nil
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			sb.WriteString(rest)
+			return sb.String(), nil
+		}
+		end += start
+
+		value, ok, err := v.lookup(rest[start+1 : end])
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			// 不是占位符：原样输出 "{" 并从下一个字符继续查找
+			sb.WriteString(rest[:start+1])
+			rest = rest[start+1:]
+			continue
+		}
+		sb.WriteString(rest[:start])
+		sb.WriteString(escape(value))
+		rest = rest[end+1:]
+	}
+}
+
+func (v Values) lookup(token string) (value string, ok bool, err error) {
+	name, arg, hasArg := strings.Cut(token, ":")
+	switch {
+	case name == placeholderInput && hasArg:
+		value, ok := v.Inputs[arg]
+		if !ok {
+			return "", false, fmt.Errorf("%w: %s", ErrMissingInput, arg)
+		}
+		return value, true, nil
+	case name == placeholderClipboard && !hasArg:
+		return v.Clipboard, true, nil
+	case name == placeholderDate:
+		now := v.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+		if !hasArg {
+			arg = defaultDateLayout
+		}
+		return now.Format(arg), true, nil
+	case name == placeholderEnv && hasArg:
+		getenv := v.Getenv
+		if getenv == nil {
+			getenv = os.Getenv
+		}
+		return getenv(arg), true, nil
+	case name == placeholderGroup && !hasArg:
+		return v.SelectedGroup, true, nil
+	}
+	return "", false, nil
+}
+
+// InputNames lists the {input:Name} placeholders of a shortcut in order of
+// first appearance
+func InputNames(s model.Shortcut) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, field := range []string{s.Path, s.Args, s.WorkDir, s.Command, s.Text} {
+		rest := field
+		for {
+			start := strings.Index(rest, "{"+placeholderInput+":")
+			if start < 0 {
+				break
+			}
+			rest = rest[start+len(placeholderInput)+2:]
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				break
+			}
+			if name := rest[:end]; !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+			rest = rest[end+1:]
+		}
+	}
+	// 网页搜索的查询词和 {input:Name} 一样在启动时输入
+	if KindOf(s) == model.KindSearch && IsSearchTemplate(s.Path) && !seen[SearchQueryInput] {
+		names = append(names, SearchQueryInput)
+	}
+	return names
+}
+
+// UsesClipboard reports whether any field of the shortcut reads {clipboard}
+func UsesClipboard(s model.Shortcut) bool {
+	token := "{" + placeholderClipboard + "}"
+	for _, field := range []string{s.Path, s.Args, s.WorkDir, s.Command, s.Text} {
+		if strings.Contains(field, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns a copy of the shortcut with every placeholder expanded,
+// escaped for where the value ends up: URL-encoded in URLs, quoted for the
+// shell in commands, and kept as a single argument in Args.
+func Resolve(s model.Shortcut, v Values) (model.Shortcut, error) {
+	var err error
+	expand := func(field *string, escape func(string) string) {
+		if err == nil {
+			*field, err = Expand(*field, v, escape)
+		}
+	}
+
+	kind := KindOf(s)
+	pathEscape := func(s string) string { return s }
+	if kind == model.KindURL || kind == model.KindSearch {
+		pathEscape = EscapeURL
+	}
+	expand(&s.Path, pathEscape)
+	if err == nil && kind == model.KindSearch && IsSearchTemplate(s.Path) {
+		query, ok := v.Inputs[SearchQueryInput]
+		if !ok {
+			return s, fmt.Errorf("%w: %s", ErrMissingInput, SearchQueryInput)
+		}
+		s.Path = ExpandSearch(s.Path, query)
+	}
+	expand(&s.WorkDir, nil)
+	expand(&s.Command, QuoteShell)
+	expand(&s.Text, nil)
+	if err != nil {
+		return s, err
+	}
+
+	// 先拆分参数再逐个展开，输入值中的空格和引号不会拆出新参数
+	if s.Args != "" {
+		args := SplitArgs(s.Args)
+		for i := range args {
+			if args[i], err = Expand(args[i], v, nil); err != nil {
+				return s, err
+			}
+		}
+		s.Args = JoinArgs(args)
+	}
+	return s, nil
+}
+
+// EscapeURL percent-encodes a value so it is safe in both the path and the
+// query of a URL
+func EscapeURL(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// QuoteShell quotes a value as a single word for the platform shell used by
+// command shortcuts (cmd.exe on Windows, sh elsewhere). The value must not be
+// placed inside quotes in the command template.
+func QuoteShell(s string) string {
+	if runtime.GOOS == "windows" {
+		return quoteCmd(s)
+	}
+	return quotePOSIX(s)
+}
+
+func quotePOSIX(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// cmdMetaChars are interpreted by cmd.exe outside quotes
+const cmdMetaChars = `()%!^"<>&|`
+
+// quoteCmd quotes a value for a command line run by cmd.exe. The value is
+// first quoted for the program with quoteArgv, then every character cmd.exe
+// treats specially, including those quotes, is escaped with "^". Escaped
+// quotes do not switch cmd.exe into quoted mode, so the carets cover the whole
+// value and neither "&" nor "%VAR%" in it is interpreted. cmd.exe ends the
+// command at a line break, so line breaks become spaces.
+func quoteCmd(s string) string {
+	quoted := quoteArgv(strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(s))
+	var sb strings.Builder
+	for i := 0; i < len(quoted); i++ {
+		if strings.IndexByte(cmdMetaChars, quoted[i]) >= 0 {
+			sb.WriteByte('^')
+		}
+		sb.WriteByte(quoted[i])
+	}
+	return sb.String()
+}
+
+// quoteArgv follows the CommandLineToArgvW rules: backslashes are literal
+// unless they precede a double quote.
+func quoteArgv(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	backslashes := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			backslashes++
+		case '"':
+			sb.WriteString(strings.Repeat(`\`, backslashes*2+1))
+			sb.WriteByte('"')
+			backslashes = 0
+			continue
+		default:
+			sb.WriteString(strings.Repeat(`\`, backslashes))
+			sb.WriteByte(c)
+			backslashes = 0
+			continue
+		}
+	}
+	sb.WriteString(strings.Repeat(`\`, backslashes*2))
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// JoinArgs is the inverse of SplitArgs: every argument survives a round trip
+// unchanged
+func JoinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n\"'") {
+			quoted[i] = arg
+			continue
+		}
+		// SplitArgs 没有转义字符：双引号本身用单引号包起来，相邻片段会拼成同一个参数
+		quoted[i] = `"` + strings.ReplaceAll(arg, `"`, `"'"'"`) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,263 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"go-musetool/internal/model"
+)
+
+var testValues = Values{
+	Inputs:        map[string]string{"Name": "Ada Lovelace", "Port": "8080"},
+	Clipboard:     "copied text",
+	SelectedGroup: "Dev",
+	Now:           time.Date(2024, 3, 5, 14, 7, 0, 0, time.UTC),
+	Getenv: func(name string) string {
+		return map[string]string{"HOME": "/home/ada"}[name]
+	},
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"no placeholders", "no placeholders"},
+		{"hello {input:Name}", "hello Ada Lovelace"},
+		{"{clipboard}", "copied text"},
+		{"{date}", "2024-03-05"},
+		{"{date:15:04}", "14:07"},
+		{"{env:HOME}/bin", "/home/ada/bin"},
+		{"{env:MISSING}", ""},
+		{"group {selectedGroup}", "group Dev"},
+		{"localhost:{input:Port}/{input:Port}", "localhost:8080/8080"},
+		{"awk '{print $1}'", "awk '{print $1}'"},
+		{"{unknown} {clipboard:arg} {input}", "{unknown} {clipboard:arg} {input}"},
+		{"{{clipboard}}", "{copied text}"},
+		{"unterminated {clipboard", "unterminated {clipboard"},
+	}
+	for _, tt := range tests {
+		got, err := Expand(tt.tmpl, testValues, nil)
+		if err != nil || got != tt.want {
+			t.Errorf("Expand(%q) = %q, %v, want %q", tt.tmpl, got, err, tt.want)
+		}
+	}
+
+	if _, err := Expand("{input:Missing}", testValues, nil); !errors.Is(err, ErrMissingInput) {
+		t.Errorf("Expand with a missing input = %v, want %v", err, ErrMissingInput)
+	}
+
+	// escape 只作用于替换的值
+	got, _ := Expand("<{input:Name}>", testValues, strings.ToUpper)
+	if got != "<ADA LOVELACE>" {
+		t.Errorf("Expand with escape = %q", got)
+	}
+}
+
+func TestInputNames(t *testing.T) {
+	s := model.Shortcut{
+		Kind:    model.KindCommand,
+		Command: "ssh {input:Host} -p {input:Port}",
+		WorkDir: "{input:Dir}",
+		Args:    "{input:Host}",
+	}
+	if got, want := InputNames(s), []string{"Host", "Dir", "Port"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("InputNames = %q, want %q", got, want)
+	}
+
+	search := model.Shortcut{Kind: model.KindSearch, Path: "https://example.com/?q={query}"}
+	if got := InputNames(search); !reflect.DeepEqual(got, []string{SearchQueryInput}) {
+		t.Errorf("InputNames(search) = %q", got)
+	}
+
+	if UsesClipboard(s) || !UsesClipboard(model.Shortcut{Text: "> {clipboard}"}) {
+		t.Error("UsesClipboard is wrong")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	v := testValues
+	v.Inputs = map[string]string{"Name": "a b&c", SearchQueryInput: "go generics"}
+
+	url, err := Resolve(model.Shortcut{Kind: model.KindURL, Path: "https://example.com/u/{input:Name}"}, v)
+	if err != nil || url.Path != "https://example.com/u/a%20b%26c" {
+		t.Errorf("url = %q, %v", url.Path, err)
+	}
+
+	search, err := Resolve(model.Shortcut{Kind: model.KindSearch, Path: "https://example.com/?q={query}"}, v)
+	if err != nil || search.Path != "https://example.com/?q=go%20generics" {
+		t.Errorf("search = %q, %v", search.Path, err)
+	}
+
+	app, err := Resolve(model.Shortcut{Kind: model.KindApplication, Path: "/usr/bin/app", Args: `--name {input:Name} "--group={selectedGroup}"`}, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := SplitArgs(app.Args), []string{"--name", "a b&c", "--group=Dev"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+
+	cmd, err := Resolve(model.Shortcut{Kind: model.KindCommand, Command: "echo {input:Name}"}, v)
+	if err != nil || cmd.Command != "echo "+QuoteShell("a b&c") {
+		t.Errorf("command = %q, %v", cmd.Command, err)
+	}
+
+	snippet, err := Resolve(model.Shortcut{Kind: model.KindSnippet, Text: "Hi {input:Name}"}, v)
+	if err != nil || snippet.Text != "Hi a b&c" {
+		t.Errorf("snippet = %q, %v", snippet.Text, err)
+	}
+
+	if _, err := Resolve(model.Shortcut{Kind: model.KindSearch, Path: "https://example.com/?q={query}"}, testValues); !errors.Is(err, ErrMissingInput) {
+		t.Errorf("search without a query = %v, want %v", err, ErrMissingInput)
+	}
+}
+
+func TestJoinArgsRoundTrip(t *testing.T) {
+	for _, args := range [][]string{
+		{"plain"},
+		{"two words", ""},
+		{`say "hi"`, "it's", `C:\Program Files\app.exe`},
+		{"tab\tand\nnewline"},
+	} {
+		if got := SplitArgs(JoinArgs(args)); !reflect.DeepEqual(got, args) {
+			t.Errorf("SplitArgs(JoinArgs(%q)) = %q", args, got)
+		}
+	}
+}
+
+// hostileValues are placeholder values that must reach the program as one
+// argument without being run by the shell
+var hostileValues = []string{
+	"",
+	"plain",
+	"two words",
+	`x" & calc & "`,
+	`x' ; touch pwned ; '`,
+	"$(touch pwned) `touch pwned` $HOME",
+	"%PATH% %%OS%% 100%",
+	"!PATH!",
+	`^caret^ (a)|b<c>d`,
+	`"quoted"`,
+	`C:\dir\`,
+	`\\server\share\"x`,
+	"中文 & 字符",
+}
+
+func TestQuotePOSIX(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	for _, v := range hostileValues {
+		out, err := exec.Command("sh", "-c", "printf '%s' "+quotePOSIX(v)).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != v {
+			t.Errorf("sh received %q, want %q", out, v)
+		}
+	}
+}
+
+func TestQuoteCmd(t *testing.T) {
+	values := append(hostileValues, "line1\nline2\r\nline3")
+	for _, v := range values {
+		line, err := cmdExpand("prog " + quoteCmd(v))
+		if err != nil {
+			t.Errorf("quoteCmd(%q) = %s: %v", v, quoteCmd(v), err)
+			continue
+		}
+		want := strings.NewReplacer("\r\n", " ", "\n", " ").Replace(v)
+		if got := commandLineToArgv(line); !reflect.DeepEqual(got, []string{"prog", want}) {
+			t.Errorf("quoteCmd(%q) = %s reaches the program as %q", v, quoteCmd(v), got)
+		}
+	}
+}
+
+// cmdExpand applies what cmd.exe does to a command line before running it:
+// carets outside quotes escape the next character and are removed. It fails
+// when the line would expand a variable or contains an operator that cmd.exe
+// would act on.
+func cmdExpand(line string) (string, error) {
+	var sb strings.Builder
+	inQuotes := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '%':
+			return "", fmt.Errorf("unescaped %% at %d", i)
+		case c == '\n' || c == '\r':
+			return "", fmt.Errorf("line break at %d", i)
+		case inQuotes:
+			inQuotes = c != '"'
+		case c == '^':
+			i++
+			if i < len(line) {
+				c = line[i]
+			}
+		case c == '"':
+			inQuotes = true
+		case strings.IndexByte("&|<>()", c) >= 0:
+			return "", fmt.Errorf("unescaped %c at %d", c, i)
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String(), nil
+}
+
+// commandLineToArgv splits a command line like CommandLineToArgvW
+func commandLineToArgv(line string) []string {
+	var args []string
+	var cur strings.Builder
+	inQuotes, inArg := false, false
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == '\\':
+			n := 0
+			for i < len(line) && line[i] == '\\' {
+				n++
+				i++
+			}
+			if i < len(line) && line[i] == '"' {
+				cur.WriteString(strings.Repeat(`\`, n/2))
+				if n%2 == 1 {
+					cur.WriteByte('"')
+					i++
+				}
+			} else {
+				cur.WriteString(strings.Repeat(`\`, n))
+			}
+			inArg = true
+		case c == '"':
+			if inQuotes && i+1 < len(line) && line[i+1] == '"' {
+				cur.WriteByte('"')
+				i += 2
+				continue
+			}
+			inQuotes = !inQuotes
+			inArg = true
+			i++
+		case (c == ' ' || c == '\t') && !inQuotes:
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+			i++
+		default:
+			cur.WriteByte(c)
+			inArg = true
+			i++
+		}
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args
+}
@@ -0,0 +1,258 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Terminal emulators that console shortcuts can run in
+const (
+	TerminalAuto      = ""        // 自动检测
+	TerminalWindows   = "wt"      // Windows Terminal
+	TerminalConhost   = "conhost" // 经典控制台窗口
+	TerminalGnome     = "gnome-terminal"
+	TerminalKonsole   = "konsole"
+	TerminalAlacritty = "alacritty"
+	TerminalKitty     = "kitty"
+	TerminalXterm     = "xterm"
+)
+
+var (
+	ErrNoTerminal      = errors.New("no supported terminal emulator found")
+	ErrUnknownTerminal = errors.New("unknown terminal emulator")
+)
+
+var (
+	terminalMu sync.RWMutex
+	terminal   string // 设置中选择的终端，TerminalAuto 表示自动检测
+)
+
+// Terminals lists the terminals supported on this OS, in the order
+// auto-detection tries them
+func Terminals() []string {
+	if runtime.GOOS == "windows" {
+		return []string{TerminalWindows, TerminalConhost}
+	}
+	return []string{TerminalGnome, TerminalKonsole, TerminalAlacritty, TerminalKitty, TerminalXterm}
+}
+
+// SetTerminal selects the terminal used by shortcuts that run in a terminal.
+// TerminalAuto picks the first installed one.
+func SetTerminal(name string) {
+	terminalMu.Lock()
+	defer terminalMu.Unlock()
+	terminal = name
+}
+
+// CurrentTerminal returns the configured terminal, detecting one when the
+// setting is TerminalAuto
+func CurrentTerminal() (string, error) {
+	terminalMu.RLock()
+	name := terminal
+	terminalMu.RUnlock()
+	if name != TerminalAuto {
+		return name, nil
+	}
+	return DetectTerminal()
+}
+
+// DetectTerminal returns the first supported terminal found on PATH
+func DetectTerminal() (string, error) {
+	for _, name := range Terminals() {
+		if _, err := exec.LookPath(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", ErrNoTerminal
+}
+
+// TerminalCommand builds the command line that opens term and runs argv in
+// it. dir is passed with the terminal's own option where it has one (the
+// caller should also start the terminal in dir). With keepOpen the window
+// stays open after argv exits: natively where the terminal supports it,
+// otherwise through a shell that waits for Enter.
+//
+// For the Windows terminals the last element is the console command line,
+// already quoted; it must be written verbatim after the other, escaped
+// arguments (see windowsCommandLine) rather than escaped again.
+func TerminalCommand(term string, argv []string, dir string, keepOpen bool) ([]string, error) {
+	if len(argv) == 0 {
+		return nil, ErrMissingTarget
+	}
+	var cmd []string
+	switch term {
+	case TerminalWindows:
+		cmd = []string{"wt.exe"}
+		if dir != "" {
+			cmd = append(cmd, "-d", dir)
+		}
+		// wt 把 ";" 当作多个标签页的命令分隔符，命令行中的 ";" 需要转义
+		cmd = append(cmd, strings.ReplaceAll(windowsConsoleLine(argv, keepOpen), ";", `\;`))
+	case TerminalConhost:
+		cmd = []string{"conhost.exe", windowsConsoleLine(argv, keepOpen)}
+	case TerminalGnome:
+		cmd = []string{"gnome-terminal"}
+		if dir != "" {
+			cmd = append(cmd, "--working-directory="+dir)
+		}
+		// gnome-terminal 没有保留窗口的选项
+		if keepOpen {
+			argv = waitForEnter(argv)
+		}
+		cmd = append(append(cmd, "--"), argv...)
+	case TerminalKonsole:
+		cmd = []string{"konsole"}
+		if dir != "" {
+			cmd = append(cmd, "--workdir", dir)
+		}
+		if keepOpen {
+			cmd = append(cmd, "--hold")
+		}
+		cmd = append(append(cmd, "-e"), argv...)
+	case TerminalAlacritty:
+		cmd = []string{"alacritty"}
+		if dir != "" {
+			cmd = append(cmd, "--working-directory", dir)
+		}
+		if keepOpen {
+			cmd = append(cmd, "--hold")
+		}
+		cmd = append(append(cmd, "-e"), argv...)
+	case TerminalKitty:
+		cmd = []string{"kitty"}
+		if dir != "" {
+			cmd = append(cmd, "--directory", dir)
+		}
+		if keepOpen {
+			cmd = append(cmd, "--hold")
+		}
+		cmd = append(cmd, argv...)
+	case TerminalXterm:
+		// xterm 没有工作目录选项，使用进程的工作目录；-e 必须是最后一个选项
+		cmd = []string{"xterm"}
+		if keepOpen {
+			cmd = append(cmd, "-hold")
+		}
+		cmd = append(append(cmd, "-e"), argv...)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTerminal, term)
+	}
+	return cmd, nil
+}
+
+// windowsConsoleLine returns the command line the console window runs.
+// argv from shellArgs keeps the user's command exactly as typed; other
+// arguments are quoted the way programs split their command line. cmd.exe is
+// added when the window must stay open or the target is not a program (.bat,
+// .lnk, documents), which the terminal could not start by itself.
+func windowsConsoleLine(argv []string, keepOpen bool) string {
+	if len(argv) == 3 && argv[0] == windowsShell && argv[1] == "/C" {
+		return cmdShellLine(argv[2], keepOpen)
+	}
+	line := joinWindowsArgs(argv)
+	if keepOpen {
+		return cmdShellLine(line, true)
+	}
+	switch strings.ToLower(filepath.Ext(argv[0])) {
+	case ".exe", ".com", "":
+		return line
+	}
+	return cmdShellLine(line, false)
+}
+
+// windowsShell is the program shellArgs runs commands with on Windows
+const windowsShell = "cmd.exe"
+
+// cmdShellLine runs command through cmd.exe, which parses its command line
+// itself: with /S it strips the outer quotes and runs the rest exactly as
+// typed. /K keeps the window open afterwards.
+func cmdShellLine(command string, keepOpen bool) string {
+	flag := "/C"
+	if keepOpen {
+		flag = "/K"
+	}
+	return windowsShell + " /S " + flag + ` "` + command + `"`
+}
+
+// windowsCommandLine joins a TerminalCommand result for a Windows terminal:
+// every argument but the last is escaped, the console line is appended as is
+func windowsCommandLine(cmdline []string) string {
+	last := len(cmdline) - 1
+	return joinWindowsArgs(cmdline[:last]) + " " + cmdline[last]
+}
+
+// joinWindowsArgs quotes args so that CommandLineToArgvW, which most
+// programs use, splits them back unchanged. It matches syscall.EscapeArg,
+// which only exists on Windows.
+func joinWindowsArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = escapeWindowsArg(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func escapeWindowsArg(s string) string {
+	if s == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(s, "\" \t\\") {
+		return s
+	}
+	hasSpace := strings.ContainsAny(s, " \t")
+	if !strings.ContainsAny(s, `"\`) {
+		return `"` + s + `"`
+	}
+
+	var b strings.Builder
+	if hasSpace {
+		b.WriteByte('"')
+	}
+	// 引号前的反斜杠要加倍，再转义引号本身
+	slashes := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			slashes++
+		case '"':
+			b.WriteString(strings.Repeat(`\`, slashes+1))
+			slashes = 0
+		default:
+			slashes = 0
+		}
+		b.WriteByte(s[i])
+	}
+	if hasSpace {
+		// 结尾的反斜杠后面是闭合引号，同样加倍
+		b.WriteString(strings.Repeat(`\`, slashes))
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+// waitForEnter wraps argv in a shell that keeps the terminal open until the
+// user presses Enter
+func waitForEnter(argv []string) []string {
+	script := `"$@"; status=$?; printf '\n[exit %d] Press Enter to close' "$status"; read _`
+	return append([]string{"sh", "-c", script, "sh"}, argv...)
+}
+
+// runInTerminal starts argv in a new window of the configured terminal
+func runInTerminal(argv []string, dir string, keepOpen bool) error {
+	term, err := CurrentTerminal()
+	if err != nil {
+		return err
+	}
+	cmdline, err := TerminalCommand(term, argv, dir, keepOpen)
+	if err != nil {
+		return err
+	}
+	cmd := terminalProcess(cmdline)
+	cmd.Dir = dir
+	return cmd.Start()
+}
@@ -0,0 +1,90 @@
+package launcher
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTerminalCommand(t *testing.T) {
+	app := []string{"/usr/bin/htop", "-d", "10"}
+	tests := []struct {
+		term     string
+		argv     []string
+		dir      string
+		keepOpen bool
+		want     []string
+	}{
+		{TerminalGnome, app, "", false, []string{"gnome-terminal", "--", "/usr/bin/htop", "-d", "10"}},
+		{TerminalGnome, app, "/tmp", false, []string{"gnome-terminal", "--working-directory=/tmp", "--", "/usr/bin/htop", "-d", "10"}},
+		{TerminalGnome, app, "", true, append([]string{"gnome-terminal", "--"}, waitForEnter(app)...)},
+		{TerminalKonsole, app, "", false, []string{"konsole", "-e", "/usr/bin/htop", "-d", "10"}},
+		{TerminalKonsole, app, "/tmp", true, []string{"konsole", "--workdir", "/tmp", "--hold", "-e", "/usr/bin/htop", "-d", "10"}},
+		{TerminalAlacritty, app, "", false, []string{"alacritty", "-e", "/usr/bin/htop", "-d", "10"}},
+		{TerminalAlacritty, app, "/tmp", true, []string{"alacritty", "--working-directory", "/tmp", "--hold", "-e", "/usr/bin/htop", "-d", "10"}},
+		{TerminalKitty, app, "", false, []string{"kitty", "/usr/bin/htop", "-d", "10"}},
+		{TerminalKitty, app, "/tmp", true, []string{"kitty", "--directory", "/tmp", "--hold", "/usr/bin/htop", "-d", "10"}},
+		{TerminalXterm, app, "", false, []string{"xterm", "-e", "/usr/bin/htop", "-d", "10"}},
+		{TerminalXterm, app, "/tmp", true, []string{"xterm", "-hold", "-e", "/usr/bin/htop", "-d", "10"}}, // 没有工作目录选项
+		{TerminalGnome, []string{"sh", "-c", "make && ./run"}, "", false, []string{"gnome-terminal", "--", "sh", "-c", "make && ./run"}},
+
+		// Windows：最后一项是原样写入的控制台命令行
+		{TerminalConhost, []string{`C:\Tools\app.exe`, "a b"}, "", false, []string{"conhost.exe", `C:\Tools\app.exe "a b"`}},
+		{TerminalConhost, []string{`C:\Tools\app.exe`}, "", true, []string{"conhost.exe", `cmd.exe /S /K "C:\Tools\app.exe"`}},
+		{TerminalConhost, []string{`C:\My Scripts\build.bat`, "x"}, "", false, []string{"conhost.exe", `cmd.exe /S /C ""C:\My Scripts\build.bat" x"`}},
+		{TerminalConhost, []string{"cmd.exe", "/C", `echo "a b" ^& dir`}, "", false, []string{"conhost.exe", `cmd.exe /S /C "echo "a b" ^& dir"`}},
+		{TerminalConhost, []string{"cmd.exe", "/C", `echo "a b"`}, "", true, []string{"conhost.exe", `cmd.exe /S /K "echo "a b""`}},
+		{TerminalWindows, []string{`C:\Program Files\App\app.exe`, `say "hi"`}, "", false, []string{"wt.exe", `"C:\Program Files\App\app.exe" "say \"hi\""`}},
+		{TerminalWindows, []string{`C:\Tools\app.exe`}, `C:\Work Dir`, true, []string{"wt.exe", "-d", `C:\Work Dir`, `cmd.exe /S /K "C:\Tools\app.exe"`}},
+		{TerminalWindows, []string{"cmd.exe", "/C", "cd src; make"}, "", false, []string{"wt.exe", `cmd.exe /S /C "cd src\; make"`}}, // wt 的命令分隔符
+		{TerminalWindows, []string{"notes.txt"}, "", false, []string{"wt.exe", `cmd.exe /S /C "notes.txt"`}},
+	}
+	for _, tt := range tests {
+		got, err := TerminalCommand(tt.term, tt.argv, tt.dir, tt.keepOpen)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TerminalCommand(%s, %q, %q, %v) = %q, %v, want %q", tt.term, tt.argv, tt.dir, tt.keepOpen, got, err, tt.want)
+		}
+	}
+
+	if _, err := TerminalCommand(TerminalXterm, nil, "", false); !errors.Is(err, ErrMissingTarget) {
+		t.Errorf("TerminalCommand without argv = %v, want %v", err, ErrMissingTarget)
+	}
+	if _, err := TerminalCommand("st", []string{"ls"}, "", false); !errors.Is(err, ErrUnknownTerminal) {
+		t.Errorf("TerminalCommand with an unknown terminal = %v, want %v", err, ErrUnknownTerminal)
+	}
+}
+
+func TestWindowsCommandLine(t *testing.T) {
+	tests := []struct {
+		cmdline []string
+		want    string
+	}{
+		{[]string{"conhost.exe", `cmd.exe /S /C "echo "a b""`}, `conhost.exe cmd.exe /S /C "echo "a b""`},
+		{[]string{"wt.exe", "-d", `C:\Work Dir\`, `app.exe`}, `wt.exe -d "C:\Work Dir\\" app.exe`},
+	}
+	for _, tt := range tests {
+		if got := windowsCommandLine(tt.cmdline); got != tt.want {
+			t.Errorf("windowsCommandLine(%q) = %s, want %s", tt.cmdline, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeWindowsArg(t *testing.T) {
+	tests := []struct {
+		arg, want string
+	}{
+		{"", `""`},
+		{"plain", "plain"},
+		{`C:\Tools\app.exe`, `C:\Tools\app.exe`},
+		{"a b", `"a b"`},
+		{`C:\Program Files\`, `"C:\Program Files\\"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`a\"b`, `a\\\"b`},
+		{"tab\there", "\"tab\there\""},
+	}
+	for _, tt := range tests {
+		if got := escapeWindowsArg(tt.arg); got != tt.want {
+			t.Errorf("escapeWindowsArg(%q) = %s, want %s", tt.arg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package macro runs the ordered steps of a macro shortcut. It has no UI
+// dependencies; side effects go through a Runner supplied by the caller.
+package macro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-musetool/internal/model"
+)
+
+// DefaultProcessTimeout is used by wait_process steps that do not set Seconds
+const DefaultProcessTimeout = 60 * time.Second
+
+var (
+	// pollInterval is how often wait_process checks the process list
+	pollInterval = 500 * time.Millisecond
+	// second is the unit of MacroStep.Seconds; tests shorten it
+	second = time.Second
+)
+
+var (
+	ErrInvalidStep    = errors.New("invalid macro step")
+	ErrProcessTimeout = errors.New("timed out waiting for process")
+)
+
+// Runner performs the side effects of the steps. The UI provides the real
+// implementation; tests can provide a fake.
+type Runner interface {
+	LaunchShortcut(ref string) error
+	RunCommand(command string) error
+	OpenURL(url string) error
+	ProcessRunning(name string) (bool, error)
+}
+
+// Progress is reported before and after each step
+type Progress struct {
+	Index int // 从 0 开始的步骤序号
+	Total int
+	Step  model.MacroStep
+	Done  bool  // false: 步骤开始; true: 步骤结束
+	Err   error // 步骤结束且失败时非空
+}
+
+// StepError records which step failed
+type StepError struct {
+	Index int
+	Step  model.MacroStep
+	Err   error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %d (%s): %v", e.Index+1, e.Step.Type, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks every step for a known type and the fields it needs
+func Validate(steps []model.MacroStep) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("%w: macro has no steps", ErrInvalidStep)
+	}
+	for i, step := range steps {
+		if err := validateStep(step); err != nil {
+			return &StepError{Index: i, Step: step, Err: err}
+		}
+	}
+	return nil
+}
+
+func validateStep(step model.MacroStep) error {
+	switch step.OnFailure {
+	case "", model.OnFailureStop, model.OnFailureContinue:
+	default:
+		return fmt.Errorf("%w: unknown failure policy %q", ErrInvalidStep, step.OnFailure)
+	}
+	switch step.Type {
+	case model.StepLaunch, model.StepCommand, model.StepOpenURL, model.StepWaitProcess:
+		if step.Target == "" {
+			return fmt.Errorf("%w: %s needs a target", ErrInvalidStep, step.Type)
+		}
+	case model.StepWait:
+		if step.Seconds <= 0 {
+			return fmt.Errorf("%w: wait needs a positive number of seconds", ErrInvalidStep)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidStep, step.Type)
+	}
+	if step.Seconds < 0 {
+		return fmt.Errorf("%w: seconds must not be negative", ErrInvalidStep)
+	}
+	return nil
+}
+
+// Run executes the steps in order. A failing step stops the macro unless its
+// policy is continue; failures of continued steps are joined into the
+// returned error. Cancelling ctx stops the macro and returns ctx.Err().
+func Run(ctx context.Context, steps []model.MacroStep, runner Runner, progress func(Progress)) error {
+	if err := Validate(steps); err != nil {
+		return err
+	}
+	if progress == nil {
+		progress = func(Progress) {}
+	}
+
+	var failures []error
+	for i, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		progress(Progress{Index: i, Total: len(steps), Step: step})
+		err := runStep(ctx, step, runner)
+		progress(Progress{Index: i, Total: len(steps), Step: step, Done: true, Err: err})
+		if err == nil {
+			continue
+		}
+		// 取消不受失败策略影响
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		stepErr := &StepError{Index: i, Step: step, Err: err}
+		if step.OnFailure != model.OnFailureContinue {
+			return errors.Join(append(failures, stepErr)...)
+		}
+		failures = append(failures, stepErr)
+	}
+	return errors.Join(failures...)
+}
+
+func runStep(ctx context.Context, step model.MacroStep, runner Runner) error {
+	switch step.Type {
+	case model.StepLaunch:
+		return runner.LaunchShortcut(step.Target)
+	case model.StepCommand:
+		return runner.RunCommand(step.Target)
+	case model.StepOpenURL:
+		return runner.OpenURL(step.Target)
+	case model.StepWait:
+		return sleep(ctx, time.Duration(step.Seconds)*second)
+	case model.StepWaitProcess:
+		return waitProcess(ctx, step, runner)
+	}
+	return fmt.Errorf("%w: unknown type %q", ErrInvalidStep, step.Type)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func waitProcess(ctx context.Context, step model.MacroStep, runner Runner) error {
+	timeout := DefaultProcessTimeout
+	if step.Seconds > 0 {
+		timeout = time.Duration(step.Seconds) * second
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		running, err := runner.ProcessRunning(step.Target)
+		if err != nil {
+			return err
+		}
+		if running {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: %s", ErrProcessTimeout, step.Target)
+		}
+		if err := sleep(ctx, pollInterval); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package macro
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go-musetool/internal/model"
+)
+
+func TestMain(m *testing.M) {
+	// 以毫秒代替秒，等待步骤不拖慢测试
+	second = time.Millisecond
+	pollInterval = time.Millisecond
+	os.Exit(m.Run())
+}
+
+// fakeRunner records every call with the time it was made. Targets listed in
+// fail return errFake; a process counts as running once it has been polled
+// startsAfter times.
+type fakeRunner struct {
+	mu          sync.Mutex
+	start       time.Time
+	calls       []string
+	at          []time.Duration
+	fail        map[string]bool
+	startsAfter int
+	polls       int
+}
+
+var errFake = errors.New("fake failure")
+
+func newFakeRunner() *fakeRunner {
+	return &fakeRunner{start: time.Now(), fail: map[string]bool{}}
+}
+
+func (r *fakeRunner) record(call string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+	r.at = append(r.at, time.Since(r.start))
+	if r.fail[call] {
+		return errFake
+	}
+	return nil
+}
+
+func (r *fakeRunner) LaunchShortcut(ref string) error { return r.record("launch " + ref) }
+func (r *fakeRunner) RunCommand(command string) error { return r.record("command " + command) }
+func (r *fakeRunner) OpenURL(url string) error        { return r.record("open " + url) }
+
+func (r *fakeRunner) ProcessRunning(name string) (bool, error) {
+	if err := r.record("process " + name); err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.polls++
+	return r.polls > r.startsAfter, nil
+}
+
+func TestRunInOrder(t *testing.T) {
+	r := newFakeRunner()
+	steps := []model.MacroStep{
+		{Type: model.StepLaunch, Target: "Dev/Editor"},
+		{Type: model.StepCommand, Target: "make build"},
+		{Type: model.StepOpenURL, Target: "http://localhost:8080"},
+		{Type: model.StepLaunch, Target: "Dev/Terminal"},
+	}
+	var progress []Progress
+	if err := Run(context.Background(), steps, r, func(p Progress) { progress = append(progress, p) }); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"launch Dev/Editor", "command make build", "open http://localhost:8080", "launch Dev/Terminal"}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("calls = %q, want %q", r.calls, want)
+	}
+
+	if len(progress) != 2*len(steps) {
+		t.Fatalf("%d progress reports, want %d", len(progress), 2*len(steps))
+	}
+	for i, p := range progress {
+		if p.Index != i/2 || p.Total != len(steps) || p.Done != (i%2 == 1) || p.Err != nil {
+			t.Errorf("progress[%d] = %+v", i, p)
+		}
+	}
+}
+
+func TestWaitDelaysNextStep(t *testing.T) {
+	r := newFakeRunner()
+	steps := []model.MacroStep{
+		{Type: model.StepLaunch, Target: "A"},
+		{Type: model.StepWait, Seconds: 50},
+		{Type: model.StepLaunch, Target: "B"},
+	}
+	if err := Run(context.Background(), steps, r, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.at) != 2 {
+		t.Fatalf("calls = %q", r.calls)
+	}
+	if gap := r.at[1] - r.at[0]; gap < 50*second {
+		t.Errorf("second launch came %v after the first, want at least %v", gap, 50*second)
+	}
+}
+
+func TestWaitProcess(t *testing.T) {
+	r := newFakeRunner()
+	r.startsAfter = 3
+	steps := []model.MacroStep{
+		{Type: model.StepWaitProcess, Target: "server"},
+		{Type: model.StepLaunch, Target: "Dev/Client"},
+	}
+	if err := Run(context.Background(), steps, r, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"process server", "process server", "process server", "process server", "launch Dev/Client"}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("calls = %q, want %q", r.calls, want)
+	}
+
+	r = newFakeRunner()
+	r.startsAfter = 1 << 30
+	steps[0].Seconds = 20
+	err := Run(context.Background(), steps, r, nil)
+	if !errors.Is(err, ErrProcessTimeout) {
+		t.Errorf("Run = %v, want %v", err, ErrProcessTimeout)
+	}
+	if last := r.calls[len(r.calls)-1]; last != "process server" {
+		t.Errorf("step after a timed out wait ran: %s", last)
+	}
+}
+
+func TestAbortOnError(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		wantCalls []string
+	}{
+		{"default stops", "", []string{"launch A", "command fail"}},
+		{"stop", model.OnFailureStop, []string{"launch A", "command fail"}},
+		{"continue", model.OnFailureContinue, []string{"launch A", "command fail", "launch B"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRunner()
+			r.fail["command fail"] = true
+			steps := []model.MacroStep{
+				{Type: model.StepLaunch, Target: "A"},
+				{Type: model.StepCommand, Target: "fail", OnFailure: tt.policy},
+				{Type: model.StepLaunch, Target: "B"},
+			}
+			err := Run(context.Background(), steps, r, nil)
+			if !reflect.DeepEqual(r.calls, tt.wantCalls) {
+				t.Errorf("calls = %q, want %q", r.calls, tt.wantCalls)
+			}
+			var stepErr *StepError
+			if !errors.As(err, &stepErr) || stepErr.Index != 1 || !errors.Is(err, errFake) {
+				t.Errorf("Run = %v, want the failure of step 2", err)
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	r := newFakeRunner()
+	ctx, cancel := context.WithCancel(context.Background())
+	steps := []model.MacroStep{
+		{Type: model.StepLaunch, Target: "A"},
+		{Type: model.StepWait, Seconds: 10000},
+		{Type: model.StepLaunch, Target: "B"},
+	}
+	var failed Progress
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	err := Run(ctx, steps, r, func(p Progress) {
+		if p.Done && p.Err != nil {
+			failed = p
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run = %v, want %v", err, context.Canceled)
+	}
+	if !reflect.DeepEqual(r.calls, []string{"launch A"}) {
+		t.Errorf("calls = %q", r.calls)
+	}
+	if failed.Index != 1 {
+		t.Errorf("cancelled step reported as %+v", failed)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []model.MacroStep
+		ok    bool
+	}{
+		{"empty", nil, false},
+		{"launch", []model.MacroStep{{Type: model.StepLaunch, Target: "Dev/A"}}, true},
+		{"launch without target", []model.MacroStep{{Type: model.StepLaunch}}, false},
+		{"wait", []model.MacroStep{{Type: model.StepWait, Seconds: 2}}, true},
+		{"wait without seconds", []model.MacroStep{{Type: model.StepWait}}, false},
+		{"wait_process default timeout", []model.MacroStep{{Type: model.StepWaitProcess, Target: "code"}}, true},
+		{"negative timeout", []model.MacroStep{{Type: model.StepWaitProcess, Target: "code", Seconds: -1}}, false},
+		{"unknown type", []model.MacroStep{{Type: "teleport", Target: "x"}}, false},
+		{"unknown policy", []model.MacroStep{{Type: model.StepOpenURL, Target: "https://a", OnFailure: "retry"}}, false},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.steps)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: Validate = %v", tt.name, err)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidStep) {
+			t.Errorf("%s: Validate = %v, want %v", tt.name, err, ErrInvalidStep)
+		}
+	}
+
+	// 无效的宏一步也不执行
+	r := newFakeRunner()
+	steps := []model.MacroStep{{Type: model.StepLaunch, Target: "A"}, {Type: model.StepWait}}
+	if err := Run(context.Background(), steps, r, nil); err == nil || len(r.calls) != 0 {
+		t.Errorf("Run of an invalid macro = %v, calls %q", err, r.calls)
+	}
+}
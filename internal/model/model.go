@@ -7,6 +7,13 @@ const (
 	ThemeDark   = "Dark"
 )
 
+// Always-on-top policies of the main window
+const (
+	TopmostAlways = "always" // 默认：没有子窗口打开时总在最前
+	TopmostNever  = "never"
+	TopmostDocked = "docked" // 只在停靠到屏幕边缘时置顶
+)
+
 // Config represents the application configuration structure.
 type Config struct {
 	ThemePreference string `json:"theme_preference"` // "dark", "light", "system"
@@ -30,14 +37,53 @@ type Config struct {
 	DebugMode bool `json:"debug_mode"` // 是否开启调试日志
 
 	// 开机自启动
-	AutoStart bool `json:"auto_start"` // 是否开机自启动
+	AutoStart   bool `json:"auto_start"`   // 是否开机自启动
+	StartHidden bool `json:"start_hidden"` // 自启动时隐藏到托盘，不显示主窗口
+	StartDelay  int  `json:"start_delay"`  // 自启动后延迟的秒数，0 表示立即启动
+
+	// 注册 musetool:// 链接处理程序
+	URLHandler bool `json:"url_handler"` // 是否处理 musetool:// 深度链接
 
 	// 最小化到托盘
 	MinimizeToTray bool `json:"minimize_to_tray"` // 关闭窗口时是否最小化到托盘而不是退出
 
+	// 主窗口置顶策略，空值表示 TopmostAlways
+	AlwaysOnTop string `json:"always_on_top,omitempty"`
+
+	// 停靠到屏幕边缘和自动隐藏（0 和空值表示使用默认设置）
+	DockDisabled         bool     `json:"dock_disabled"`           // 不停靠到屏幕边缘
+	DockEdges            []string `json:"dock_edges,omitempty"`    // 允许停靠的边缘 "top" "bottom" "left" "right"，为空表示全部
+	DockAutoHideDisabled bool     `json:"dock_auto_hide_disabled"` // 停靠后不自动隐藏
+	DockHideDelay        int      `json:"dock_hide_delay"`         // 鼠标离开后隐藏的延迟（毫秒），0 表示默认 50
+	DockShowDelay        int      `json:"dock_show_delay"`         // 鼠标停在边缘多久后显示（毫秒）
+	DockRevealZone       int      `json:"dock_reveal_zone"`        // 边缘热区的宽度（像素），0 表示默认 1
+	DockRevealHotkeyOnly bool     `json:"dock_reveal_hotkey_only"` // 隐藏后只能通过热键或托盘显示
+
 	// 关闭对话框已显示
 	CloseDialogShown bool `json:"close_dialog_shown"` // 是否已显示过首次关闭对话框
 
+	// 本地自动化 API（仅监听 127.0.0.1）
+	APIEnabled bool `json:"api_enabled"` // 是否启用本地 HTTP API
+	APIPort    int  `json:"api_port"`    // 监听端口，0 表示使用默认端口
+
+	// 参数化快捷方式 {input:Name} 的输入历史，按占位符名称保存，最近的在前
+	InputHistory map[string][]string `json:"input_history,omitempty"`
+
+	// 启动历史
+	HistoryDisabled      bool `json:"history_disabled"`       // 是否停止记录启动历史
+	HistoryRetentionDays int  `json:"history_retention_days"` // 保留天数，0 表示永久保留
+	SortByUsage          bool `json:"sort_by_usage"`          // 分组内按使用次数排序
+
+	// 在终端中运行的快捷方式使用的终端程序，空表示自动检测
+	Terminal string `json:"terminal,omitempty"`
+
+	// 全局热键（如 "Ctrl+Alt+Space"），空表示不使用
+	HotkeyToggle string `json:"hotkey_toggle,omitempty"` // 显示/隐藏主窗口
+	HotkeySearch string `json:"hotkey_search,omitempty"` // 显示主窗口并聚焦搜索框
+
+	// 打开规则：匹配的网址和文件交给指定的命令，而不是系统默认程序
+	Routes []Route `json:"routes,omitempty"`
+
 	Groups []Group `json:"groups"`
 }
 
@@ -45,11 +91,94 @@ type Config struct {
 type Group struct {
 	Name      string     `json:"name"`
 	Shortcuts []Shortcut `json:"shortcuts"`
+	Workspace *Workspace `json:"workspace,omitempty"` // "全部启动" 设置，nil 表示默认
+	Hotkey    string     `json:"hotkey,omitempty"`    // 显示主窗口并切换到该分组的全局热键
+}
+
+// Workspace controls how "Launch all" starts the shortcuts of a group.
+type Workspace struct {
+	Shortcuts   []string `json:"shortcuts,omitempty"`   // 参与启动的快捷方式名（按启动顺序），为空表示全部
+	DelayMs     int      `json:"delayMs,omitempty"`     // 相邻两次启动之间的间隔（毫秒）
+	Concurrency int      `json:"concurrency,omitempty"` // 同时进行的启动数，0 表示逐个启动
 }
 
-// Shortcut represents an executable or URL item.
+// Shortcut kinds
+const (
+	KindApplication = "application" // 可执行文件或 .lnk 快捷方式
+	KindFile        = "file"        // 用默认程序打开的文档
+	KindFolder      = "folder"      // 在文件管理器中打开的目录
+	KindURL         = "url"         // 网址或其它 URL 协议
+	KindCommand     = "command"     // 通过系统 shell 执行的命令行
+	KindSnippet     = "snippet"     // 点击后复制到剪贴板的文本
+	KindMacro       = "macro"       // 按顺序执行的多个步骤
+	KindSearch      = "search"      // 网页搜索：Path 是含 {query} 的网址模板
+)
+
+// Shortcut represents a launchable item. Kind selects which of the payload
+// fields below are used.
 type Shortcut struct {
+	ID       string `json:"id,omitempty"` // 稳定标识，用于启动历史（改名不变）
 	Name     string `json:"name"`
-	Path     string `json:"path"`
-	IconPath string `json:"iconPath"` // 绝对路径到提取的图标
+	Kind     string `json:"kind,omitempty"` // 为空时按 Path 自动识别（兼容旧配置）
+	Path     string `json:"path"`           // application / file / folder / url / search
+	IconPath string `json:"iconPath"`       // 绝对路径到提取的图标
+
+	Args    string `json:"args,omitempty"`    // application: 启动参数
+	WorkDir string `json:"workDir,omitempty"` // application / command: 工作目录
+	Command string `json:"command,omitempty"` // command: 命令行
+	Text    string `json:"text,omitempty"`    // snippet: 文本内容
+	Keyword string `json:"keyword,omitempty"` // search: 在搜索框中输入的关键字，如 "g"
+
+	AutoPaste bool `json:"autoPaste,omitempty"` // snippet: 复制后粘贴到之前使用的窗口
+
+	Steps []MacroStep `json:"steps,omitempty"` // macro: 步骤列表
+
+	FocusExisting bool `json:"focusExisting,omitempty"` // application: 已在运行时切换到现有窗口，不再启动新实例
+
+	RunInTerminal bool `json:"runInTerminal,omitempty"` // application / command: 在终端窗口中运行
+	KeepOpen      bool `json:"keepOpen,omitempty"`      // 在终端中运行时，程序退出后保留窗口
+
+	Schedule *Schedule `json:"schedule,omitempty"` // 自动启动设置，nil 表示不自动启动
+
+	Hotkey string `json:"hotkey,omitempty"` // 全局热键，如 "Ctrl+Alt+T"，为空表示不使用
+}
+
+// Schedule launches a shortcut automatically.
+type Schedule struct {
+	Cron    string `json:"cron,omitempty"`    // 5 字段 cron 表达式，如 "0 16 * * fri"
+	AtStart bool   `json:"atStart,omitempty"` // MuseTool 启动时运行
+	CatchUp bool   `json:"catchUp,omitempty"` // 错过（关机、空闲）后补运行一次
+}
+
+// Route sends URLs and files to a custom command. Every criterion that is
+// set must match; the first matching route in the list wins.
+type Route struct {
+	Scheme   string `json:"scheme,omitempty"`   // URL 协议，如 "ssh"
+	Host     string `json:"host,omitempty"`     // 主机名通配符，如 "*.internal.corp"
+	Ext      string `json:"ext,omitempty"`      // 文件扩展名，如 ".md"
+	Command  string `json:"command"`            // 命令模板，{target} 替换为网址或路径
+	Terminal bool   `json:"terminal,omitempty"` // 在终端中运行命令
+}
+
+// Macro step types
+const (
+	StepLaunch      = "launch"       // Target: 要启动的快捷方式 "Group/Name"
+	StepCommand     = "command"      // Target: 命令行
+	StepWait        = "wait"         // Seconds: 等待秒数
+	StepWaitProcess = "wait_process" // Target: 进程名; Seconds: 超时（0 表示默认值）
+	StepOpenURL     = "open_url"     // Target: URL
+)
+
+// Macro step failure policies
+const (
+	OnFailureStop     = "stop"
+	OnFailureContinue = "continue"
+)
+
+// MacroStep is one step of a macro shortcut
+type MacroStep struct {
+	Type      string `json:"type"`
+	Target    string `json:"target,omitempty"`
+	Seconds   int    `json:"seconds,omitempty"`
+	OnFailure string `json:"onFailure,omitempty"` // 为空时等同于 stop
 }
@@ -0,0 +1,20 @@
+// Package paste types the clipboard into the window the user was working in
+// before they clicked the launcher.
+package paste
+
+import (
+	"errors"
+	"time"
+)
+
+// focusDelay gives the target window time to become active before the
+// keystroke is sent
+const focusDelay = 100 * time.Millisecond
+
+var ErrNoWindow = errors.New("no window to paste into")
+
+// IntoPreviousWindow activates the window that was in front of ours (or the
+// current foreground window if it is not ours) and sends Ctrl+V to it
+func IntoPreviousWindow() error {
+	return intoPreviousWindow()
+}
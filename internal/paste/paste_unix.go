@@ -0,0 +1,77 @@
+//go:build !windows
+
+package paste
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// intoPreviousWindow uses xdotool (X11). The window below ours in the
+// window manager's stacking order is the one the user came from.
+func intoPreviousWindow() error {
+	if _, err := exec.LookPath("xdotool"); err != nil {
+		return fmt.Errorf("auto-paste needs xdotool: %w", err)
+	}
+	active, err := xdotool("getactivewindow")
+	if err != nil {
+		return err
+	}
+	target := active
+	if pid, err := xdotool("getwindowpid", active); err == nil && pid == strconv.Itoa(os.Getpid()) {
+		if target, err = windowBelow(active); err != nil {
+			return err
+		}
+	}
+
+	if _, err := xdotool("windowactivate", "--sync", target); err != nil {
+		return err
+	}
+	time.Sleep(focusDelay)
+	_, err = xdotool("key", "--clearmodifiers", "ctrl+v")
+	return err
+}
+
+func xdotool(args ...string) (string, error) {
+	out, err := exec.Command("xdotool", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("xdotool %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// windowBelow returns the window stacked directly below id, read from the
+// _NET_CLIENT_LIST_STACKING root property (bottom to top)
+func windowBelow(id string) (string, error) {
+	want, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.Command("xprop", "-root", "_NET_CLIENT_LIST_STACKING").Output()
+	if err != nil {
+		return "", fmt.Errorf("xprop: %w", err)
+	}
+	_, list, ok := strings.Cut(string(out), "#")
+	if !ok {
+		return "", ErrNoWindow
+	}
+	var below uint64
+	for _, field := range strings.Split(list, ",") {
+		w, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(field), "0x"), 16, 64)
+		if err != nil {
+			continue
+		}
+		if w == want {
+			if below == 0 {
+				return "", ErrNoWindow
+			}
+			return strconv.FormatUint(below, 10), nil
+		}
+		below = w
+	}
+	return "", ErrNoWindow
+}
@@ -0,0 +1,78 @@
+package paste
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32                  = windows.NewLazySystemDLL("user32.dll")
+	procGetForegroundWindow = user32.NewProc("GetForegroundWindow")
+	procGetWindow           = user32.NewProc("GetWindow")
+	procGetWindowLongW      = user32.NewProc("GetWindowLongW")
+	procGetWindowTextLength = user32.NewProc("GetWindowTextLengthW")
+	procSetForegroundWindow = user32.NewProc("SetForegroundWindow")
+	procKeybdEvent          = user32.NewProc("keybd_event")
+)
+
+const (
+	gwHwndNext     = 2   // GW_HWNDNEXT
+	gwOwner        = 4   // GW_OWNER
+	gwlExStyle     = -20 // GWL_EXSTYLE
+	wsExToolWindow = 0x00000080
+	vkControl      = 0x11
+	vkV            = 0x56
+	keyeventfKeyUp = 0x0002
+)
+
+func intoPreviousWindow() error {
+	fg, _, _ := procGetForegroundWindow.Call()
+	target := fg
+	if fg == 0 || ownWindow(fg) {
+		// 按 Z 序向下找到第一个属于其他程序的主窗口，即点击启动器之前的窗口
+		target = 0
+		for h := fg; h != 0; {
+			h, _, _ = procGetWindow.Call(h, gwHwndNext)
+			if h != 0 && !ownWindow(h) && isAppWindow(h) {
+				target = h
+				break
+			}
+		}
+	}
+	if target == 0 {
+		return ErrNoWindow
+	}
+
+	procSetForegroundWindow.Call(target)
+	time.Sleep(focusDelay)
+	procKeybdEvent.Call(vkControl, 0, 0, 0)
+	procKeybdEvent.Call(vkV, 0, 0, 0)
+	procKeybdEvent.Call(vkV, 0, keyeventfKeyUp, 0)
+	procKeybdEvent.Call(vkControl, 0, keyeventfKeyUp, 0)
+	return nil
+}
+
+func ownWindow(hwnd uintptr) bool {
+	var pid uint32
+	windows.GetWindowThreadProcessId(windows.HWND(hwnd), &pid)
+	return int(pid) == os.Getpid()
+}
+
+// isAppWindow 只接受可见、有标题、没有所有者的普通窗口
+func isAppWindow(hwnd uintptr) bool {
+	if !windows.IsWindowVisible(windows.HWND(hwnd)) {
+		return false
+	}
+	if owner, _, _ := procGetWindow.Call(hwnd, gwOwner); owner != 0 {
+		return false
+	}
+	index := int32(gwlExStyle)
+	exStyle, _, _ := procGetWindowLongW.Call(hwnd, uintptr(index))
+	if exStyle&wsExToolWindow != 0 {
+		return false
+	}
+	length, _, _ := procGetWindowTextLength.Call(hwnd)
+	return length > 0
+}
@@ -0,0 +1,37 @@
+package process
+
+import "sync"
+
+// Fake is an in-memory Backend for tests. Processes whose PID is in Windows
+// can be focused; every successful Focus call is recorded in Focused.
+type Fake struct {
+	mu      sync.Mutex
+	Procs   []Process
+	Windows map[int]bool
+	Focused []int
+	Err     error // 非 nil 时所有调用都返回该错误
+}
+
+// Processes implements Backend
+func (f *Fake) Processes() ([]Process, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return append([]Process{}, f.Procs...), nil
+}
+
+// Focus implements Backend
+func (f *Fake) Focus(pid int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return false, f.Err
+	}
+	if !f.Windows[pid] {
+		return false, nil
+	}
+	f.Focused = append(f.Focused, pid)
+	return true, nil
+}
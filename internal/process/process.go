@@ -0,0 +1,134 @@
+// Package process answers questions about running processes and brings
+// their windows to the front. The platform specific work is done by a
+// Backend; tests can swap in a Fake.
+package process
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Process is a running process. Exe is the full executable path when the
+// platform exposes it, otherwise just the executable name.
+type Process struct {
+	PID int
+	Exe string
+}
+
+// Backend lists processes and focuses their windows
+type Backend interface {
+	// Processes returns the running processes
+	Processes() ([]Process, error)
+	// Focus brings a top-level window of the process to the front and
+	// reports whether one was found
+	Focus(pid int) (bool, error)
+}
+
+var (
+	backendMu sync.RWMutex
+	backend   Backend = platformBackend{}
+)
+
+// SetBackend replaces the backend and returns the previous one, so tests can
+// restore it afterwards
+func SetBackend(b Backend) Backend {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	prev := backend
+	backend = b
+	return prev
+}
+
+func current() Backend {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return backend
+}
+
+// List returns the running processes
+func List() ([]Process, error) {
+	return current().Processes()
+}
+
+// IsRunning reports whether a process with the given executable name exists.
+// The match ignores case, directories and a trailing ".exe", so "code",
+// "Code.exe" and "C:\...\Code.exe" are all equivalent.
+func IsRunning(name string) (bool, error) {
+	want := normalize(name)
+	procs, err := List()
+	if err != nil {
+		return false, err
+	}
+	for _, p := range procs {
+		if normalize(p.Exe) == want {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MatchPath returns the processes started from the executable at path. When
+// only the name of a process is known (no permission to read its path) the
+// names are compared instead.
+func MatchPath(procs []Process, path string) []Process {
+	wantPath, wantName := normalizePath(path), normalize(path)
+	var matched []Process
+	for _, p := range procs {
+		if isAbs(p.Exe) {
+			if normalizePath(p.Exe) == wantPath {
+				matched = append(matched, p)
+			}
+		} else if normalize(p.Exe) == wantName {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
+
+// Focus brings a window of a process started from path to the front. It
+// reports false when no such process has a window, so the caller can launch
+// a new instance instead.
+func Focus(path string) (bool, error) {
+	b := current()
+	procs, err := b.Processes()
+	if err != nil {
+		return false, err
+	}
+	for _, p := range MatchPath(procs, path) {
+		focused, err := b.Focus(p.PID)
+		if err != nil {
+			return false, err
+		}
+		if focused {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func normalize(name string) string {
+	// Windows 路径在 Linux 上也要能取到文件名
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.ToLower(name)
+	return strings.TrimSuffix(name, ".exe")
+}
+
+// normalizePath makes paths comparable: forward slashes, cleaned, and case
+// insensitive on Windows
+func normalizePath(path string) string {
+	path = filepath.ToSlash(filepath.Clean(strings.ReplaceAll(path, `\`, "/")))
+	if runtime.GOOS == "windows" {
+		path = strings.ToLower(path)
+	}
+	return path
+}
+
+// isAbs accepts both Unix and Windows absolute paths regardless of platform
+func isAbs(path string) bool {
+	if strings.HasPrefix(path, "/") || strings.HasPrefix(path, `\\`) {
+		return true
+	}
+	return len(path) >= 3 && path[1] == ':' && (path[2] == '\\' || path[2] == '/')
+}
@@ -0,0 +1,83 @@
+package process
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// useFake installs f as the backend for the duration of the test
+func useFake(t *testing.T, f *Fake) {
+	prev := SetBackend(f)
+	t.Cleanup(func() { SetBackend(prev) })
+}
+
+func pids(procs []Process) []int {
+	var out []int
+	for _, p := range procs {
+		out = append(out, p.PID)
+	}
+	return out
+}
+
+func TestMatchPath(t *testing.T) {
+	procs := []Process{
+		{PID: 1, Exe: "/usr/bin/code"},
+		{PID: 2, Exe: "/opt/other/code"},
+		{PID: 3, Exe: "code"}, // 无权读取路径时只有名称
+		{PID: 4, Exe: `C:\Program Files\Microsoft VS Code\Code.exe`},
+		{PID: 5, Exe: "Code.exe"},
+		{PID: 6, Exe: "/usr/bin/vim"},
+	}
+	tests := []struct {
+		path string
+		want []int
+	}{
+		// 只有名称时忽略大小写和 .exe
+		{"/usr/bin/code", []int{1, 3, 5}},
+		{"/usr/bin/../bin/code", []int{1, 3, 5}},
+		{`C:\Program Files\Microsoft VS Code\Code.exe`, []int{3, 4, 5}},
+		{"/usr/bin/missing", nil},
+	}
+	for _, tt := range tests {
+		if got := pids(MatchPath(procs, tt.path)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MatchPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestIsRunning(t *testing.T) {
+	useFake(t, &Fake{Procs: []Process{{PID: 10, Exe: `C:\Apps\Code.exe`}, {PID: 11, Exe: "/usr/bin/firefox"}}})
+	for name, want := range map[string]bool{
+		"code": true, "Code.exe": true, `D:\elsewhere\CODE.EXE`: true,
+		"firefox": true, "chrome": false,
+	} {
+		if got, err := IsRunning(name); err != nil || got != want {
+			t.Errorf("IsRunning(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+}
+
+func TestFocus(t *testing.T) {
+	// 第一个匹配的进程没有窗口（例如后台进程），应继续尝试下一个
+	f := &Fake{
+		Procs:   []Process{{PID: 1, Exe: "/usr/bin/code"}, {PID: 2, Exe: "/usr/bin/code"}, {PID: 3, Exe: "/usr/bin/vim"}},
+		Windows: map[int]bool{2: true, 3: true},
+	}
+	useFake(t, f)
+
+	if focused, err := Focus("/usr/bin/code"); !focused || err != nil {
+		t.Errorf("Focus(code) = %v, %v", focused, err)
+	}
+	if !reflect.DeepEqual(f.Focused, []int{2}) {
+		t.Errorf("focused %v, want [2]", f.Focused)
+	}
+	if focused, err := Focus("/usr/bin/emacs"); focused || err != nil {
+		t.Errorf("Focus(emacs) = %v, %v, want false", focused, err)
+	}
+
+	f.Err = errors.New("access denied")
+	if _, err := Focus("/usr/bin/code"); !errors.Is(err, f.Err) {
+		t.Errorf("Focus with a failing backend = %v", err)
+	}
+}
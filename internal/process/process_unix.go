@@ -0,0 +1,88 @@
+//go:build !windows
+
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// platformBackend reads /proc and focuses windows through the EWMH
+// _NET_ACTIVE_WINDOW request, sent by wmctrl or xdotool
+type platformBackend struct{}
+
+// Processes reads processes from /proc. comm is truncated to 15 characters,
+// so the executable link is preferred when it is readable.
+func (platformBackend) Processes() ([]Process, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	var procs []Process
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join("/proc", e.Name())
+		if exe, err := os.Readlink(filepath.Join(dir, "exe")); err == nil {
+			procs = append(procs, Process{PID: pid, Exe: strings.TrimSuffix(exe, " (deleted)")})
+			continue
+		}
+		if comm, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil {
+			procs = append(procs, Process{PID: pid, Exe: strings.TrimSpace(string(comm))})
+		}
+	}
+	return procs, nil
+}
+
+// Focus activates a window whose _NET_WM_PID is pid. Without wmctrl or
+// xdotool (or on Wayland) no window is found and the caller launches instead.
+func (platformBackend) Focus(pid int) (bool, error) {
+	if wid := findWindowWmctrl(pid); wid != "" {
+		return exec.Command("wmctrl", "-i", "-a", wid).Run() == nil, nil
+	}
+	if _, err := exec.LookPath("xdotool"); err == nil {
+		out, err := exec.Command("xdotool", "search", "--onlyvisible", "--pid", strconv.Itoa(pid)).Output()
+		if err != nil {
+			return false, nil // 没有匹配的窗口时 xdotool 返回非零
+		}
+		if wid := firstLine(out); wid != "" {
+			return exec.Command("xdotool", "windowactivate", wid).Run() == nil, nil
+		}
+	}
+	return false, nil
+}
+
+// findWindowWmctrl looks the pid up in `wmctrl -lp`, which lists the
+// _NET_CLIENT_LIST windows with their _NET_WM_PID
+func findWindowWmctrl(pid int) string {
+	if _, err := exec.LookPath("wmctrl"); err != nil {
+		return ""
+	}
+	out, err := exec.Command("wmctrl", "-lp").Output()
+	if err != nil {
+		return ""
+	}
+	want := strconv.Itoa(pid)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		// 格式: <窗口 ID> <桌面> <PID> <主机> <标题>
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 3 && fields[2] == want {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+func firstLine(out []byte) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(line)
+}
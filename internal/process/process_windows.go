@@ -0,0 +1,124 @@
+package process
+
+import (
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32                  = windows.NewLazySystemDLL("user32.dll")
+	procGetWindow           = user32.NewProc("GetWindow")
+	procIsIconic            = user32.NewProc("IsIconic")
+	procShowWindow          = user32.NewProc("ShowWindow")
+	procSetForegroundWindow = user32.NewProc("SetForegroundWindow")
+)
+
+const (
+	gwOwner   = 4 // GW_OWNER
+	swRestore = 9 // SW_RESTORE
+
+	imagePathSize = 1024 // 超长路径查询失败时退回进程名
+)
+
+// EnumWindows 回调只创建一次（回调数量有上限），查找状态放在包级变量中由 focusMu 保护
+var (
+	focusMu    sync.Mutex
+	focusPID   int
+	focusFound windows.HWND
+
+	enumWindowsCallback = windows.NewCallback(func(hwnd windows.HWND, _ uintptr) uintptr {
+		var owner uint32
+		if _, err := windows.GetWindowThreadProcessId(hwnd, &owner); err != nil || int(owner) != focusPID {
+			return 1
+		}
+		// 只要主窗口：可见且没有所有者（排除工具窗口和对话框）
+		if !windows.IsWindowVisible(hwnd) {
+			return 1
+		}
+		if ownerHwnd, _, _ := procGetWindow.Call(uintptr(hwnd), gwOwner); ownerHwnd != 0 {
+			return 1
+		}
+		focusFound = hwnd
+		return 0 // 停止枚举
+	})
+)
+
+// platformBackend uses a toolhelp snapshot for processes and EnumWindows to
+// find their top-level windows
+type platformBackend struct{}
+
+// Processes enumerates processes with a toolhelp snapshot. The snapshot only
+// has executable names, so the full path is queried per process; processes
+// we may not open (services, elevated programs) keep just the name.
+func (platformBackend) Processes() ([]Process, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	if err := windows.Process32First(snapshot, &entry); err != nil {
+		return nil, err
+	}
+
+	var procs []Process
+	for {
+		exe := imagePath(entry.ProcessID)
+		if exe == "" {
+			exe = windows.UTF16ToString(entry.ExeFile[:])
+		}
+		procs = append(procs, Process{PID: int(entry.ProcessID), Exe: exe})
+		if err := windows.Process32Next(snapshot, &entry); err != nil {
+			if err == windows.ERROR_NO_MORE_FILES {
+				break
+			}
+			return nil, err
+		}
+	}
+	return procs, nil
+}
+
+// imagePath returns the full executable path of a process, or "" if it
+// cannot be opened
+func imagePath(pid uint32) string {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(h)
+
+	buf := make([]uint16, imagePathSize)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(h, 0, &buf[0], &size); err != nil {
+		return ""
+	}
+	return windows.UTF16ToString(buf[:size])
+}
+
+// Focus restores and activates the first visible, unowned top-level window
+// of the process
+func (platformBackend) Focus(pid int) (bool, error) {
+	focusMu.Lock()
+	focusPID, focusFound = pid, 0
+	// 回调返回 0 中止枚举时 EnumWindows 也会返回错误，以 focusFound 为准
+	windows.EnumWindows(enumWindowsCallback, nil)
+	found := focusFound
+	focusMu.Unlock()
+
+	if found == 0 {
+		return false, nil
+	}
+
+	if iconic, _, _ := procIsIconic.Call(uintptr(found)); iconic != 0 {
+		procShowWindow.Call(uintptr(found), swRestore)
+	}
+	ok, _, err := procSetForegroundWindow.Call(uintptr(found))
+	if ok == 0 {
+		return false, err
+	}
+	return true, nil
+}
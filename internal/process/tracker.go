@@ -0,0 +1,114 @@
+package process
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tracker reports when programs started from watched executables start or
+// exit. Neither Windows nor Linux notifies an unprivileged process when other
+// processes start (that takes WMI or the netlink process connector, which
+// needs CAP_NET_ADMIN), so the tracker scans the process list: right after
+// Rescan, which callers use after launching something, and every interval to
+// notice programs started or closed elsewhere. Only changes are reported.
+type Tracker struct {
+	interval time.Duration
+	onChange func(path string, running bool)
+
+	mu      sync.Mutex
+	running map[string]bool // 跟踪的路径 → 上一次扫描的结果
+	rescan  chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewTracker creates a tracker that calls onChange from its own goroutine
+func NewTracker(interval time.Duration, onChange func(path string, running bool)) *Tracker {
+	return &Tracker{
+		interval: interval,
+		onChange: onChange,
+		running:  map[string]bool{},
+		rescan:   make(chan struct{}, 1),
+	}
+}
+
+// Start scans in the background until Stop is called
+func (t *Tracker) Start() {
+	t.stop, t.done = make(chan struct{}), make(chan struct{})
+	go t.loop()
+}
+
+// Stop ends the background scans and waits for the current one to finish
+func (t *Tracker) Stop() {
+	close(t.stop)
+	<-t.done
+}
+
+func (t *Tracker) loop() {
+	defer close(t.done)
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+		case <-t.rescan:
+		}
+		// 列出进程失败时等下一次扫描
+		t.Scan()
+	}
+}
+
+// Watch starts tracking path and returns its state at the last scan. A path
+// that was not tracked yet is scanned right away.
+func (t *Tracker) Watch(path string) bool {
+	t.mu.Lock()
+	running, ok := t.running[path]
+	if !ok {
+		t.running[path] = false
+	}
+	t.mu.Unlock()
+	if !ok {
+		t.Rescan()
+	}
+	return running
+}
+
+// Rescan asks the background goroutine to scan without waiting for the
+// interval
+func (t *Tracker) Rescan() {
+	select {
+	case t.rescan <- struct{}{}:
+	default: // 已有待进行的扫描
+	}
+}
+
+// Scan lists the processes once and reports the tracked paths whose state
+// changed, in path order
+func (t *Tracker) Scan() error {
+	procs, err := List()
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	var changed []string
+	for path, was := range t.running {
+		if now := len(MatchPath(procs, path)) > 0; now != was {
+			t.running[path] = now
+			changed = append(changed, path)
+		}
+	}
+	states := make([]bool, len(changed))
+	sort.Strings(changed)
+	for i, path := range changed {
+		states[i] = t.running[path]
+	}
+	t.mu.Unlock()
+
+	for i, path := range changed {
+		t.onChange(path, states[i])
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package process
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type change struct {
+	path    string
+	running bool
+}
+
+// recorder collects the notifications of a tracker
+type recorder struct {
+	mu      sync.Mutex
+	changes []change
+	notify  chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{notify: make(chan struct{}, 16)}
+}
+
+func (r *recorder) onChange(path string, running bool) {
+	r.mu.Lock()
+	r.changes = append(r.changes, change{path, running})
+	r.mu.Unlock()
+	r.notify <- struct{}{}
+}
+
+func (r *recorder) take() []change {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	got := r.changes
+	r.changes = nil
+	return got
+}
+
+func (f *Fake) set(procs ...Process) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Procs = procs
+}
+
+func TestTrackerReportsStartAndExit(t *testing.T) {
+	f := &Fake{}
+	useFake(t, f)
+	r := newRecorder()
+	tracker := NewTracker(time.Hour, r.onChange)
+
+	if tracker.Watch("/usr/bin/code") || tracker.Watch("/usr/bin/vim") {
+		t.Fatal("Watch reported a path as running before any scan")
+	}
+	tracker.Scan()
+	if got := r.take(); len(got) != 0 {
+		t.Errorf("changes without any process: %v", got)
+	}
+
+	f.set(Process{PID: 1, Exe: "/usr/bin/code"}, Process{PID: 2, Exe: "/usr/bin/vim"})
+	tracker.Scan()
+	if got, want := r.take(), []change{{"/usr/bin/code", true}, {"/usr/bin/vim", true}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after start: %v, want %v", got, want)
+	}
+	if !tracker.Watch("/usr/bin/code") {
+		t.Error("Watch does not return the last state")
+	}
+
+	// 状态不变时不通知
+	f.set(Process{PID: 1, Exe: "/usr/bin/code"}, Process{PID: 3, Exe: "/usr/bin/vim"})
+	tracker.Scan()
+	if got := r.take(); len(got) != 0 {
+		t.Errorf("changes while nothing changed: %v", got)
+	}
+
+	f.set(Process{PID: 3, Exe: "/usr/bin/vim"})
+	tracker.Scan()
+	if got, want := r.take(), []change{{"/usr/bin/code", false}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after exit: %v, want %v", got, want)
+	}
+
+	f.Err = errors.New("access denied")
+	if err := tracker.Scan(); err == nil {
+		t.Error("Scan hid the backend error")
+	}
+	if got := r.take(); len(got) != 0 {
+		t.Errorf("changes after a failed scan: %v", got)
+	}
+}
+
+func TestTrackerScansOnWatchAndRescan(t *testing.T) {
+	f := &Fake{Procs: []Process{{PID: 1, Exe: "/usr/bin/code"}}}
+	useFake(t, f)
+	r := newRecorder()
+	tracker := NewTracker(time.Hour, r.onChange)
+	tracker.Start()
+	defer tracker.Stop()
+
+	wait := func(what string) {
+		t.Helper()
+		select {
+		case <-r.notify:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no notification after %s", what)
+		}
+	}
+
+	// 新路径不必等待间隔
+	tracker.Watch("/usr/bin/code")
+	wait("Watch")
+	if got := r.take(); !reflect.DeepEqual(got, []change{{"/usr/bin/code", true}}) {
+		t.Errorf("after Watch: %v", got)
+	}
+
+	f.set()
+	tracker.Rescan()
+	wait("Rescan")
+	if got := r.take(); !reflect.DeepEqual(got, []change{{"/usr/bin/code", false}}) {
+		t.Errorf("after Rescan: %v", got)
+	}
+}
+
+func TestTrackerInterval(t *testing.T) {
+	f := &Fake{}
+	useFake(t, f)
+	r := newRecorder()
+	tracker := NewTracker(10*time.Millisecond, r.onChange)
+	tracker.Watch("/usr/bin/code")
+	tracker.Start()
+	defer tracker.Stop()
+
+	f.set(Process{PID: 1, Exe: "/usr/bin/code"})
+	select {
+	case <-r.notify:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the periodic scan did not notice the new process")
+	}
+}
@@ -0,0 +1,84 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"* * * foo *",
+		"@yearly",
+	} {
+		if _, err := ParseCron(expr); !errors.Is(err, ErrInvalidCron) {
+			t.Errorf("ParseCron(%q) = %v, want %v", expr, err, ErrInvalidCron)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// 2024-03-04 是周一
+	tests := []struct {
+		expr string
+		from string
+		want string
+	}{
+		{"* * * * *", "2024-03-04 10:00", "2024-03-04 10:01"},
+		{"30 9 * * *", "2024-03-04 09:30", "2024-03-05 09:30"},
+		{"30 9 * * *", "2024-03-04 09:29", "2024-03-04 09:30"},
+		{"*/15 * * * *", "2024-03-04 10:01", "2024-03-04 10:15"},
+		{"5/20 * * * *", "2024-03-04 10:26", "2024-03-04 10:45"},
+		{"0 8-18/2 * * *", "2024-03-04 18:00", "2024-03-05 08:00"},
+		{"0 9 * * 1-5", "2024-03-08 10:00", "2024-03-11 09:00"},
+		{"0 9 * * mon,FRI", "2024-03-05 00:00", "2024-03-08 09:00"},
+		{"0 0 * * 7", "2024-03-04 00:00", "2024-03-10 00:00"},
+		{"0 0 1 jan *", "2024-03-04 00:00", "2025-01-01 00:00"},
+		{"0 0 29 2 *", "2024-03-01 00:00", "2028-02-29 00:00"},
+		// 两个日期字段都受限时满足任意一个即可
+		{"0 12 1 * fri", "2024-03-02 00:00", "2024-03-08 12:00"},
+		{"0 12 1 * fri", "2024-03-29 13:00", "2024-04-01 12:00"},
+		{"0 12 */10 * *", "2024-03-02 00:00", "2024-03-11 12:00"},
+		{"@hourly", "2024-03-04 10:59", "2024-03-04 11:00"},
+		{"@daily", "2024-03-04 10:00", "2024-03-05 00:00"},
+		{"@weekly", "2024-03-04 10:00", "2024-03-10 00:00"},
+		{"@monthly", "2024-03-04 10:00", "2024-04-01 00:00"},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := c.Next(date(tt.from)); !got.Equal(date(tt.want)) {
+			t.Errorf("%q.Next(%s) = %s, want %s", tt.expr, tt.from, got.Format("2006-01-02 15:04"), tt.want)
+		}
+	}
+
+	never, _ := ParseCron("0 0 30 feb *")
+	if got := never.Next(date("2024-01-01 00:00")); !got.IsZero() {
+		t.Errorf("30 February matched %s", got)
+	}
+	if never.String() != "0 0 30 feb *" {
+		t.Errorf("String = %q", never.String())
+	}
+}
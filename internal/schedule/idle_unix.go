@@ -0,0 +1,25 @@
+//go:build !windows
+
+package schedule
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IdleTime returns how long ago the user last pressed a key or moved the
+// mouse, or 0 if it cannot be determined. X11 exposes this through the
+// screensaver extension, queried with xprintidle when it is installed.
+func IdleTime() time.Duration {
+	out, err := exec.Command("xprintidle").Output()
+	if err != nil {
+		return 0
+	}
+	ms, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
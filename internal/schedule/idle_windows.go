@@ -0,0 +1,32 @@
+package schedule
+
+import (
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32               = windows.NewLazySystemDLL("user32.dll")
+	kernel32             = windows.NewLazySystemDLL("kernel32.dll")
+	procGetLastInputInfo = user32.NewProc("GetLastInputInfo")
+	procGetTickCount     = kernel32.NewProc("GetTickCount")
+)
+
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+// IdleTime returns how long ago the user last pressed a key or moved the
+// mouse, or 0 if it cannot be determined
+func IdleTime() time.Duration {
+	info := lastInputInfo{cbSize: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	if ok, _, _ := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return 0
+	}
+	now, _, _ := procGetTickCount.Call()
+	// 两个值都是 32 位毫秒计数，无符号相减可正确处理 49 天回绕
+	return time.Duration(uint32(now)-info.dwTime) * time.Millisecond
+}
@@ -0,0 +1,235 @@
+// Package schedule launches shortcuts on cron schedules and when the app
+// starts. Time comes from an injectable Clock so the scheduler can be driven
+// step by step in tests.
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go-musetool/internal/model"
+)
+
+const (
+	// StateFileName is the file in the data directory holding the last run
+	// of every scheduled shortcut, so missed runs survive restarts
+	StateFileName = "schedule.json"
+
+	// DefaultIdleThreshold is how long without user input counts as idle
+	DefaultIdleThreshold = 10 * time.Minute
+
+	// missGrace is how late a run may start and still count as on time
+	// rather than as a missed run
+	missGrace = 2 * time.Minute
+)
+
+// StatePath returns the location of the state file inside dataDir
+func StatePath(dataDir string) string {
+	return filepath.Join(dataDir, StateFileName)
+}
+
+// Job is the schedule of one shortcut
+type Job struct {
+	ID      string // 快捷方式 ID
+	Name    string // 仅用于日志
+	Cron    *Cron  // nil 表示没有定时
+	AtStart bool
+	CatchUp bool
+}
+
+// Jobs collects the schedules attached to shortcuts. Shortcuts with an
+// invalid cron expression are skipped and reported in errs.
+func Jobs(config *model.Config) (jobs []Job, errs []error) {
+	for _, g := range config.Groups {
+		for _, s := range g.Shortcuts {
+			if s.Schedule == nil || s.ID == "" {
+				continue
+			}
+			job := Job{ID: s.ID, Name: s.Name, AtStart: s.Schedule.AtStart, CatchUp: s.Schedule.CatchUp}
+			if s.Schedule.Cron != "" {
+				c, err := ParseCron(s.Schedule.Cron)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", s.Name, err))
+					continue
+				}
+				job.Cron = c
+			}
+			if job.Cron != nil || job.AtStart {
+				jobs = append(jobs, job)
+			}
+		}
+	}
+	return jobs, errs
+}
+
+// Options configure a Scheduler. The zero value uses the real clock, never
+// considers the machine idle and keeps no state on disk.
+type Options struct {
+	Clock         Clock
+	Idle          func() time.Duration // 距离上次用户输入的时间
+	IdleThreshold time.Duration        // 0 表示 DefaultIdleThreshold
+	StatePath     string               // 为空时不保存状态
+}
+
+// Scheduler runs jobs when they are due
+type Scheduler struct {
+	launch func(Job)
+	opts   Options
+
+	mu      sync.Mutex
+	jobs    []Job
+	lastRun map[string]time.Time // 按快捷方式 ID
+}
+
+// New creates a scheduler that calls launch for every due job. The saved
+// state is loaded from opts.StatePath; a missing or unreadable file starts
+// with an empty state.
+func New(launch func(Job), opts Options) *Scheduler {
+	if opts.Clock == nil {
+		opts.Clock = RealClock{}
+	}
+	if opts.IdleThreshold <= 0 {
+		opts.IdleThreshold = DefaultIdleThreshold
+	}
+	s := &Scheduler{launch: launch, opts: opts, lastRun: map[string]time.Time{}}
+	if opts.StatePath != "" {
+		if data, err := os.ReadFile(opts.StatePath); err == nil {
+			json.Unmarshal(data, &s.lastRun)
+		}
+	}
+	return s
+}
+
+// SetJobs replaces the scheduled jobs. New cron jobs start counting from now,
+// so adding a schedule never triggers a catch-up run.
+func (s *Scheduler) SetJobs(jobs []Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.opts.Clock.Now()
+	keep := map[string]time.Time{}
+	for _, j := range jobs {
+		if j.Cron == nil {
+			continue
+		}
+		if last, ok := s.lastRun[j.ID]; ok {
+			keep[j.ID] = last
+		} else {
+			keep[j.ID] = now
+		}
+	}
+	s.jobs = append([]Job{}, jobs...)
+	changed := len(keep) != len(s.lastRun)
+	for id, t := range keep {
+		if !s.lastRun[id].Equal(t) {
+			changed = true
+		}
+	}
+	s.lastRun = keep
+	if changed {
+		return s.saveLocked()
+	}
+	return nil
+}
+
+// RunAtStart launches every job with an "at app start" trigger
+func (s *Scheduler) RunAtStart() {
+	s.mu.Lock()
+	var due []Job
+	for _, j := range s.jobs {
+		if j.AtStart {
+			due = append(due, j)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, j := range due {
+		s.launch(j)
+	}
+}
+
+// Tick launches the cron jobs that are due at now and returns them. While
+// the machine is idle nothing runs; due jobs wait until the user is back.
+// A run missed by more than a short grace period (the machine was off,
+// asleep or idle) runs once if the job allows catch-up and is skipped
+// otherwise; several missed runs are coalesced into one.
+func (s *Scheduler) Tick(now time.Time) []Job {
+	if s.opts.Idle != nil && s.opts.Idle() >= s.opts.IdleThreshold {
+		return nil
+	}
+
+	s.mu.Lock()
+	var due []Job
+	changed := false
+	for _, j := range s.jobs {
+		if j.Cron == nil {
+			continue
+		}
+		next := j.Cron.Next(s.lastRun[j.ID])
+		if next.IsZero() || next.After(now) {
+			continue
+		}
+		s.lastRun[j.ID] = now
+		changed = true
+		if now.Sub(next) > missGrace && !j.CatchUp {
+			continue
+		}
+		due = append(due, j)
+	}
+	if changed {
+		// 保存失败只影响重启后的补运行，不影响本次启动
+		s.saveLocked()
+	}
+	s.mu.Unlock()
+
+	for _, j := range due {
+		s.launch(j)
+	}
+	return due
+}
+
+// NextRun returns when the job with the given ID runs next, or the zero
+// time if it has no cron schedule
+func (s *Scheduler) NextRun(id string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if j.ID == id && j.Cron != nil {
+			return j.Cron.Next(s.lastRun[id])
+		}
+	}
+	return time.Time{}
+}
+
+// Run launches the at-start jobs, then checks the cron jobs at the start of
+// every minute until ctx is cancelled. The first check happens immediately
+// so runs missed while the app was closed are caught up.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.RunAtStart()
+	for {
+		now := s.opts.Clock.Now()
+		s.Tick(now)
+		wait := now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.opts.Clock.After(wait):
+		}
+	}
+}
+
+func (s *Scheduler) saveLocked() error {
+	if s.opts.StatePath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.lastRun, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.opts.StatePath, data, 0600)
+}
@@ -0,0 +1,212 @@
+package schedule
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go-musetool/internal/model"
+)
+
+// launches records the jobs a scheduler launched
+type launches struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (l *launches) launch(j Job) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ids = append(l.ids, j.ID)
+}
+
+func (l *launches) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ids := l.ids
+	l.ids = nil
+	return ids
+}
+
+func cronJob(id, expr string, catchUp bool) Job {
+	c, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return Job{ID: id, Name: id, Cron: c, CatchUp: catchUp}
+}
+
+func TestJobs(t *testing.T) {
+	config := &model.Config{Groups: []model.Group{{Shortcuts: []model.Shortcut{
+		{ID: "a", Name: "A", Schedule: &model.Schedule{Cron: "0 9 * * *"}},
+		{ID: "b", Name: "B", Schedule: &model.Schedule{AtStart: true}},
+		{ID: "c", Name: "C", Schedule: &model.Schedule{Cron: "bogus"}},
+		{ID: "d", Name: "D", Schedule: &model.Schedule{}},
+		{ID: "", Name: "no id", Schedule: &model.Schedule{AtStart: true}},
+		{ID: "e", Name: "E"},
+	}}}}
+	jobs, errs := Jobs(config)
+	var ids []string
+	for _, j := range jobs {
+		ids = append(ids, j.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"a", "b"}) {
+		t.Errorf("jobs = %q, want [a b]", ids)
+	}
+	if len(errs) != 1 {
+		t.Errorf("errs = %v, want the invalid cron of C", errs)
+	}
+}
+
+func TestTickFiresWhenDue(t *testing.T) {
+	clock := NewFakeClock(date("2024-03-04 08:58"))
+	var l launches
+	s := New(l.launch, Options{Clock: clock})
+	s.SetJobs([]Job{cronJob("daily", "0 9 * * *", false), cronJob("quarter", "*/15 * * * *", false)})
+
+	if got := s.NextRun("daily"); !got.Equal(date("2024-03-04 09:00")) {
+		t.Errorf("NextRun = %s", got)
+	}
+	if due := s.Tick(date("2024-03-04 08:59")); len(due) != 0 {
+		t.Errorf("ran %v before it was due", due)
+	}
+	s.Tick(date("2024-03-04 09:00"))
+	if got := l.take(); !reflect.DeepEqual(got, []string{"daily", "quarter"}) {
+		t.Errorf("at 09:00 ran %q", got)
+	}
+	// 同一分钟内不会重复运行
+	s.Tick(date("2024-03-04 09:00"))
+	if got := l.take(); len(got) != 0 {
+		t.Errorf("ran again within the minute: %q", got)
+	}
+	s.Tick(date("2024-03-04 09:15"))
+	if got := l.take(); !reflect.DeepEqual(got, []string{"quarter"}) {
+		t.Errorf("at 09:15 ran %q", got)
+	}
+}
+
+func TestTickMissedRuns(t *testing.T) {
+	clock := NewFakeClock(date("2024-03-04 08:00"))
+	var l launches
+	s := New(l.launch, Options{Clock: clock})
+	s.SetJobs([]Job{cronJob("skip", "0 9 * * *", false), cronJob("catchup", "0 9 * * *", true)})
+
+	// 机器休眠了三天：只补运行一次，且只有允许补运行的任务
+	s.Tick(date("2024-03-07 12:00"))
+	if got := l.take(); !reflect.DeepEqual(got, []string{"catchup"}) {
+		t.Errorf("after missed runs ran %q, want [catchup]", got)
+	}
+	s.Tick(date("2024-03-07 12:01"))
+	if got := l.take(); len(got) != 0 {
+		t.Errorf("missed runs were not coalesced: %q", got)
+	}
+
+	// 在宽限期内晚到的运行仍算按时
+	s.Tick(date("2024-03-08 09:01"))
+	if got := l.take(); !reflect.DeepEqual(got, []string{"skip", "catchup"}) {
+		t.Errorf("slightly late run ran %q", got)
+	}
+}
+
+func TestTickWaitsWhileIdle(t *testing.T) {
+	idle := time.Hour
+	var l launches
+	s := New(l.launch, Options{
+		Clock: NewFakeClock(date("2024-03-04 08:00")),
+		Idle:  func() time.Duration { return idle },
+	})
+	s.SetJobs([]Job{cronJob("daily", "0 9 * * *", false)})
+
+	if due := s.Tick(date("2024-03-04 09:00")); len(due) != 0 {
+		t.Errorf("ran %v while idle", due)
+	}
+	idle = time.Second
+	if due := s.Tick(date("2024-03-04 09:01")); len(due) != 1 {
+		t.Errorf("did not run once the user was back: %v", due)
+	}
+}
+
+func TestStateSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFileName)
+	jobs := []Job{cronJob("catchup", "0 9 * * *", true)}
+
+	var l launches
+	s := New(l.launch, Options{Clock: NewFakeClock(date("2024-03-04 08:00")), StatePath: path})
+	s.SetJobs(jobs)
+
+	// 重启后仍从上次运行的时间计算，错过的运行会被补上
+	restarted := New(l.launch, Options{Clock: NewFakeClock(date("2024-03-05 12:00")), StatePath: path})
+	restarted.SetJobs(jobs)
+	restarted.Tick(date("2024-03-05 12:00"))
+	if got := l.take(); !reflect.DeepEqual(got, []string{"catchup"}) {
+		t.Errorf("after restart ran %q", got)
+	}
+
+	// 新加的定时从现在开始计算，不会补运行
+	fresh := New(l.launch, Options{Clock: NewFakeClock(date("2024-03-05 12:00")), StatePath: path})
+	fresh.SetJobs(append(jobs, cronJob("new", "0 9 * * *", true)))
+	fresh.Tick(date("2024-03-05 12:00"))
+	if got := l.take(); len(got) != 0 {
+		t.Errorf("new schedule caught up: %q", got)
+	}
+}
+
+// waitForWaiter blocks until the scheduler is waiting on the fake clock
+func waitForWaiter(t *testing.T, c *FakeClock) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		n := len(c.waiters)
+		c.mu.Unlock()
+		if n > 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("scheduler is not waiting on the clock")
+}
+
+func TestRunWithFakeClock(t *testing.T) {
+	clock := NewFakeClock(date("2024-03-04 08:58").Add(30 * time.Second))
+	var l launches
+	s := New(l.launch, Options{Clock: clock})
+	s.SetJobs([]Job{
+		{ID: "start", AtStart: true},
+		cronJob("nine", "0 9 * * *", false),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	waitForWaiter(t, clock)
+	if got := l.take(); !reflect.DeepEqual(got, []string{"start"}) {
+		t.Errorf("at start ran %q", got)
+	}
+
+	// 08:59:00 和 09:00:00 各检查一次
+	clock.Advance(30 * time.Second)
+	waitForWaiter(t, clock)
+	if got := l.take(); len(got) != 0 {
+		t.Errorf("at 08:59 ran %q", got)
+	}
+	clock.Advance(time.Minute)
+	waitForWaiter(t, clock)
+	if got := l.take(); !reflect.DeepEqual(got, []string{"nine"}) {
+		t.Errorf("at 09:00 ran %q", got)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
@@ -94,6 +94,7 @@ func ImportConfigWithIcons(zipPath, appDataDir string) (*model.Config, error) {
 			}
 		}
 	}
+	EnsureShortcutIDs(config)
 
 	return config, nil
 }
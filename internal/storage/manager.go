@@ -1,13 +1,25 @@
 package storage
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"os"
+	"strings"
 
 	"go-musetool/internal/model"
 )
 
+var (
+	ErrGroupNotFound    = errors.New("group not found")
+	ErrShortcutNotFound = errors.New("shortcut not found")
+	ErrGroupExists      = errors.New("group already exists")
+	ErrShortcutExists   = errors.New("shortcut already exists")
+	ErrGroupNameEmpty   = errors.New("group name is empty")
+	ErrLastGroup        = errors.New("cannot delete the last group")
+)
+
 func LoadConfig(path string) (*model.Config, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -41,13 +53,16 @@ func SaveConfig(path string, config *model.Config) error {
 }
 
 func AddShortcut(config *model.Config, groupName string, shortcut model.Shortcut) error {
+	if shortcut.ID == "" {
+		shortcut.ID = NewShortcutID()
+	}
 	for i, group := range config.Groups {
 		if group.Name == groupName {
 			config.Groups[i].Shortcuts = append(config.Groups[i].Shortcuts, shortcut)
 			return nil
 		}
 	}
-	return errors.New("group not found")
+	return ErrGroupNotFound
 }
 
 func UpdateShortcut(config *model.Config, groupName string, oldName string, newShortcut model.Shortcut) error {
@@ -55,14 +70,19 @@ func UpdateShortcut(config *model.Config, groupName string, oldName string, newS
 		if group.Name == groupName {
 			for j, s := range group.Shortcuts {
 				if s.Name == oldName {
+					// 编辑后 ID 不变，启动历史才能延续
+					if newShortcut.ID == "" {
+						newShortcut.ID = s.ID
+					}
 					config.Groups[i].Shortcuts[j] = newShortcut
+					renameWorkspaceEntry(&config.Groups[i], oldName, newShortcut.Name)
 					return nil
 				}
 			}
-			return errors.New("shortcut not found")
+			return ErrShortcutNotFound
 		}
 	}
-	return errors.New("group not found")
+	return ErrGroupNotFound
 }
 
 func RemoveShortcut(config *model.Config, groupName string, shortcutName string) error {
@@ -71,11 +91,228 @@ func RemoveShortcut(config *model.Config, groupName string, shortcutName string)
 			for j, s := range group.Shortcuts {
 				if s.Name == shortcutName {
 					config.Groups[i].Shortcuts = append(config.Groups[i].Shortcuts[:j], config.Groups[i].Shortcuts[j+1:]...)
+					renameWorkspaceEntry(&config.Groups[i], shortcutName, "")
 					return nil
 				}
 			}
-			return errors.New("shortcut not found")
+			return ErrShortcutNotFound
+		}
+	}
+	return ErrGroupNotFound
+}
+
+// MoveShortcut moves a shortcut from one group to the end of another group.
+// The target group must not already have a shortcut with the same name.
+func MoveShortcut(config *model.Config, fromGroup, toGroup, shortcutName string) error {
+	if fromGroup == toGroup {
+		return nil
+	}
+	to := FindGroup(config, toGroup)
+	from := FindGroup(config, fromGroup)
+	if from == nil || to == nil {
+		return ErrGroupNotFound
+	}
+	for _, s := range to.Shortcuts {
+		if s.Name == shortcutName {
+			return ErrShortcutExists
+		}
+	}
+
+	for j, s := range from.Shortcuts {
+		if s.Name == shortcutName {
+			from.Shortcuts = append(from.Shortcuts[:j], from.Shortcuts[j+1:]...)
+			to.Shortcuts = append(to.Shortcuts, s)
+			renameWorkspaceEntry(from, shortcutName, "")
+			return nil
+		}
+	}
+	return ErrShortcutNotFound
+}
+
+// renameWorkspaceEntry keeps the group's "Launch all" list in sync when a
+// shortcut is renamed; an empty newName removes the entry.
+func renameWorkspaceEntry(group *model.Group, oldName, newName string) {
+	if group.Workspace == nil {
+		return
+	}
+	names := group.Workspace.Shortcuts[:0]
+	for _, name := range group.Workspace.Shortcuts {
+		if name == oldName {
+			if newName == "" {
+				continue
+			}
+			name = newName
+		}
+		names = append(names, name)
+	}
+	group.Workspace.Shortcuts = names
+}
+
+// NewShortcutID returns a random identifier for a new shortcut
+func NewShortcutID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err) // crypto/rand 不会失败
+	}
+	return hex.EncodeToString(buf)
+}
+
+// EnsureShortcutIDs assigns an ID to every shortcut that lacks one (configs
+// written before IDs existed) and reports whether anything changed.
+func EnsureShortcutIDs(config *model.Config) bool {
+	changed := false
+	for i := range config.Groups {
+		for j := range config.Groups[i].Shortcuts {
+			if config.Groups[i].Shortcuts[j].ID == "" {
+				config.Groups[i].Shortcuts[j].ID = NewShortcutID()
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// FindShortcutByID returns the group and shortcut with the given ID.
+func FindShortcutByID(config *model.Config, id string) (string, model.Shortcut, bool) {
+	for _, group := range config.Groups {
+		for _, s := range group.Shortcuts {
+			if s.ID == id {
+				return group.Name, s, true
+			}
+		}
+	}
+	return "", model.Shortcut{}, false
+}
+
+// FindGroup returns a pointer to the named group, or nil if it does not exist.
+func FindGroup(config *model.Config, groupName string) *model.Group {
+	for i := range config.Groups {
+		if config.Groups[i].Name == groupName {
+			return &config.Groups[i]
+		}
+	}
+	return nil
+}
+
+// FindShortcut returns the named shortcut inside the named group.
+func FindShortcut(config *model.Config, groupName, shortcutName string) (model.Shortcut, error) {
+	group := FindGroup(config, groupName)
+	if group == nil {
+		return model.Shortcut{}, ErrGroupNotFound
+	}
+	for _, s := range group.Shortcuts {
+		if s.Name == shortcutName {
+			return s, nil
+		}
+	}
+	return model.Shortcut{}, ErrShortcutNotFound
+}
+
+// LookupShortcut resolves a "Group/Name" reference. Group names may contain
+// slashes themselves, so every group whose name is a prefix of ref is tried.
+func LookupShortcut(config *model.Config, ref string) (string, model.Shortcut, error) {
+	groupFound := false
+	for _, group := range config.Groups {
+		prefix := group.Name + "/"
+		if !strings.HasPrefix(ref, prefix) {
+			continue
+		}
+		groupFound = true
+		name := strings.TrimPrefix(ref, prefix)
+		for _, s := range group.Shortcuts {
+			if s.Name == name {
+				return group.Name, s, nil
+			}
+		}
+	}
+	if groupFound {
+		return "", model.Shortcut{}, ErrShortcutNotFound
+	}
+	return "", model.Shortcut{}, ErrGroupNotFound
+}
+
+// AddGroup appends a new empty group.
+func AddGroup(config *model.Config, groupName string) error {
+	if groupName == "" {
+		return ErrGroupNameEmpty
+	}
+	if FindGroup(config, groupName) != nil {
+		return ErrGroupExists
+	}
+	config.Groups = append(config.Groups, model.Group{Name: groupName, Shortcuts: []model.Shortcut{}})
+	return nil
+}
+
+// RenameGroup renames an existing group.
+func RenameGroup(config *model.Config, oldName, newName string) error {
+	if newName == "" {
+		return ErrGroupNameEmpty
+	}
+	group := FindGroup(config, oldName)
+	if group == nil {
+		return ErrGroupNotFound
+	}
+	if newName != oldName && FindGroup(config, newName) != nil {
+		return ErrGroupExists
+	}
+	group.Name = newName
+	return nil
+}
+
+// DeleteGroup removes a group and all of its shortcuts. The last remaining
+// group cannot be deleted.
+func DeleteGroup(config *model.Config, groupName string) error {
+	for i, group := range config.Groups {
+		if group.Name == groupName {
+			if len(config.Groups) <= 1 {
+				return ErrLastGroup
+			}
+			config.Groups = append(config.Groups[:i], config.Groups[i+1:]...)
+			return nil
+		}
+	}
+	return ErrGroupNotFound
+}
+
+// SearchResult is a shortcut matched by SearchShortcuts together with its group
+type SearchResult struct {
+	Group    string         `json:"group"`
+	Shortcut model.Shortcut `json:"shortcut"`
+}
+
+// SearchShortcuts returns the shortcuts whose name, path, command, snippet
+// text or group name contains query, ignoring case, in group order. An empty
+// query matches everything.
+func SearchShortcuts(config *model.Config, query string) []SearchResult {
+	query = strings.ToLower(strings.TrimSpace(query))
+	results := []SearchResult{}
+	for _, group := range config.Groups {
+		groupMatch := strings.Contains(strings.ToLower(group.Name), query)
+		for _, s := range group.Shortcuts {
+			if groupMatch ||
+				strings.Contains(strings.ToLower(s.Name), query) ||
+				strings.Contains(strings.ToLower(s.Path), query) ||
+				strings.Contains(strings.ToLower(s.Command), query) ||
+				strings.Contains(strings.ToLower(s.Text), query) {
+				results = append(results, SearchResult{Group: group.Name, Shortcut: s})
+			}
+		}
+	}
+	return results
+}
+
+// FindKeyword returns the search shortcut whose keyword is keyword,
+// ignoring case
+func FindKeyword(config *model.Config, keyword string) (string, model.Shortcut, bool) {
+	if keyword == "" {
+		return "", model.Shortcut{}, false
+	}
+	for _, group := range config.Groups {
+		for _, s := range group.Shortcuts {
+			if s.Kind == model.KindSearch && strings.EqualFold(s.Keyword, keyword) {
+				return group.Name, s, true
+			}
 		}
 	}
-	return errors.New("group not found")
+	return "", model.Shortcut{}, false
 }
@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-musetool/internal/model"
+)
+
+// testConfig has a group whose name contains "/" and a "Launch all" list
+func testConfig() *model.Config {
+	return &model.Config{Groups: []model.Group{
+		{Name: "Dev", Shortcuts: []model.Shortcut{
+			{ID: "1", Name: "Terminal", Path: "/usr/bin/xterm"},
+			{ID: "2", Name: "Editor", Path: "/usr/bin/code"},
+			{ID: "3", Name: "Docs", Path: "https://go.dev/doc"},
+		}, Workspace: &model.Workspace{Shortcuts: []string{"Editor", "Terminal"}}},
+		{Name: "Dev/Tools", Shortcuts: []model.Shortcut{
+			{ID: "4", Name: "Profiler", Path: "/usr/bin/pprof"},
+		}},
+		{Name: "Web", Shortcuts: []model.Shortcut{
+			{ID: "5", Name: "Docs", Path: "https://pkg.go.dev"},
+		}},
+	}}
+}
+
+func names(shortcuts []model.Shortcut) []string {
+	var out []string
+	for _, s := range shortcuts {
+		out = append(out, s.Name)
+	}
+	return out
+}
+
+func TestLookupShortcut(t *testing.T) {
+	tests := []struct {
+		ref       string
+		wantGroup string
+		wantID    string
+		wantErr   error
+	}{
+		{"Dev/Terminal", "Dev", "1", nil},
+		{"Dev/Tools/Profiler", "Dev/Tools", "4", nil},
+		{"Web/Docs", "Web", "5", nil},
+		{"Dev/Nope", "", "", ErrShortcutNotFound},
+		{"Dev/Tools/Nope", "", "", ErrShortcutNotFound},
+		{"Ops/Terminal", "", "", ErrGroupNotFound},
+		{"Terminal", "", "", ErrGroupNotFound},
+		{"dev/Terminal", "", "", ErrGroupNotFound}, // 分组名区分大小写
+	}
+	config := testConfig()
+	for _, tt := range tests {
+		group, s, err := LookupShortcut(config, tt.ref)
+		if !errors.Is(err, tt.wantErr) || group != tt.wantGroup || s.ID != tt.wantID {
+			t.Errorf("LookupShortcut(%q) = %q, %q, %v, want %q, %q, %v", tt.ref, group, s.ID, err, tt.wantGroup, tt.wantID, tt.wantErr)
+		}
+	}
+}
+
+func TestMoveShortcut(t *testing.T) {
+	tests := []struct {
+		name, from, to, shortcut string
+		wantErr                  error
+		wantFrom, wantTo         []string
+		wantWorkspace            []string // Dev 的 "全部启动" 列表
+	}{
+		{"move", "Dev", "Dev/Tools", "Terminal", nil,
+			[]string{"Editor", "Docs"}, []string{"Profiler", "Terminal"}, []string{"Editor"}},
+		{"not in the workspace", "Dev", "Dev/Tools", "Docs", nil,
+			[]string{"Terminal", "Editor"}, []string{"Profiler", "Docs"}, []string{"Editor", "Terminal"}},
+		{"into a workspace group", "Dev/Tools", "Dev", "Profiler", nil,
+			nil, []string{"Terminal", "Editor", "Docs", "Profiler"}, []string{"Editor", "Terminal"}},
+		{"same group", "Dev", "Dev", "Terminal", nil,
+			[]string{"Terminal", "Editor", "Docs"}, []string{"Terminal", "Editor", "Docs"}, []string{"Editor", "Terminal"}},
+		{"duplicate name", "Dev", "Web", "Docs", ErrShortcutExists,
+			[]string{"Terminal", "Editor", "Docs"}, []string{"Docs"}, []string{"Editor", "Terminal"}},
+		{"missing shortcut", "Dev", "Web", "Nope", ErrShortcutNotFound,
+			[]string{"Terminal", "Editor", "Docs"}, []string{"Docs"}, []string{"Editor", "Terminal"}},
+		{"missing source group", "Ops", "Web", "Docs", ErrGroupNotFound,
+			nil, []string{"Docs"}, []string{"Editor", "Terminal"}},
+		{"missing target group", "Dev", "Ops", "Terminal", ErrGroupNotFound,
+			[]string{"Terminal", "Editor", "Docs"}, nil, []string{"Editor", "Terminal"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			err := MoveShortcut(config, tt.from, tt.to, tt.shortcut)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MoveShortcut = %v, want %v", err, tt.wantErr)
+			}
+			if g := FindGroup(config, tt.from); g != nil && !reflect.DeepEqual(names(g.Shortcuts), tt.wantFrom) {
+				t.Errorf("%s = %q, want %q", tt.from, names(g.Shortcuts), tt.wantFrom)
+			}
+			if g := FindGroup(config, tt.to); g != nil && !reflect.DeepEqual(names(g.Shortcuts), tt.wantTo) {
+				t.Errorf("%s = %q, want %q", tt.to, names(g.Shortcuts), tt.wantTo)
+			}
+			if got := FindGroup(config, "Dev").Workspace.Shortcuts; !reflect.DeepEqual(got, tt.wantWorkspace) {
+				t.Errorf("Dev workspace = %q, want %q", got, tt.wantWorkspace)
+			}
+		})
+	}
+}
+
+func TestMoveShortcutKeepsID(t *testing.T) {
+	config := testConfig()
+	if err := MoveShortcut(config, "Dev", "Web", "Terminal"); err != nil {
+		t.Fatal(err)
+	}
+	group, s, ok := FindShortcutByID(config, "1")
+	if !ok || group != "Web" || s.Name != "Terminal" {
+		t.Errorf("FindShortcutByID(1) = %q, %+v, %v after the move", group, s, ok)
+	}
+}
+
+func TestRenameWorkspaceEntry(t *testing.T) {
+	tests := []struct {
+		name, oldName, newName string
+		want                   []string
+	}{
+		{"rename", "Terminal", "Shell", []string{"Editor", "Shell"}},
+		{"remove", "Editor", "", []string{"Terminal"}},
+		{"not listed", "Docs", "Manual", []string{"Editor", "Terminal"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := &testConfig().Groups[0]
+			renameWorkspaceEntry(group, tt.oldName, tt.newName)
+			if !reflect.DeepEqual(group.Workspace.Shortcuts, tt.want) {
+				t.Errorf("workspace = %q, want %q", group.Workspace.Shortcuts, tt.want)
+			}
+		})
+	}
+
+	// 没有 "全部启动" 设置的分组不受影响
+	group := &model.Group{Name: "Web"}
+	renameWorkspaceEntry(group, "Docs", "")
+	if group.Workspace != nil {
+		t.Error("renameWorkspaceEntry created a workspace")
+	}
+}
+
+func TestUpdateAndRemoveShortcutSyncWorkspace(t *testing.T) {
+	config := testConfig()
+	if err := UpdateShortcut(config, "Dev", "Terminal", model.Shortcut{Name: "Shell", Path: "/bin/sh"}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := FindShortcut(config, "Dev", "Shell")
+	if err != nil || s.ID != "1" {
+		t.Errorf("FindShortcut(Dev, Shell) = %+v, %v, want the old ID kept", s, err)
+	}
+	if err := RemoveShortcut(config, "Dev", "Editor"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := config.Groups[0].Workspace.Shortcuts, []string{"Shell"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("workspace = %q, want %q", got, want)
+	}
+
+	if err := UpdateShortcut(config, "Dev", "Nope", model.Shortcut{Name: "X"}); !errors.Is(err, ErrShortcutNotFound) {
+		t.Errorf("UpdateShortcut of a missing shortcut = %v", err)
+	}
+	if err := RemoveShortcut(config, "Ops", "Shell"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("RemoveShortcut in a missing group = %v", err)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	config := testConfig()
+	if err := AddGroup(config, ""); !errors.Is(err, ErrGroupNameEmpty) {
+		t.Errorf("AddGroup(\"\") = %v", err)
+	}
+	if err := AddGroup(config, "Web"); !errors.Is(err, ErrGroupExists) {
+		t.Errorf("AddGroup(Web) = %v", err)
+	}
+	if err := RenameGroup(config, "Web", "Dev"); !errors.Is(err, ErrGroupExists) {
+		t.Errorf("RenameGroup(Web, Dev) = %v", err)
+	}
+	if err := RenameGroup(config, "Ops", "Ops2"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("RenameGroup(Ops) = %v", err)
+	}
+	if err := RenameGroup(config, "Web", "Links"); err != nil || FindGroup(config, "Links") == nil {
+		t.Errorf("RenameGroup(Web, Links) = %v", err)
+	}
+
+	single := &model.Config{Groups: []model.Group{{Name: "Only"}}}
+	if err := DeleteGroup(single, "Only"); !errors.Is(err, ErrLastGroup) {
+		t.Errorf("DeleteGroup of the last group = %v", err)
+	}
+	if err := DeleteGroup(config, "Dev/Tools"); err != nil || FindGroup(config, "Dev/Tools") != nil {
+		t.Errorf("DeleteGroup(Dev/Tools) = %v", err)
+	}
+}
+
+func TestSaveLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	config, err := LoadConfig(path)
+	if err != nil || config == nil || len(config.Groups) != 0 {
+		t.Fatalf("LoadConfig of a missing file = %+v, %v, want an empty config", config, err)
+	}
+	want := testConfig()
+	if err := SaveConfig(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestSearchShortcuts(t *testing.T) {
+	config := testConfig()
+	web := FindGroup(config, "Web")
+	web.Shortcuts = append(web.Shortcuts,
+		model.Shortcut{ID: "6", Name: "Build", Kind: model.KindCommand, Command: "go build ./..."},
+		model.Shortcut{ID: "7", Name: "Greeting", Kind: model.KindSnippet, Text: "Hello World"},
+	)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Dev/Terminal", "Dev/Editor", "Dev/Docs", "Dev/Tools/Profiler", "Web/Docs", "Web/Build", "Web/Greeting"}},
+		{"TERM", []string{"Dev/Terminal"}},         // 名称，忽略大小写
+		{"  editor ", []string{"Dev/Editor"}},      // 去掉首尾空白
+		{"docs", []string{"Dev/Docs", "Web/Docs"}}, // 同名快捷方式按分组顺序
+		{"PKG.GO", []string{"Web/Docs"}},           // 路径
+		{"Go Build", []string{"Web/Build"}},        // 命令
+		{"world", []string{"Web/Greeting"}},        // 片段文本
+		{"tools", []string{"Dev/Tools/Profiler"}},  // 分组名
+		{"wEb", []string{"Web/Docs", "Web/Build", "Web/Greeting"}},
+		{"nope", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, r := range SearchShortcuts(config, tt.query) {
+			got = append(got, r.Group+"/"+r.Shortcut.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SearchShortcuts(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
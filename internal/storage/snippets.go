@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"go-musetool/internal/model"
+)
+
+// snippetBundleVersion is written to exported bundles so the format can
+// change later without misreading old files
+const snippetBundleVersion = 1
+
+var ErrNotSnippetBundle = errors.New("not a snippet bundle")
+
+// SnippetBundle is the file format used to share text snippets
+type SnippetBundle struct {
+	Version  int       `json:"version"`
+	Snippets []Snippet `json:"snippets"`
+}
+
+// Snippet is one entry of a bundle
+type Snippet struct {
+	Name      string `json:"name"`
+	Text      string `json:"text"`
+	AutoPaste bool   `json:"autoPaste,omitempty"`
+}
+
+// ExportSnippets writes the snippet shortcuts among shortcuts to a bundle
+// file and returns how many were exported
+func ExportSnippets(path string, shortcuts []model.Shortcut) (int, error) {
+	bundle := SnippetBundle{Version: snippetBundleVersion, Snippets: []Snippet{}}
+	for _, s := range shortcuts {
+		if s.Kind == model.KindSnippet {
+			bundle.Snippets = append(bundle.Snippets, Snippet{Name: s.Name, Text: s.Text, AutoPaste: s.AutoPaste})
+		}
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return 0, err
+	}
+	return len(bundle.Snippets), nil
+}
+
+// ImportSnippets adds the snippets of a bundle file to a group and returns
+// how many were added. Snippets whose name is taken get a numbered name.
+func ImportSnippets(config *model.Config, groupName, path string) (int, error) {
+	group := FindGroup(config, groupName)
+	if group == nil {
+		return 0, ErrGroupNotFound
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var bundle SnippetBundle
+	if err := json.Unmarshal(data, &bundle); err != nil || bundle.Version == 0 {
+		return 0, ErrNotSnippetBundle
+	}
+	if bundle.Version > snippetBundleVersion {
+		return 0, fmt.Errorf("%w: version %d is newer than supported", ErrNotSnippetBundle, bundle.Version)
+	}
+
+	added := 0
+	for _, sn := range bundle.Snippets {
+		if sn.Name == "" || sn.Text == "" {
+			continue
+		}
+		name := sn.Name
+		for n := 2; ; n++ {
+			if _, err := FindShortcut(config, groupName, name); err != nil {
+				break
+			}
+			name = fmt.Sprintf("%s (%d)", sn.Name, n)
+		}
+		shortcut := model.Shortcut{Name: name, Kind: model.KindSnippet, Text: sn.Text, AutoPaste: sn.AutoPaste}
+		if err := AddShortcut(config, groupName, shortcut); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"go-musetool/internal/api"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+)
+
+// apiBackend 实现 api.Backend，所有调用都在 UI 线程上执行，
+// 与界面操作共用同一套 storage 与启动逻辑
+type apiBackend struct {
+	l *LauncherApp
+}
+
+// applyAPISettings 根据配置启动或停止本地自动化 API
+func (l *LauncherApp) applyAPISettings() {
+	if l.apiServer != nil {
+		l.apiServer.Close()
+		l.apiServer = nil
+	}
+	if !l.Config.APIEnabled {
+		return
+	}
+
+	token, err := api.LoadOrCreateToken(filepath.Dir(l.ConfigPath))
+	if err != nil {
+		logger.Error("Failed to load API token: %v", err)
+		return
+	}
+
+	server := api.NewServer(&apiBackend{l: l}, token)
+	if err := server.Start(l.Config.APIPort); err != nil {
+		logger.Error("Failed to start API server: %v", err)
+		return
+	}
+	l.apiServer = server
+	logger.Info("Local automation API enabled")
+}
+
+// mutate 在 UI 线程执行 fn，成功后保存配置并刷新界面
+func (b *apiBackend) mutate(fn func() error) error {
+	var err error
+	fyne.DoAndWait(func() {
+		if err = fn(); err != nil {
+			return
+		}
+		if err = storage.SaveConfig(b.l.ConfigPath, b.l.Config); err != nil {
+			err = fmt.Errorf("failed to save config: %w", err)
+			return
+		}
+		b.l.setupUI()
+	})
+	return err
+}
+
+func (b *apiBackend) Groups() []model.Group {
+	var groups []model.Group
+	fyne.DoAndWait(func() {
+		groups = make([]model.Group, len(b.l.Config.Groups))
+		for i, g := range b.l.Config.Groups {
+			// 复制整个结构体，再深拷贝切片和指针字段，新增字段不会被漏掉
+			groups[i] = g
+			groups[i].Shortcuts = make([]model.Shortcut, len(g.Shortcuts))
+			for j, s := range g.Shortcuts {
+				s.Steps = append([]model.MacroStep(nil), s.Steps...)
+				if s.Schedule != nil {
+					schedule := *s.Schedule
+					s.Schedule = &schedule
+				}
+				groups[i].Shortcuts[j] = s
+			}
+			if g.Workspace != nil {
+				ws := *g.Workspace
+				ws.Shortcuts = append([]string{}, ws.Shortcuts...)
+				groups[i].Workspace = &ws
+			}
+		}
+	})
+	return groups
+}
+
+func (b *apiBackend) LaunchShortcut(group, name string) error {
+	var err error
+	fyne.DoAndWait(func() {
+		var shortcut model.Shortcut
+		if shortcut, err = storage.FindShortcut(b.l.Config, group, name); err != nil {
+			err = fmt.Errorf("%w: %s/%s", err, group, name)
+			return
+		}
+		// 片段和宏的处理程序需要在 UI 线程执行
+		err = b.l.launchShortcut(shortcut)
+	})
+	return err
+}
+
+func (b *apiBackend) AddGroup(name string) error {
+	return b.mutate(func() error {
+		return storage.AddGroup(b.l.Config, name)
+	})
+}
+
+func (b *apiBackend) RenameGroup(oldName, newName string) error {
+	return b.mutate(func() error {
+		if err := storage.RenameGroup(b.l.Config, oldName, newName); err != nil {
+			return err
+		}
+		if b.l.CurrentGroup == oldName {
+			b.l.CurrentGroup = newName
+		}
+		return nil
+	})
+}
+
+func (b *apiBackend) DeleteGroup(name string) error {
+	return b.mutate(func() error {
+		return storage.DeleteGroup(b.l.Config, name)
+	})
+}
+
+func (b *apiBackend) AddShortcut(group string, shortcut model.Shortcut) error {
+	return b.mutate(func() error {
+		if _, err := storage.FindShortcut(b.l.Config, group, shortcut.Name); err == nil {
+			return fmt.Errorf("%w: %s/%s", storage.ErrShortcutExists, group, shortcut.Name)
+		}
+		return storage.AddShortcut(b.l.Config, group, shortcut)
+	})
+}
+
+func (b *apiBackend) UpdateShortcut(group, name string, shortcut model.Shortcut) error {
+	return b.mutate(func() error {
+		if shortcut.Name != name {
+			if _, err := storage.FindShortcut(b.l.Config, group, shortcut.Name); err == nil {
+				return fmt.Errorf("%w: %s/%s", storage.ErrShortcutExists, group, shortcut.Name)
+			}
+		}
+		return storage.UpdateShortcut(b.l.Config, group, name, shortcut)
+	})
+}
+
+func (b *apiBackend) DeleteShortcut(group, name string) error {
+	return b.mutate(func() error {
+		return storage.RemoveShortcut(b.l.Config, group, name)
+	})
+}
+
+func (b *apiBackend) SwitchGroup(name string) error {
+	var err error
+	fyne.DoAndWait(func() {
+		err = b.l.switchToGroup(name)
+	})
+	return err
+}
+
+func (b *apiBackend) ShowWindow() {
+	fyne.DoAndWait(b.l.showMainWindow)
+}
+
+func (b *apiBackend) HideWindow() {
+	fyne.DoAndWait(func() {
+		b.l.Window.Hide()
+	})
+}
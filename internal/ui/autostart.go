@@ -1,144 +1,110 @@
 package ui
 
 import (
-	"log"
+	"errors"
 	"os"
 	"path/filepath"
-	"syscall"
-	"unsafe"
+	"strconv"
 
-	"golang.org/x/sys/windows/registry"
+	"go-musetool/internal/autostart"
+	"go-musetool/internal/deeplink"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/storage"
 )
 
-const (
-	runKeyPath = `Software\Microsoft\Windows\CurrentVersion\Run`
-	appName    = "GoMuseTool"
-)
-
-// SetAutoStart enables or disables auto-start on Windows boot
-func SetAutoStart(enable bool) error {
-	k, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+// executablePath returns the absolute path of the running executable
+func executablePath() (string, error) {
+	exePath, err := os.Executable()
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer k.Close()
+	return filepath.Abs(exePath)
+}
 
-	if enable {
-		// Get the executable path
-		exePath, err := os.Executable()
-		if err != nil {
-			return err
-		}
-		// Convert to absolute path
-		absPath, err := filepath.Abs(exePath)
-		if err != nil {
-			return err
-		}
-		// Set the registry value
-		err = k.SetStringValue(appName, absPath)
-		if err != nil {
-			return err
-		}
-		log.Printf("Auto-start enabled: %s", absPath)
-	} else {
-		// Remove the registry value
-		err = k.DeleteValue(appName)
-		if err != nil && err != registry.ErrNotExist {
-			return err
-		}
-		log.Printf("Auto-start disabled")
+// autoStartOptions 根据 "启动时隐藏到托盘" 和 "延迟启动" 设置生成自启动参数
+func (l *LauncherApp) autoStartOptions() autostart.Options {
+	var args []string
+	if l.Config.StartHidden {
+		args = append(args, "--tray")
 	}
-
-	return nil
+	if l.Config.StartDelay > 0 {
+		args = append(args, "--delay", strconv.Itoa(l.Config.StartDelay))
+	}
+	return autostart.Options{Args: args}
 }
 
-// IsAutoStartEnabled checks if auto-start is currently enabled
-func IsAutoStartEnabled() bool {
-	k, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
+// SetAutoStart enables or disables starting MuseTool at login. Call it again
+// after changing StartHidden or StartDelay to rewrite the entry.
+func (l *LauncherApp) SetAutoStart(enable bool) error {
+	if !enable {
+		return autostart.Disable()
+	}
+	exePath, err := executablePath()
 	if err != nil {
-		return false
+		return err
 	}
-	defer k.Close()
-
-	_, _, err = k.GetStringValue(appName)
-	return err == nil
-}
-
-// MinimizeWindow minimizes a window to the taskbar
-func MinimizeWindow(hwnd uintptr) {
-	const SW_MINIMIZE = 6
-	showWindow := syscall.NewLazyDLL("user32.dll").NewProc("ShowWindow")
-	showWindow.Call(hwnd, SW_MINIMIZE)
+	return autostart.Enable(exePath, l.autoStartOptions())
 }
 
-// HideWindow hides a window completely
-func HideWindow(hwnd uintptr) {
-	const SW_HIDE = 0
-	showWindow := syscall.NewLazyDLL("user32.dll").NewProc("ShowWindow")
-	showWindow.Call(hwnd, SW_HIDE)
-}
-
-// ShowWindow shows a window
-func ShowWindowNormal(hwnd uintptr) {
-	const SW_SHOW = 5
-	const SW_RESTORE = 9
-	showWindow := syscall.NewLazyDLL("user32.dll").NewProc("ShowWindow")
-	// First restore if minimized
-	showWindow.Call(hwnd, SW_RESTORE)
-	// Then show
-	showWindow.Call(hwnd, SW_SHOW)
-	// Bring to foreground
-	setForegroundWindow := syscall.NewLazyDLL("user32.dll").NewProc("SetForegroundWindow")
-	setForegroundWindow.Call(hwnd)
+// IsAutoStartEnabled checks if auto-start is currently enabled. An enabled
+// entry that starts another executable (the program was moved or updated
+// to a new folder) or uses outdated arguments is rewritten for this one.
+func (l *LauncherApp) IsAutoStartEnabled() bool {
+	enabled, err := l.checkAutoStart()
+	if err != nil {
+		logger.Error("Failed to read auto-start entry: %v", err)
+	}
+	return enabled
 }
 
-// IsWindowVisible checks if a window is currently visible
-func IsWindowVisible(hwnd uintptr) bool {
-	isWindowVisible := syscall.NewLazyDLL("user32.dll").NewProc("IsWindowVisible")
-	ret, _, _ := isWindowVisible.Call(hwnd)
-	return ret != 0
+// checkAutoStart 读取自启动项并在需要时修复，读取失败时返回错误
+func (l *LauncherApp) checkAutoStart() (bool, error) {
+	entry, err := autostart.Read()
+	if errors.Is(err, autostart.ErrNotInstalled) {
+		return false, nil
+	}
+	if err != nil || !entry.Enabled {
+		return false, err
+	}
+	exePath, err := executablePath()
+	if err != nil {
+		return true, nil
+	}
+	if opts := l.autoStartOptions(); !entry.Matches(exePath, opts) {
+		logger.Info("Repairing auto-start entry: %s -> %s", entry.Path, exePath)
+		if err := autostart.Enable(exePath, opts); err != nil {
+			logger.Error("Failed to repair auto-start entry: %v", err)
+		}
+	}
+	return true, nil
 }
 
-// BringWindowToFront brings a window to the foreground
-func BringWindowToFront(hwnd uintptr) {
-	// Remove topmost flag temporarily
-	SetWindowAlwaysOnTop(hwnd, false)
-
-	// Show and restore the window
-	const SW_RESTORE = 9
-	showWindow := syscall.NewLazyDLL("user32.dll").NewProc("ShowWindow")
-	showWindow.Call(hwnd, SW_RESTORE)
-
-	// Set foreground
-	setForegroundWindow := syscall.NewLazyDLL("user32.dll").NewProc("SetForegroundWindow")
-	setForegroundWindow.Call(hwnd)
-
-	// Restore topmost flag
-	SetWindowAlwaysOnTop(hwnd, true)
+// syncAutoStart 让配置与系统中的自启动项保持一致：用户可能在系统设置
+// （任务管理器、桌面环境的 "启动应用程序"）中关闭或打开了自启动
+func (l *LauncherApp) syncAutoStart() {
+	enabled, err := l.checkAutoStart()
+	if err != nil {
+		logger.Error("Failed to read auto-start entry: %v", err)
+		return
+	}
+	if enabled == l.Config.AutoStart {
+		return
+	}
+	logger.Info("Auto-start changed outside MuseTool: %v", enabled)
+	l.Config.AutoStart = enabled
+	if err := storage.SaveConfig(l.ConfigPath, l.Config); err != nil {
+		logger.Error("error saving config: %v", err)
+	}
 }
 
-// FlashWindow flashes the window to get user attention
-func FlashWindow(hwnd uintptr) {
-	const FLASHW_ALL = 3
-	const FLASHW_TIMERNOFG = 12
-
-	type FLASHWINFO struct {
-		cbSize    uint32
-		hwnd      uintptr
-		dwFlags   uint32
-		uCount    uint32
-		dwTimeout uint32
+// setURLHandler registers or removes the musetool:// handler for this executable
+func setURLHandler(enable bool) error {
+	if !enable {
+		return deeplink.Unregister()
 	}
-
-	flashWindowEx := syscall.NewLazyDLL("user32.dll").NewProc("FlashWindowEx")
-
-	fwi := FLASHWINFO{
-		cbSize:    uint32(unsafe.Sizeof(FLASHWINFO{})),
-		hwnd:      hwnd,
-		dwFlags:   FLASHW_ALL | FLASHW_TIMERNOFG,
-		uCount:    3,
-		dwTimeout: 0,
+	exePath, err := executablePath()
+	if err != nil {
+		return err
 	}
-
-	flashWindowEx.Call(uintptr(unsafe.Pointer(&fwi)))
+	return deeplink.Register(exePath)
 }
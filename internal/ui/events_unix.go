@@ -0,0 +1,139 @@
+//go:build !windows
+
+package ui
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"go-musetool/internal/logger"
+)
+
+// On X11 the events come from helper programs that print them, so MuseTool
+// needs no X11 bindings:
+//
+//	xev     (x11-utils)  window moves; without it edge docking and auto-hide are off
+//	xprop   (x11-utils)  active window changes for "on top only when docked"
+//	xinput  (xinput)     pointer motion; without it a docked window only hides
+//	                     when it is set to come back by hotkey
+//
+// A missing program is logged once and the feature it drives stays off.
+
+const (
+	// moveEndDelay X11 没有"移动结束"事件：这段时间内没有新的 ConfigureNotify 即视为结束
+	moveEndDelay = 300 * time.Millisecond
+	// cursorQueryGap 连续移动时两次查询光标位置的最小间隔，限制 xdotool 的启动次数
+	cursorQueryGap = 50 * time.Millisecond
+)
+
+// unavailableTools 已记录过无法启动的程序
+var unavailableTools sync.Map
+
+// watchTool runs an X11 helper that prints events until it is killed and
+// calls onLine for each line of its output. ok is false when there is no X
+// display or the tool cannot be started.
+func watchTool(onLine func(string), tool string, args ...string) (stop func(), ok bool) {
+	if os.Getenv("DISPLAY") == "" {
+		return func() {}, false
+	}
+	cmd := exec.Command(tool, args...)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return func() {}, false
+	}
+	if err := cmd.Start(); err != nil {
+		if _, logged := unavailableTools.LoadOrStore(tool, true); !logged {
+			logger.Info("Cannot start %s (%v), the events it reports are not available", tool, err)
+		}
+		return func() {}, false
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(out)
+		for scanner.Scan() {
+			onLine(scanner.Text())
+		}
+		cmd.Wait()
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { cmd.Process.Kill() })
+	}, true
+}
+
+// WatchWindow follows the ConfigureNotify events xev prints for the window;
+// the window manager sends one for every move
+func (p *unixPlatform) WatchWindow(hwnd uintptr, onEvent func(windowEvent)) (func(), bool) {
+	if hwnd == 0 {
+		return func() {}, false
+	}
+	var mu sync.Mutex
+	var ended *time.Timer
+	return watchTool(func(line string) {
+		if !strings.HasPrefix(line, "ConfigureNotify") {
+			return
+		}
+		onEvent(windowMoved)
+		mu.Lock()
+		defer mu.Unlock()
+		if ended != nil {
+			ended.Stop()
+		}
+		ended = time.AfterFunc(moveEndDelay, func() { onEvent(windowMoveEnded) })
+	}, "xev", "-id", windowID(hwnd), "-event", "structure")
+}
+
+// WatchForeground follows _NET_ACTIVE_WINDOW on the root window
+func (p *unixPlatform) WatchForeground(onChange func()) (func(), bool) {
+	return watchTool(func(string) { onChange() }, "xprop", "-spy", "-root", "_NET_ACTIVE_WINDOW")
+}
+
+// WatchCursor listens to the raw motion events xinput prints. They carry
+// deltas rather than a position, so each motion is followed by a position
+// query: the first one at once, then at most one per cursorQueryGap while the
+// pointer keeps moving. Nothing is queried while the pointer is still.
+func (p *unixPlatform) WatchCursor(onMove func(x, y int)) (func(), bool) {
+	moved := make(chan struct{}, 1)
+	stopTool, ok := watchTool(func(line string) {
+		if !strings.Contains(line, "(RawMotion)") {
+			return
+		}
+		select {
+		case moved <- struct{}{}:
+		default: // 上一次移动还没有查询
+		}
+	}, "xinput", "test-xi2", "--root")
+	if !ok {
+		return stopTool, false
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-moved:
+			case <-done:
+				return
+			}
+			onMove(p.CursorPos())
+			select {
+			case <-time.After(cursorQueryGap):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			stopTool()
+			close(done)
+		})
+	}, true
+}
@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"log"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	procSetWinEventHook     = user32.NewProc("SetWinEventHook")
+	procSetWindowsHookExW   = user32.NewProc("SetWindowsHookExW")
+	procUnhookWindowsHookEx = user32.NewProc("UnhookWindowsHookEx")
+	procCallNextHookEx      = user32.NewProc("CallNextHookEx")
+	procGetMessageW         = user32.NewProc("GetMessageW")
+	procPeekMessageW        = user32.NewProc("PeekMessageW")
+	procPostThreadMessageW  = user32.NewProc("PostThreadMessageW")
+)
+
+const (
+	EVENT_SYSTEM_FOREGROUND     = 0x0003
+	EVENT_SYSTEM_MOVESIZEEND    = 0x000B
+	EVENT_OBJECT_LOCATIONCHANGE = 0x800B
+	WINEVENT_OUTOFCONTEXT       = 0x0000
+	OBJID_WINDOW                = 0
+	WH_MOUSE_LL                 = 14
+	WM_MOUSEMOVE                = 0x0200
+
+	wmEventCall = 0x8000 + 1 // WM_APP+1：在事件线程上执行排队的调用
+)
+
+type MSG struct {
+	Hwnd    uintptr
+	Message uint32
+	WParam  uintptr
+	LParam  uintptr
+	Time    uint32
+	Pt      POINT
+}
+
+// MSLLHOOKSTRUCT 低级鼠标钩子收到的鼠标事件
+type MSLLHOOKSTRUCT struct {
+	Pt          POINT
+	MouseData   uint32
+	Flags       uint32
+	Time        uint32
+	DwExtraInfo uintptr
+}
+
+// 回调只创建一次：syscall.NewCallback 创建的回调不会释放
+var (
+	winEventCallback  = syscall.NewCallback(winEventProc)
+	mouseHookCallback = syscall.NewCallback(mouseHookProc)
+)
+
+// eventThread owns the WinEvent and low-level mouse hooks. Both call back on
+// the thread that installed them while it waits for messages, so one locked
+// OS thread installs them and runs a message loop for the life of the
+// process. Hook callbacks only hand the event to the watchers' channels;
+// each watcher has a goroutine that runs its callback.
+type eventThread struct {
+	start    sync.Once
+	threadID uint32
+	calls    chan func()
+
+	mu         sync.Mutex
+	nextID     int
+	windows    map[uintptr]chan windowEvent // 按窗口句柄
+	foreground map[int]chan struct{}
+	cursors    map[int]chan POINT
+
+	mouseHook uintptr // 只在事件线程上访问
+}
+
+var events = &eventThread{
+	calls:      make(chan func(), 16),
+	windows:    map[uintptr]chan windowEvent{},
+	foreground: map[int]chan struct{}{},
+	cursors:    map[int]chan POINT{},
+}
+
+func (t *eventThread) loop(ready chan<- struct{}) {
+	runtime.LockOSThread()
+
+	t.threadID = windows.GetCurrentThreadId()
+	// PeekMessage 为线程创建消息队列，之后 PostThreadMessage 才能成功
+	var m MSG
+	procPeekMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0, 0)
+
+	// 窗口移动只监听本进程；前台窗口切换需要监听所有进程
+	pid := uintptr(windows.GetCurrentProcessId())
+	for _, h := range []struct{ event, pid uintptr }{
+		{EVENT_OBJECT_LOCATIONCHANGE, pid},
+		{EVENT_SYSTEM_MOVESIZEEND, pid},
+		{EVENT_SYSTEM_FOREGROUND, 0},
+	} {
+		if r, _, err := procSetWinEventHook.Call(h.event, h.event, 0, winEventCallback, h.pid, 0, WINEVENT_OUTOFCONTEXT); r == 0 {
+			log.Printf("SetWinEventHook(0x%X) failed: %v", h.event, err)
+		}
+	}
+	close(ready)
+
+	// 钩子回调在 GetMessage 等待时执行
+	for {
+		r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+		if int32(r) <= 0 {
+			return
+		}
+		if m.Message != wmEventCall {
+			continue
+		}
+		for pending := true; pending; {
+			select {
+			case f := <-t.calls:
+				f()
+			default:
+				pending = false
+			}
+		}
+	}
+}
+
+// call runs f on the event thread, starting the thread first if needed, and
+// waits for it
+func (t *eventThread) call(f func()) {
+	t.start.Do(func() {
+		ready := make(chan struct{})
+		go t.loop(ready)
+		<-ready
+	})
+	done := make(chan struct{})
+	t.calls <- func() { f(); close(done) }
+	procPostThreadMessageW.Call(uintptr(t.threadID), wmEventCall, 0, 0)
+	<-done
+}
+
+func (t *eventThread) newID() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	return t.nextID
+}
+
+// addWatch registers a watcher channel under key and runs deliver for each
+// value sent to it. A second watcher under the same key replaces the first.
+func addWatch[K comparable, T any](watchers map[K]chan T, key K, size int, deliver func(T)) (stop func()) {
+	ch := make(chan T, size)
+	events.mu.Lock()
+	if old, ok := watchers[key]; ok {
+		close(old)
+	}
+	watchers[key] = ch
+	events.mu.Unlock()
+
+	go func() {
+		for v := range ch {
+			deliver(v)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			events.mu.Lock()
+			defer events.mu.Unlock()
+			if watchers[key] == ch {
+				delete(watchers, key)
+				close(ch)
+			}
+		})
+	}
+}
+
+func winEventProc(hook, event, hwnd, idObject, idChild, thread, eventTime uintptr) uintptr {
+	if int32(idObject) != OBJID_WINDOW || int32(idChild) != 0 {
+		return 0
+	}
+	switch event {
+	case EVENT_SYSTEM_FOREGROUND:
+		events.notifyForeground()
+	case EVENT_OBJECT_LOCATIONCHANGE:
+		events.notifyWindow(hwnd, windowMoved)
+	case EVENT_SYSTEM_MOVESIZEEND:
+		events.notifyWindow(hwnd, windowMoveEnded)
+	}
+	return 0
+}
+
+// mouseHookProc 系统中每次鼠标移动都会经过这里，必须尽快返回
+func mouseHookProc(code, wParam uintptr, info *MSLLHOOKSTRUCT) uintptr {
+	if int32(code) >= 0 && wParam == WM_MOUSEMOVE {
+		events.notifyCursor(info.Pt)
+	}
+	r, _, _ := procCallNextHookEx.Call(0, code, wParam, uintptr(unsafe.Pointer(info)))
+	return r
+}
+
+func (t *eventThread) notifyWindow(hwnd uintptr, ev windowEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if ch, ok := t.windows[hwnd]; ok {
+		select {
+		case ch <- ev:
+		default: // 处理不过来时丢弃，之后的事件会带来最新位置
+		}
+	}
+}
+
+func (t *eventThread) notifyForeground() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, ch := range t.foreground {
+		select {
+		case ch <- struct{}{}:
+		default: // 已有待处理的通知
+		}
+	}
+}
+
+// notifyCursor 只保留最新的位置
+func (t *eventThread) notifyCursor(pt POINT) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, ch := range t.cursors {
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- pt:
+		default:
+		}
+	}
+}
+
+// updateMouseHook installs the low-level mouse hook while the cursor is
+// watched and removes it otherwise. It runs on the event thread.
+func (t *eventThread) updateMouseHook() {
+	t.mu.Lock()
+	want := len(t.cursors) > 0
+	t.mu.Unlock()
+
+	switch {
+	case want && t.mouseHook == 0:
+		var module windows.Handle
+		windows.GetModuleHandleEx(0, nil, &module)
+		h, _, err := procSetWindowsHookExW.Call(WH_MOUSE_LL, mouseHookCallback, uintptr(module), 0)
+		if h == 0 {
+			log.Printf("SetWindowsHookEx(WH_MOUSE_LL) failed: %v", err)
+		}
+		t.mouseHook = h
+	case !want && t.mouseHook != 0:
+		procUnhookWindowsHookEx.Call(t.mouseHook)
+		t.mouseHook = 0
+	}
+}
+
+func (windowsPlatform) WatchWindow(hwnd uintptr, onEvent func(windowEvent)) (func(), bool) {
+	if hwnd == 0 {
+		return func() {}, false
+	}
+	stop := addWatch(events.windows, hwnd, 64, onEvent)
+	events.call(func() {})
+	return stop, true
+}
+
+func (windowsPlatform) WatchForeground(onChange func()) (func(), bool) {
+	stop := addWatch(events.foreground, events.newID(), 1, func(struct{}) { onChange() })
+	events.call(func() {})
+	return stop, true
+}
+
+func (windowsPlatform) WatchCursor(onMove func(x, y int)) (func(), bool) {
+	stopWatch := addWatch(events.cursors, events.newID(), 1, func(pt POINT) { onMove(int(pt.X), int(pt.Y)) })
+	stop := func() {
+		stopWatch()
+		events.call(events.updateMouseHook)
+	}
+
+	var ok bool
+	events.call(func() {
+		events.updateMouseHook()
+		ok = events.mouseHook != 0
+	})
+	if !ok {
+		stop()
+	}
+	return stop, ok
+}
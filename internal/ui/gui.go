@@ -2,19 +2,32 @@ package ui
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"image/color"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"go-musetool/internal/api"
+	"go-musetool/internal/dock"
+	"go-musetool/internal/history"
+	"go-musetool/internal/hotkey"
+	"go-musetool/internal/ipc"
 	"go-musetool/internal/language"
 	"go-musetool/internal/launcher"
 	"go-musetool/internal/logger"
+	"go-musetool/internal/macro"
 	"go-musetool/internal/model"
+	"go-musetool/internal/process"
+	"go-musetool/internal/schedule"
 	"go-musetool/internal/storage"
 	"go-musetool/internal/version"
 
@@ -44,12 +57,53 @@ type LauncherApp struct {
 	DeleteGroupDialogForWindow fyne.Window // 删除分组对话框(For)窗口引用
 	DeleteShortcutWindow       fyne.Window // 删除快捷方式确认窗口引用
 	AboutWindow                fyne.Window // 关于窗口引用
+	WorkspaceWindow            fyne.Window // 全部启动设置窗口引用
+	InputPromptWindow          fyne.Window // 参数输入窗口引用
+	RoutesWindow               fyne.Window // 打开规则窗口引用
 	MainWindowIconData         []byte
+	ipcServer                  *ipc.Server // 接收后续实例请求的 IPC 服务
+	apiServer                  *api.Server // 本地自动化 API 服务
 	isTrueFullscreen           bool
 	preFullscreenState         struct {
 		x, y, w, h int
 		style      uintptr
 	}
+
+	// 启动历史
+	CurrentView string         // 非空时显示虚拟分组（viewRecent / viewMostUsed）
+	History     *history.Store // 加载失败时为 nil
+
+	// 运行状态标记
+	runningTiles   []runningTile     // 当前显示的可跟踪图标
+	runningTracker *process.Tracker  // 程序的启动和退出
+	lnkTargets     map[string]string // .lnk 解析缓存
+
+	scheduler *schedule.Scheduler // 定时和启动时运行
+
+	searchQuery string        // 搜索框内容，重建界面后保留
+	toast       *widget.PopUp // 当前显示的提示
+
+	trayIcons map[string]fyne.Resource // 托盘菜单图标缓存，按图标路径
+
+	dockContainer *InteractiveContainer // 主窗口内容的外层，负责边缘停靠和自动隐藏
+
+	// 主窗口句柄和置顶状态
+	hwnd       atomic.Uintptr // 主窗口句柄，找到后缓存
+	topmost    bool           // 上次设置的置顶状态
+	topmostSet bool           // 是否设置过置顶状态
+
+	// 全局热键
+	hotkeys         *hotkey.Manager // 没有可用的后端时为 nil
+	hotkeyErr       error           // 后端不可用的原因
+	hotkeySignature string          // 上次注册的热键，未改变时不重新注册
+	mainHidden      bool            // 主窗口已隐藏到托盘
+	searchEntry     *widget.Entry
+
+	// StartHidden 为 true 时启动后只显示托盘图标（--tray），主窗口在第一次
+	// 显示时才创建，所以届时再恢复位置和样式
+	StartHidden    bool
+	restorePending bool
+	windowShown    bool // 主窗口已显示过，原生窗口已创建（只在 UI 线程访问）
 }
 
 // SetMainWindowIconData 设置主窗口图标数据
@@ -192,114 +246,18 @@ func NewLauncherApp(config *model.Config, configPath string, iconData []byte) *L
 		}
 	}) */
 
-	// 2. 设置系统托盘 (使用原生 Windows API 以支持左键点击)
-	// 注意: 我们不再使用 Fyne 的 desk.SetSystemTrayMenu，而是使用自定义的 InitTray
-	// 使用与主窗口相同的图标数据,确保托盘图标和主窗口图标一致
-	// Create a channel to handle show requests from tray
-	// This decouples the OS-locked tray thread from Fyne's thread handling
-	trayShowChan := make(chan struct{}, 1)
-	go func() {
-		for range trayShowChan {
-			// fyne.Do schedules the function on the main UI thread
-			fyne.Do(func() {
-				if l.Window != nil {
-					l.Window.Show()
-					l.Window.RequestFocus()
-				}
-			})
-		}
-	}()
-
-	InitTrayWithData(l.getMainWindowIconData(), func() {
-		// OnShow callback (Left click or Menu Show)
-		// Non-blocking send to channel
-		select {
-		case trayShowChan <- struct{}{}:
-		default:
-		}
-	}, func() {
-		// OnExit callback (Menu Exit)
-		l.saveWindowState()
-		a.Quit()
-	})
-	log.Println("Native Windows system tray initialized")
+	// 2. 设置系统托盘（分组子菜单可直接启动快捷方式，setupUI 时重建）
+	l.initTray()
 
 	// 4. 从配置加载窗口大小和位置
-	// 注意：必须使用 Windows API 恢复大小，因为 Fyne 的 Resize 设置的是内容大小，而我们保存的是窗口总大小
-	if config.WindowWidth > 0 && config.WindowHeight > 0 && config.WindowX >= 0 && config.WindowY >= 0 {
-		go func() {
-			// 等待窗口创建完成
-			time.Sleep(200 * time.Millisecond)
-			hwnd := GetWindowHandle(language.T().WindowTitle)
-			if hwnd == 0 {
-				return
-			}
-
-			// 验证位置是否在屏幕范围内
-			sw, sh := GetScreenSize()
-			x, y := config.WindowX, config.WindowY
-			w, h := config.WindowWidth, config.WindowHeight
-
-			// 确保窗口至少有一部分在屏幕内
-			minVisible := 100
-			if x > sw-minVisible {
-				x = sw - minVisible
-			}
-			if x < -w+minVisible {
-				x = -w + minVisible
-			}
-			if y > sh-minVisible {
-				y = sh - minVisible
-			}
-			if y < 0 {
-				y = 0
-			}
-
-			// 使用 WinAPI 同时设置位置和大小
-			MoveAndResizeWindow(hwnd, x, y, w, h)
-		}()
+	if l.hasSavedGeometry() {
+		go l.restoreWindowGeometry()
 	} else {
 		// No valid window geometry, use default size
 		w.Resize(fyne.NewSize(800, 600))
 		w.CenterOnScreen()
 	}
 
-	// 启动定期强制置顶的 goroutine (独立于窗口位置恢复逻辑)
-	go func() {
-		ticker := time.NewTicker(200 * time.Millisecond) // 提高检查频率以获得更好的响应性
-		for range ticker.C {
-			// 如果窗口处于全屏模式，则跳过所有置顶逻辑
-			if l.Window != nil && l.Window.FullScreen() {
-				continue
-			}
-
-			// 检查是否有任何子窗口存在 (使用内部引用而不是系统调用)
-			hasChildWindow := l.SettingsWindow != nil ||
-				l.ShortcutWindow != nil ||
-				l.AddGroupWindow != nil ||
-				l.EditGroupWindow != nil ||
-				l.DeleteGroupWindow != nil ||
-				l.EditGroupDialogForWindow != nil ||
-				l.DeleteGroupDialogForWindow != nil ||
-				l.DeleteShortcutWindow != nil ||
-				l.AboutWindow != nil
-
-			// 获取主窗口句柄
-			mainHwnd := GetWindowHandle(language.T().WindowTitle)
-			if mainHwnd == 0 {
-				continue
-			}
-
-			if hasChildWindow {
-				// 如果有子窗口存在，显式禁用主窗口的置顶状态
-				SetWindowAlwaysOnTop(mainHwnd, false)
-			} else {
-				// 只有在所有子窗口都不存在时，才强制主窗口置顶
-				SetWindowAlwaysOnTop(mainHwnd, true)
-			}
-		}
-	}()
-
 	// 3. 设置窗口关闭事件处理
 	// 使用 SetCloseIntercept 而不是 SetOnClosed,避免递归调用问题
 	w.SetCloseIntercept(func() {
@@ -311,7 +269,7 @@ func NewLauncherApp(config *model.Config, configPath string, iconData []byte) *L
 			logger.Debug("Dialog already shown, executing direct action. MinimizeToTray=%v", l.Config.MinimizeToTray)
 			if l.Config.MinimizeToTray {
 				// 最小化到托盘
-				l.Window.Hide()
+				l.hideMainWindow()
 			} else {
 				// 退出程序
 				l.saveWindowState()
@@ -342,7 +300,7 @@ func NewLauncherApp(config *model.Config, configPath string, iconData []byte) *L
 					}
 					closeDialog.Hide()
 					// 执行最小化
-					l.Window.Hide()
+					l.hideMainWindow()
 				}),
 				widget.NewButton(language.T().CloseDialogExit, func() {
 					logger.Debug("SetCloseIntercept: User selected Exit")
@@ -372,12 +330,115 @@ func NewLauncherApp(config *model.Config, configPath string, iconData []byte) *L
 		logger.Debug("SetCloseIntercept: Close dialog shown")
 	})
 
+	// 旧配置中的快捷方式没有 ID，补齐后保存，启动历史才能关联到快捷方式
+	if storage.EnsureShortcutIDs(l.Config) {
+		if err := storage.SaveConfig(l.ConfigPath, l.Config); err != nil {
+			log.Printf("error saving config: %v", err)
+		}
+	}
+	l.openHistory()
+
+	// 建立界面时图标会登记到运行状态跟踪中
+	l.startRunningTracker()
 	log.Println("setting up ui content...")
 	l.setupUI()
 	log.Println("ui content setup complete.")
+
+	// 文本片段需要剪贴板，由 UI 提供处理程序
+	launcher.RegisterHandler(model.KindSnippet, l.copySnippet)
+	launcher.RegisterHandler(model.KindMacro, l.runMacro)
+	launcher.SetTerminal(l.Config.Terminal)
+	launcher.SetRoutes(l.Config.Routes)
+
+	// 按配置启动本地自动化 API
+	l.applyAPISettings()
+
+	// 处理程序注册完成后再开始定时任务，启动时运行的快捷方式才能正常启动
+	l.startScheduler()
+
+	// 自启动可能在系统设置中被打开或关闭
+	l.syncAutoStart()
+
+	l.startHotkeys()
+
+	// 程序可能被移动过，刷新 musetool:// 注册的可执行文件路径
+	if l.Config.URLHandler {
+		go func() {
+			if err := setURLHandler(true); err != nil {
+				logger.Error("Failed to refresh URL handler: %v", err)
+			}
+		}()
+	}
 	return l
 }
 
+// hasSavedGeometry 配置中是否保存了有效的窗口位置和大小
+func (l *LauncherApp) hasSavedGeometry() bool {
+	c := l.Config
+	return c.WindowWidth > 0 && c.WindowHeight > 0 && c.WindowX >= 0 && c.WindowY >= 0
+}
+
+// restoreWindowGeometry 恢复保存的窗口位置和大小
+// 注意：必须使用 Windows API 恢复大小，因为 Fyne 的 Resize 设置的是内容大小，而我们保存的是窗口总大小
+func (l *LauncherApp) restoreWindowGeometry() {
+	// 等待窗口创建完成
+	time.Sleep(200 * time.Millisecond)
+	hwnd := l.mainWindowHandle()
+	if hwnd == 0 {
+		return
+	}
+
+	// 验证位置是否在屏幕范围内
+	sw, sh := GetScreenSize()
+	x, y := l.Config.WindowX, l.Config.WindowY
+	w, h := l.Config.WindowWidth, l.Config.WindowHeight
+
+	// 确保窗口至少有一部分在屏幕内
+	minVisible := 100
+	if x > sw-minVisible {
+		x = sw - minVisible
+	}
+	if x < -w+minVisible {
+		x = -w + minVisible
+	}
+	if y > sh-minVisible {
+		y = sh - minVisible
+	}
+	if y < 0 {
+		y = 0
+	}
+
+	// 使用 WinAPI 同时设置位置和大小
+	MoveAndResizeWindow(hwnd, x, y, w, h)
+}
+
+// showMainWindow 显示并激活主窗口（托盘、第二实例等调用，必须在 UI 线程执行）
+func (l *LauncherApp) showMainWindow() {
+	if l.Window != nil {
+		l.mainHidden = false
+		l.Window.Show()
+		l.Window.RequestFocus()
+		// 隐藏在屏幕边缘外时移回可见位置（只允许热键显示时这是唯一的入口）
+		if l.dockContainer != nil {
+			l.dockContainer.Reveal()
+		}
+		l.mainWindowShown()
+		if l.restorePending {
+			// 隐藏启动后第一次显示：原生窗口现在才存在
+			l.restorePending = false
+			if l.hasSavedGeometry() {
+				go l.restoreWindowGeometry()
+			}
+		}
+	}
+}
+
+// hideMainWindow 隐藏主窗口到托盘
+func (l *LauncherApp) hideMainWindow() {
+	l.mainHidden = true
+	l.Window.Hide()
+}
+
 // 辅助函数：根据配置应用主题
 func (l *LauncherApp) applyTheme() {
 	// 1. 设置 Fyne 内部主题 (Must run on UI thread)
@@ -399,73 +460,82 @@ func (l *LauncherApp) applyTheme() {
 	})
 
 	// 2. 设置系统标题栏颜色 (Runs in its own goroutine, independent of Fyne UI thread)
-	if l.Window != nil {
-		go func() {
-			// 等待窗口创建并获取句柄
-			// 重试几次以确保窗口已创建
-			// 重试几次以确保窗口已创建
-			var hwnd uintptr
-			for i := 0; i < 10; i++ {
-				hwnd = GetWindowHandle(language.T().WindowTitle)
-				if hwnd != 0 {
-					break
-				}
-				time.Sleep(100 * time.Millisecond)
-			}
+	// 主窗口还没有显示时原生窗口不存在，由 mainWindowShown 应用
+	if l.Window != nil && l.windowShown {
+		go l.applyMainWindowStyle()
+	}
+}
 
-			if hwnd != 0 {
-				var color uint32
-				// Windows DWM 颜色使用 BGR 格式 (0xBBGGRR)
-				if l.Config.TitleBarColor != 0 {
-					color = l.Config.TitleBarColor
-				} else {
-					switch l.Config.ThemePreference {
-					case model.ThemeDark:
-						// 深灰色 (RGB 0x202020 -> BGR 0x202020)
-						color = 0x202020
-					case model.ThemeLight:
-						// 浅灰色 (RGB 0xF0F0F0 -> BGR 0xF0F0F0)
-						color = 0xF0F0F0
-					default:
-						// 跟随系统：检测系统深色模式
-						if IsSystemDarkMode() {
-							color = 0x202020 // 深色模式
-						} else {
-							color = 0xF0F0F0 // 浅色模式
-						}
-					}
-				}
+// mainWindowShown runs on the UI thread after the main window is shown.
+// Window.Show creates the native window, so the first call can look up its
+// handle and apply the native style; later calls do nothing.
+func (l *LauncherApp) mainWindowShown() {
+	if l.windowShown {
+		return
+	}
+	l.windowShown = true
+	go l.applyMainWindowStyle()
+}
 
-				SetTitleBarColor(hwnd, color)
-				log.Printf("applied title bar color: 0x%X", color)
+// applyMainWindowStyle sets the title bar color, opacity and taskbar style of the
+// native main window. It runs in its own goroutine.
+func (l *LauncherApp) applyMainWindowStyle() {
+	hwnd := l.mainWindowHandle()
+	if hwnd == 0 {
+		log.Println("main window handle not found, window style not applied")
+		return
+	}
 
-				// 应用配置中的窗口透明度
-				opacity := l.Config.Opacity
-				if opacity <= 0 || opacity > 1.0 {
-					opacity = 1.0 // 默认不透明（仅当未设置或无效时）
-				}
-				SetWindowOpacity(hwnd, opacity)
-				log.Printf("applied window opacity: %.2f", opacity)
+	var color uint32
+	// Windows DWM 颜色使用 BGR 格式 (0xBBGGRR)
+	if l.Config.TitleBarColor != 0 {
+		color = l.Config.TitleBarColor
+	} else {
+		switch l.Config.ThemePreference {
+		case model.ThemeDark:
+			// 深灰色 (RGB 0x202020 -> BGR 0x202020)
+			color = 0x202020
+		case model.ThemeLight:
+			// 浅灰色 (RGB 0xF0F0F0 -> BGR 0xF0F0F0)
+			color = 0xF0F0F0
+		default:
+			// 跟随系统：检测系统深色模式
+			if IsSystemDarkMode() {
+				color = 0x202020 // 深色模式
+			} else {
+				color = 0xF0F0F0 // 浅色模式
+			}
+		}
+	}
 
-				// 如果窗口不是全屏模式，则设置主窗口不在任务栏显示，避免与全屏模式冲突
-				if !l.Window.FullScreen() {
-					SetWindowNoTaskbar(hwnd)
-					log.Printf("SetWindowNoTaskbar applied (not fullscreen)")
-				} else {
-					log.Printf("SetWindowNoTaskbar skipped (fullscreen)")
-				}
+	SetTitleBarColor(hwnd, color)
+	log.Printf("applied title bar color: 0x%X", color)
 
-				// 确保窗口置顶
-				SetWindowAlwaysOnTop(hwnd, true)
-			}
-		}()
+	// 应用配置中的窗口透明度
+	opacity := l.Config.Opacity
+	if opacity <= 0 || opacity > 1.0 {
+		opacity = 1.0 // 默认不透明（仅当未设置或无效时）
+	}
+	SetWindowOpacity(hwnd, opacity)
+	log.Printf("applied window opacity: %.2f", opacity)
+
+	// 如果窗口不是全屏模式，则设置主窗口不在任务栏显示，避免与全屏模式冲突
+	if !l.Window.FullScreen() {
+		SetWindowNoTaskbar(hwnd)
+		log.Printf("SetWindowNoTaskbar applied (not fullscreen)")
+	} else {
+		log.Printf("SetWindowNoTaskbar skipped (fullscreen)")
 	}
+
+	// 修改窗口样式后重新应用置顶设置
+	fyne.Do(l.refreshTopmost)
 }
 
 // setupUI 设置主界面布局
 func (l *LauncherApp) setupUI() {
 	// 确保在每次重新创建 UI 时都应用主题
 	l.applyTheme()
+	l.reloadSchedule()
 
 	// 1. 创建内容区域容器
 	contentContainer := container.NewMax()
@@ -485,10 +555,24 @@ func (l *LauncherApp) setupUI() {
 		l.CurrentGroup = l.Config.Groups[0].Name
 	}
 
+	// 搜索框：有内容时显示所有分组中匹配的快捷方式
+	searchEntry := widget.NewEntry()
+	l.searchEntry = searchEntry
+	searchEntry.SetPlaceHolder(language.T().SearchPlaceholder)
+	searchEntry.SetText(l.searchQuery)
+	clearSearch := func() {
+		if l.searchQuery != "" {
+			l.searchQuery = ""
+			searchEntry.SetText("")
+		}
+	}
+
 	// 3. 定义切换分组的函数
 	var refreshTabBar func() // 前向声明
 	switchGroup := func(groupName string) {
+		clearSearch()
 		l.CurrentGroup = groupName
+		l.CurrentView = ""
 		// 更新内容区域
 		for _, g := range l.Config.Groups {
 			if g.Name == groupName {
@@ -503,8 +587,48 @@ func (l *LauncherApp) setupUI() {
 		}
 	}
 
+	// 切换到虚拟分组（最近使用 / 最常使用）
+	switchView := func(view string) {
+		clearSearch()
+		l.CurrentView = view
+		contentContainer.Objects = []fyne.CanvasObject{l.createHistoryContent(view, switchGroup)}
+		contentContainer.Refresh()
+		if refreshTabBar != nil {
+			refreshTabBar()
+		}
+	}
+
+	showSearch := func() {
+		contentContainer.Objects = []fyne.CanvasObject{l.createSearchContent(l.searchQuery, switchGroup)}
+		contentContainer.Refresh()
+	}
+	searchEntry.OnChanged = func(query string) {
+		if query == l.searchQuery {
+			return
+		}
+		l.searchQuery = query
+		if strings.TrimSpace(query) != "" {
+			showSearch()
+		} else if l.CurrentView != "" && l.historyEnabled() {
+			switchView(l.CurrentView)
+		} else {
+			switchGroup(l.CurrentGroup)
+		}
+	}
+	searchEntry.OnSubmitted = func(query string) {
+		if strings.TrimSpace(query) != "" {
+			l.submitSearch(query)
+		}
+	}
+
 	// 初始化显示当前分组内容
-	switchGroup(l.CurrentGroup)
+	if strings.TrimSpace(l.searchQuery) != "" {
+		showSearch()
+	} else if l.CurrentView != "" && l.historyEnabled() {
+		switchView(l.CurrentView)
+	} else {
+		switchGroup(l.CurrentGroup)
+	}
 
 	// 4. 创建自定义 Tab 栏
 	var tabBarContainer *fyne.Container
@@ -545,6 +669,20 @@ func (l *LauncherApp) setupUI() {
 					fyne.NewMenuItem(language.T().ContextMenuDeleteGroup, func() {
 						l.showDeleteGroupDialogFor(targetGroup)
 					}),
+					fyne.NewMenuItemSeparator(),
+					fyne.NewMenuItem(language.T().ContextMenuLaunchAll, func() {
+						l.launchWorkspaceFromMenu(targetGroup)
+					}),
+					fyne.NewMenuItem(language.T().ContextMenuWorkspaceSettings, func() {
+						l.showWorkspaceSettingsDialog(targetGroup)
+					}),
+					fyne.NewMenuItemSeparator(),
+					fyne.NewMenuItem(language.T().ContextMenuExportSnippets, func() {
+						l.exportSnippets(targetGroup)
+					}),
+					fyne.NewMenuItem(language.T().ContextMenuImportSnippets, func() {
+						l.importSnippets(targetGroup)
+					}),
 				)
 
 				menu := fyne.NewMenu("", menuItems...)
@@ -576,13 +714,14 @@ func (l *LauncherApp) setupUI() {
 			})
 
 			// 选中状态样式：HighImportance (填充)，未选中：LowImportance (扁平)
-			if groupName == l.CurrentGroup {
+			if groupName == l.CurrentGroup && l.CurrentView == "" {
 				btn.Importance = widget.HighImportance
 			} else {
 				btn.Importance = widget.LowImportance
 			}
 			buttons = append(buttons, btn)
 		}
+		buttons = append(buttons, l.historyTabButtons(switchView)...)
 
 		// 创建按钮容器
 		var buttonsContainer *fyne.Container
@@ -644,6 +783,20 @@ func (l *LauncherApp) setupUI() {
 					fyne.NewMenuItem(language.T().ContextMenuDeleteGroup, func() {
 						l.showDeleteGroupDialogFor(groupName)
 					}),
+					fyne.NewMenuItemSeparator(),
+					fyne.NewMenuItem(language.T().ContextMenuLaunchAll, func() {
+						l.launchWorkspaceFromMenu(groupName)
+					}),
+					fyne.NewMenuItem(language.T().ContextMenuWorkspaceSettings, func() {
+						l.showWorkspaceSettingsDialog(groupName)
+					}),
+					fyne.NewMenuItemSeparator(),
+					fyne.NewMenuItem(language.T().ContextMenuExportSnippets, func() {
+						l.exportSnippets(groupName)
+					}),
+					fyne.NewMenuItem(language.T().ContextMenuImportSnippets, func() {
+						l.importSnippets(groupName)
+					}),
 				)
 
 				menu := fyne.NewMenu("", menuItems...)
@@ -672,13 +825,14 @@ func (l *LauncherApp) setupUI() {
 				}
 			})
 
-			if groupName == l.CurrentGroup {
+			if groupName == l.CurrentGroup && l.CurrentView == "" {
 				btn.Importance = widget.HighImportance
 			} else {
 				btn.Importance = widget.LowImportance
 			}
 			newButtons = append(newButtons, btn)
 		}
+		newButtons = append(newButtons, l.historyTabButtons(switchView)...)
 
 		// 重新创建按钮容器
 		var buttonsContainer *fyne.Container
@@ -734,51 +888,61 @@ func (l *LauncherApp) setupUI() {
 		mainLayout = container.NewBorder(tabBarContainer, nil, nil, nil, paddedContent)
 	}
 
-	// 外层布局：工具栏固定在底部
-	borderLayout := container.NewBorder(nil, toolbar, nil, nil, mainLayout)
-	finalContent := NewInteractiveContainer(l.Window, borderLayout, l.saveWindowState)
-	finalContent.SetDebugMode(l.Config.DebugMode)
+	// 外层布局：工具栏和搜索框固定在底部
+	bottomBar := container.NewBorder(nil, nil, toolbar, nil, searchEntry)
+	borderLayout := container.NewBorder(nil, bottomBar, nil, nil, mainLayout)
+	// 停靠容器只创建一次，每次重建界面只替换内容
+	if l.dockContainer == nil {
+		l.dockContainer = NewInteractiveContainer(l.Window, borderLayout, l.saveWindowState)
+		l.dockContainer.OnDockChanged = func() { fyne.Do(l.updateTopmost) }
+		if hwnd := l.hwnd.Load(); hwnd != 0 {
+			go l.dockContainer.Watch(hwnd)
+		}
+	} else {
+		l.dockContainer.SetContent(borderLayout)
+	}
+	l.dockContainer.SetDebugMode(l.Config.DebugMode)
+	l.dockContainer.SetSettings(dockSettings(l.Config))
 
-	l.Window.SetContent(finalContent)
+	l.Window.SetContent(l.dockContainer)
+
+	// 分组和快捷方式可能已改变
+	l.refreshTray()
+	l.applyHotkeys(false)
 }
 
 func (l *LauncherApp) createGroupContent(group model.Group) fyne.CanvasObject {
+	l.runningTiles = nil
 	var items []fyne.CanvasObject
-	for i, s := range group.Shortcuts {
-		shortcut := s      // capture loop variable
-		shortcutIndex := i // 捕获索引
+	for _, i := range l.shortcutOrder(group.Shortcuts) {
+		shortcut := group.Shortcuts[i] // capture loop variable
+		shortcutIndex := i             // 捕获索引
 		btn := NewShortcutWidget(shortcut.Name, func() {
-			log.Printf("launching: %s (%s)", shortcut.Name, shortcut.Path)
-			if err := launcher.Open(shortcut.Path); err != nil {
-				log.Printf("error launching %s: %v", shortcut.Name, err)
-			}
+			l.launchShortcut(shortcut)
 		}, func(e *fyne.PointEvent) {
-			// 构建菜单项
-			menuItems := []*fyne.MenuItem{
-				fyne.NewMenuItem(language.T().ContextMenuOpenLocation, func() {
-					targetPath := shortcut.Path
-					// 如果是 .lnk 快捷方式，解析真实路径
-					if strings.HasSuffix(strings.ToLower(targetPath), ".lnk") {
-						resolved := ResolveLnkTarget(targetPath)
-						if resolved != "" {
-							targetPath = resolved
-						}
+			// 构建菜单项（不同类型提供不同的操作）
+			var menuItems []*fyne.MenuItem
+			switch launcher.KindOf(shortcut) {
+			case model.KindURL:
+				menuItems = append(menuItems, fyne.NewMenuItem(language.T().ContextMenuCopyLink, func() {
+					l.App.Clipboard().SetContent(shortcut.Path)
+				}))
+			case model.KindCommand:
+				menuItems = append(menuItems, fyne.NewMenuItem(language.T().ContextMenuCopyCommand, func() {
+					l.App.Clipboard().SetContent(shortcut.Command)
+				}))
+			case model.KindApplication, model.KindFile, model.KindFolder:
+				menuItems = append(menuItems, fyne.NewMenuItem(language.T().ContextMenuOpenLocation, func() {
+					// 在文件管理器中选中目标（.lnk / .desktop 先解析到真实路径）
+					if err := launcher.Reveal(shortcut.Path); err != nil {
+						log.Printf("error revealing %s: %v", shortcut.Path, err)
 					}
-
-					// 获取所在目录
-					dirPath := filepath.Dir(targetPath)
-
-					// check path exists
-					if _, err := os.Stat(dirPath); err == nil {
-						// 使用 explorer 打开目录
-						exec.Command("explorer", dirPath).Start()
-					} else {
-						log.Printf("Directory not found: %s", dirPath)
-					}
-				}),
+				}))
+			}
+			menuItems = append(menuItems,
 				fyne.NewMenuItem(language.T().ShortcutEdit, func() { l.showShortcutDialog(&shortcut) }),
 				fyne.NewMenuItem(language.T().ShortcutDelete, func() { l.showDeleteShortcutDialog(group.Name, shortcut.Name) }),
-			}
+			)
 
 			// 如果有多个分组，添加"移动到分组"子菜单
 			if len(l.Config.Groups) > 1 {
@@ -819,7 +983,8 @@ func (l *LauncherApp) createGroupContent(group model.Group) fyne.CanvasObject {
 			// 计算总偏移
 			offset := rowOffset*colsPerRow + colOffset
 
-			if offset != 0 {
+			// 按使用次数排序时位置由统计决定，不支持拖动
+			if offset != 0 && !l.sortingByUsage() {
 				targetIndex := shortcutIndex + offset
 				if targetIndex < 0 {
 					targetIndex = 0
@@ -830,6 +995,8 @@ func (l *LauncherApp) createGroupContent(group model.Group) fyne.CanvasObject {
 				l.reorderShortcut(group.Name, shortcutIndex, targetIndex)
 			}
 		})
+		btn.Tooltip = func() string { return l.shortcutTooltip(shortcut) }
+		l.trackRunning(shortcut, btn)
 		if shortcut.IconPath != "" {
 			if res, err := fyne.LoadResourceFromPath(shortcut.IconPath); err == nil {
 				btn.SetIcon(res)
@@ -850,6 +1017,93 @@ func (l *LauncherApp) createGroupContent(group model.Group) fyne.CanvasObject {
 	return container.NewScroll(paddedGrid)
 }
 
+// launchShortcut 启动快捷方式，所有入口（点击、托盘、第二实例）共用
+func (l *LauncherApp) launchShortcut(shortcut model.Shortcut) error {
+	// 含 {input:...} 占位符时先弹出输入窗口，提交后再启动
+	if names := launcher.InputNames(shortcut); len(names) > 0 {
+		l.showInputPrompt(shortcut, names)
+		return nil
+	}
+	return l.launchWithInputs(shortcut, nil)
+}
+
+// launchWithInputs 展开占位符后启动快捷方式（在 UI 线程调用）
+func (l *LauncherApp) launchWithInputs(shortcut model.Shortcut, inputs map[string]string) error {
+	p, err := l.prepareLaunch(shortcut, inputs)
+	if err != nil {
+		return err
+	}
+	err = p.start()
+	l.recordLaunch(p.shortcut, err)
+	l.rescanRunning()
+	return err
+}
+
+// pendingLaunch is a shortcut with its placeholders expanded, ready to start
+type pendingLaunch struct {
+	shortcut  model.Shortcut
+	focusPath string // 已在运行时切换到现有窗口，为空表示不切换
+}
+
+// prepareLaunch 读取剪贴板等 UI 状态并展开占位符（在 UI 线程调用）
+func (l *LauncherApp) prepareLaunch(shortcut model.Shortcut, inputs map[string]string) (pendingLaunch, error) {
+	values := launcher.Values{Inputs: inputs, SelectedGroup: l.CurrentGroup}
+	if launcher.UsesClipboard(shortcut) {
+		values.Clipboard = l.App.Clipboard().Content()
+	}
+	shortcut, err := launcher.Resolve(shortcut, values)
+	if err != nil {
+		log.Printf("error expanding %s: %v", shortcut.Name, err)
+		return pendingLaunch{}, err
+	}
+	return pendingLaunch{shortcut: shortcut, focusPath: l.focusPath(shortcut)}, nil
+}
+
+// start focuses the running program or launches the shortcut. Kinds whose
+// handler uses the UI (snippets, macros) must start on the UI thread; the
+// others may start on any goroutine.
+func (p pendingLaunch) start() error {
+	if p.focusPath != "" && focusRunning(p.shortcut, p.focusPath) {
+		return nil
+	}
+	log.Printf("launching: %s [%s] (%s)", p.shortcut.Name, launcher.KindOf(p.shortcut), launcher.Target(p.shortcut))
+	if err := launcher.Launch(p.shortcut); err != nil {
+		log.Printf("error launching %s: %v", p.shortcut.Name, err)
+		return err
+	}
+	return nil
+}
+
+// startsOnUIThread reports whether the shortcut's launch handler uses the UI
+func startsOnUIThread(shortcut model.Shortcut) bool {
+	kind := launcher.KindOf(shortcut)
+	return kind == model.KindSnippet || kind == model.KindMacro
+}
+
+// switchToGroup 切换当前显示的分组并重建界面
+func (l *LauncherApp) switchToGroup(groupName string) error {
+	if storage.FindGroup(l.Config, groupName) == nil {
+		return fmt.Errorf("%w: %s", storage.ErrGroupNotFound, groupName)
+	}
+	l.CurrentGroup = groupName
+	l.CurrentView = ""
+	l.setupUI()
+	return nil
+}
+
+// terminalLabel 返回终端设置中显示的名称
+func terminalLabel(name string) string {
+	switch name {
+	case launcher.TerminalAuto:
+		return language.T().SettingsTerminalAuto
+	case launcher.TerminalWindows:
+		return "Windows Terminal"
+	case launcher.TerminalConhost:
+		return "Console (conhost)"
+	}
+	return name
+}
+
 // showSettingsDialog 用于主题配置
 func (l *LauncherApp) showSettingsDialog() {
 	// Ensure single settings window
@@ -925,7 +1179,7 @@ func (l *LauncherApp) showSettingsDialog() {
 		opacityLabel.SetText(fmt.Sprintf(language.T().SettingsOpacity, value*100))
 		// 实时预览透明度效果 - 只应用到主窗口
 		go func() {
-			mainHwnd := GetWindowHandle("Go MuseTool")
+			mainHwnd := l.mainWindowHandle()
 			if mainHwnd != 0 {
 				SetWindowOpacity(mainHwnd, value)
 			}
@@ -943,13 +1197,119 @@ func (l *LauncherApp) showSettingsDialog() {
 	debugCheck.SetChecked(l.Config.DebugMode)
 
 	// Auto Start
-	autoStartCheck := widget.NewCheck(language.T().SettingsAutoStart, func(checked bool) {})
+	autoStartLabel := language.T().SettingsAutoStart
+	if runtime.GOOS != "windows" {
+		autoStartLabel = language.T().SettingsAutoStartLogin
+	}
+	autoStartCheck := widget.NewCheck(autoStartLabel, func(checked bool) {})
 	autoStartCheck.SetChecked(l.Config.AutoStart)
+	startHiddenCheck := widget.NewCheck(language.T().SettingsStartHidden, func(checked bool) {})
+	startHiddenCheck.SetChecked(l.Config.StartHidden)
+	startDelayEntry := widget.NewEntry()
+	startDelayEntry.SetPlaceHolder("0")
+	if l.Config.StartDelay > 0 {
+		startDelayEntry.SetText(strconv.Itoa(l.Config.StartDelay))
+	}
+
+	// musetool:// 链接处理
+	urlHandlerCheck := widget.NewCheck(language.T().SettingsURLHandler, func(checked bool) {})
+	urlHandlerCheck.SetChecked(l.Config.URLHandler)
 
 	// Minimize to Tray
 	minimizeToTrayCheck := widget.NewCheck(language.T().SettingsMinimizeToTray, func(checked bool) {})
 	minimizeToTrayCheck.SetChecked(l.Config.MinimizeToTray)
 
+	// Always on top
+	topmostPolicies := []string{model.TopmostAlways, model.TopmostDocked, model.TopmostNever}
+	topmostSelect := widget.NewSelect([]string{language.T().SettingsTopmostAlways, language.T().SettingsTopmostDocked, language.T().SettingsTopmostNever}, func(selected string) {})
+	topmostSelect.SetSelectedIndex(max(slices.Index(topmostPolicies, l.Config.AlwaysOnTop), 0))
+
+	// Local automation API
+	apiCheck := widget.NewCheck(language.T().SettingsAPIEnable, func(checked bool) {})
+	apiCheck.SetChecked(l.Config.APIEnabled)
+	apiPortEntry := widget.NewEntry()
+	apiPortEntry.SetPlaceHolder(strconv.Itoa(api.DefaultPort))
+	if l.Config.APIPort != 0 {
+		apiPortEntry.SetText(strconv.Itoa(l.Config.APIPort))
+	}
+	apiCopyTokenBtn := widget.NewButton(language.T().SettingsAPICopyToken, func() {
+		token, err := api.LoadOrCreateToken(filepath.Dir(l.ConfigPath))
+		if err != nil {
+			dialog.ShowError(err, settingsWin)
+			return
+		}
+		settingsWin.Clipboard().SetContent(token)
+		dialog.ShowInformation(language.T().Success, language.T().SettingsAPITokenCopied, settingsWin)
+	})
+
+	// Terminal
+	terminals := append([]string{launcher.TerminalAuto}, launcher.Terminals()...)
+	if !slices.Contains(terminals, l.Config.Terminal) {
+		terminals = append(terminals, l.Config.Terminal) // 手动修改过配置文件
+	}
+	var terminalLabels []string
+	for _, name := range terminals {
+		terminalLabels = append(terminalLabels, terminalLabel(name))
+	}
+	terminalSelect := widget.NewSelect(terminalLabels, func(selected string) {})
+	terminalSelect.SetSelectedIndex(slices.Index(terminals, l.Config.Terminal))
+
+	// Launch History
+	historyCheck := widget.NewCheck(language.T().SettingsHistoryEnable, func(checked bool) {})
+	historyCheck.SetChecked(!l.Config.HistoryDisabled)
+	sortByUsageCheck := widget.NewCheck(language.T().SettingsSortByUsage, func(checked bool) {})
+	sortByUsageCheck.SetChecked(l.Config.SortByUsage)
+	retentionDays := append([]int{}, historyRetentionOptions...)
+	if !slices.Contains(retentionDays, l.Config.HistoryRetentionDays) {
+		retentionDays = append(retentionDays, l.Config.HistoryRetentionDays) // 手动修改过配置文件
+	}
+	var retentionLabels []string
+	for _, days := range retentionDays {
+		if days <= 0 {
+			retentionLabels = append(retentionLabels, language.T().SettingsHistoryForever)
+		} else {
+			retentionLabels = append(retentionLabels, fmt.Sprintf(language.T().SettingsHistoryDays, days))
+		}
+	}
+	retentionSelect := widget.NewSelect(retentionLabels, func(selected string) {})
+	retentionSelect.SetSelectedIndex(slices.Index(retentionDays, l.Config.HistoryRetentionDays))
+	historyExportBtn := widget.NewButton(language.T().SettingsHistoryExport, func() {
+		// 临时禁用设置窗口的置顶状态，确保文件对话框显示在前面
+		settingsHwnd := GetWindowHandle(language.T().SettingsTitle)
+		if settingsHwnd != 0 {
+			SetWindowAlwaysOnTop(settingsHwnd, false)
+		}
+
+		filename, err := nativeDialog.File().Title(language.T().SettingsHistoryExport).Filter("CSV", "csv").Save()
+
+		// 恢复设置窗口的置顶状态
+		if settingsHwnd != 0 {
+			SetWindowAlwaysOnTop(settingsHwnd, true)
+		}
+
+		if err == nil && filename != "" {
+			if err := l.exportHistoryCSV(filename); err != nil {
+				dialog.ShowError(err, settingsWin)
+			}
+		}
+	})
+	historyClearBtn := widget.NewButton(language.T().SettingsHistoryClear, func() {
+		dialog.ShowConfirm(language.T().SettingsHistoryClear, language.T().SettingsHistoryClearConfirm, func(ok bool) {
+			if !ok || l.History == nil {
+				return
+			}
+			if err := l.History.Clear(); err != nil {
+				dialog.ShowError(err, settingsWin)
+				return
+			}
+			dialog.ShowInformation(language.T().Success, language.T().SettingsHistoryCleared, settingsWin)
+		}, settingsWin)
+	})
+	if l.History == nil {
+		historyExportBtn.Disable()
+		historyClearBtn.Disable()
+	}
+
 	// Reset Close Dialog Button
 	// resetCloseDialogDesc removed - no longer displayed
 
@@ -963,6 +1323,68 @@ func (l *LauncherApp) showSettingsDialog() {
 		}
 	})
 
+	// Edge Docking
+	dockEdges := []dock.Edge{dock.Top, dock.Bottom, dock.Left, dock.Right}
+	dockEdgeLabels := []string{language.T().TabPosTop, language.T().TabPosBottom, language.T().TabPosLeft, language.T().TabPosRight}
+	allowedEdges := dock.AllEdges
+	if len(l.Config.DockEdges) > 0 {
+		allowedEdges = dock.ParseEdges(l.Config.DockEdges)
+	}
+	dockEdgeRow := container.NewHBox()
+	dockEdgeChecks := make([]*widget.Check, len(dockEdges))
+	for i, e := range dockEdges {
+		dockEdgeChecks[i] = widget.NewCheck(dockEdgeLabels[i], func(checked bool) {})
+		dockEdgeChecks[i].SetChecked(allowedEdges.Has(e))
+		dockEdgeRow.Add(dockEdgeChecks[i])
+	}
+	dockAutoHideCheck := widget.NewCheck(language.T().SettingsDockAutoHide, func(checked bool) {})
+	dockAutoHideCheck.SetChecked(!l.Config.DockAutoHideDisabled)
+	newDockEntry := func(value, placeholder int) *widget.Entry {
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(strconv.Itoa(placeholder))
+		if value > 0 {
+			entry.SetText(strconv.Itoa(value))
+		}
+		return entry
+	}
+	dockHideDelayEntry := newDockEntry(l.Config.DockHideDelay, int(dock.DefaultHideDelay/time.Millisecond))
+	dockShowDelayEntry := newDockEntry(l.Config.DockShowDelay, 0)
+	dockRevealZoneEntry := newDockEntry(l.Config.DockRevealZone, dock.DefaultRevealZone)
+	dockRevealSelect := widget.NewSelect([]string{language.T().SettingsDockRevealHover, language.T().SettingsDockRevealHotkey}, func(selected string) {})
+	if l.Config.DockRevealHotkeyOnly {
+		dockRevealSelect.SetSelectedIndex(1)
+	} else {
+		dockRevealSelect.SetSelectedIndex(0)
+	}
+	dockOptions := []fyne.Disableable{dockAutoHideCheck, dockHideDelayEntry, dockShowDelayEntry, dockRevealZoneEntry, dockRevealSelect}
+	for _, check := range dockEdgeChecks {
+		dockOptions = append(dockOptions, check)
+	}
+	dockCheck := widget.NewCheck(language.T().SettingsDockEnable, func(checked bool) {
+		for _, option := range dockOptions {
+			if checked {
+				option.Enable()
+			} else {
+				option.Disable()
+			}
+		}
+	})
+	dockCheck.SetChecked(!l.Config.DockDisabled)
+	dockCheck.OnChanged(dockCheck.Checked)
+
+	// Global hotkeys: toggle, search and one per group
+	hotkeyToggleEntry := NewHotkeyEntry(l.Config.HotkeyToggle)
+	hotkeySearchEntry := NewHotkeyEntry(l.Config.HotkeySearch)
+	hotkeyForm := widget.NewForm(
+		widget.NewFormItem(language.T().SettingsHotkeyToggle, hotkeyToggleEntry),
+		widget.NewFormItem(language.T().SettingsHotkeySearch, hotkeySearchEntry),
+	)
+	groupHotkeyEntries := make([]*HotkeyEntry, len(l.Config.Groups))
+	for i, g := range l.Config.Groups {
+		groupHotkeyEntries[i] = NewHotkeyEntry(g.Hotkey)
+		hotkeyForm.Append(fmt.Sprintf(language.T().SettingsHotkeyGroup, g.Name), groupHotkeyEntries[i])
+	}
+
 	// Create dialog content with all settings
 	dialogContent := container.NewVBox(
 		widget.NewLabel(language.T().SettingsTheme),
@@ -980,7 +1402,43 @@ func (l *LauncherApp) showSettingsDialog() {
 		widget.NewSeparator(),
 		debugCheck,
 		autoStartCheck,
+		startHiddenCheck,
+		container.NewBorder(nil, nil, widget.NewLabel(language.T().SettingsStartDelay), nil, startDelayEntry),
+		urlHandlerCheck,
 		minimizeToTrayCheck,
+		container.NewBorder(nil, nil, widget.NewLabel(language.T().SettingsAlwaysOnTop), nil, topmostSelect),
+		widget.NewSeparator(),
+		widget.NewLabel(language.T().SettingsDockTitle),
+		dockCheck,
+		dockEdgeRow,
+		dockAutoHideCheck,
+		container.NewBorder(nil, nil, widget.NewLabel(language.T().SettingsDockHideDelay), nil, dockHideDelayEntry),
+		container.NewBorder(nil, nil, widget.NewLabel(language.T().SettingsDockShowDelay), nil, dockShowDelayEntry),
+		container.NewBorder(nil, nil, widget.NewLabel(language.T().SettingsDockRevealZone), nil, dockRevealZoneEntry),
+		container.NewBorder(nil, nil, widget.NewLabel(language.T().SettingsDockReveal), nil, dockRevealSelect),
+		widget.NewSeparator(),
+		widget.NewLabel(language.T().SettingsAPITitle),
+		apiCheck,
+		container.NewBorder(nil, nil, widget.NewLabel(language.T().SettingsAPIPort), apiCopyTokenBtn, apiPortEntry),
+		widget.NewSeparator(),
+		widget.NewLabel(language.T().SettingsHistoryTitle),
+		historyCheck,
+		sortByUsageCheck,
+		container.NewBorder(nil, nil, widget.NewLabel(language.T().SettingsHistoryRetention), nil, retentionSelect),
+		container.NewHBox(historyExportBtn, historyClearBtn),
+		widget.NewSeparator(),
+		widget.NewLabel(language.T().SettingsTerminal),
+		terminalSelect,
+		widget.NewButton(language.T().RoutesTitle, func() { l.showRoutesDialog() }),
+		widget.NewSeparator(),
+		widget.NewLabel(language.T().SettingsHotkeysTitle),
+		hotkeyForm,
+		widget.NewSeparator(),
+		widget.NewLabel(language.T().KindSearch),
+		container.NewHBox(
+			widget.NewButton(language.T().SettingsAddDefaultSearches, func() { l.addSearches(launcher.DefaultSearches()) }),
+			widget.NewButton(language.T().SettingsImportOpenSearch, func() { l.importOpenSearch() }),
+		),
 		widget.NewSeparator(),
 		widget.NewLabel(language.T().SettingsDataManagement),
 		container.NewHBox(
@@ -1102,7 +1560,7 @@ func (l *LauncherApp) showSettingsDialog() {
 			// Apply opacity immediately
 			go func() {
 				time.Sleep(100 * time.Millisecond)
-				hwnd := GetWindowHandle("Go MuseTool")
+				hwnd := l.mainWindowHandle()
 				if hwnd != 0 {
 					SetWindowOpacity(hwnd, newOpacity)
 				}
@@ -1122,9 +1580,29 @@ func (l *LauncherApp) showSettingsDialog() {
 		}
 
 		// Save Auto Start
+		newStartDelay := 0
+		if text := strings.TrimSpace(startDelayEntry.Text); text != "" {
+			delay, err := strconv.Atoi(text)
+			if err != nil || delay < 0 || delay > 3600 {
+				dialog.ShowError(fmt.Errorf(language.T().SettingsStartDelayInvalid, text), settingsWin)
+				return
+			}
+			newStartDelay = delay
+		}
+		if startHiddenCheck.Checked != l.Config.StartHidden || newStartDelay != l.Config.StartDelay {
+			l.Config.StartHidden = startHiddenCheck.Checked
+			l.Config.StartDelay = newStartDelay
+			changed = true
+			// 自启动项中的参数需要同步更新
+			if autoStartCheck.Checked && l.Config.AutoStart {
+				if err := l.SetAutoStart(true); err != nil {
+					log.Printf("Failed to update auto-start: %v", err)
+				}
+			}
+		}
 		if autoStartCheck.Checked != l.Config.AutoStart {
 			l.Config.AutoStart = autoStartCheck.Checked
-			if err := SetAutoStart(l.Config.AutoStart); err != nil {
+			if err := l.SetAutoStart(l.Config.AutoStart); err != nil {
 				log.Printf("Failed to set auto-start: %v", err)
 				dialog.ShowError(fmt.Errorf("failed to set auto-start: %w", err), settingsWin)
 			} else {
@@ -1132,12 +1610,166 @@ func (l *LauncherApp) showSettingsDialog() {
 			}
 		}
 
+		// Save URL Handler
+		if urlHandlerCheck.Checked != l.Config.URLHandler {
+			if err := setURLHandler(urlHandlerCheck.Checked); err != nil {
+				log.Printf("Failed to set URL handler: %v", err)
+				dialog.ShowError(fmt.Errorf("failed to register musetool:// links: %w", err), settingsWin)
+			} else {
+				l.Config.URLHandler = urlHandlerCheck.Checked
+				changed = true
+			}
+		}
+
 		// Save Minimize to Tray
 		if minimizeToTrayCheck.Checked != l.Config.MinimizeToTray {
 			l.Config.MinimizeToTray = minimizeToTrayCheck.Checked
 			changed = true
 		}
 
+		// Save Always on top
+		newTopmost := ""
+		if i := topmostSelect.SelectedIndex(); i > 0 {
+			newTopmost = topmostPolicies[i]
+		}
+		if newTopmost != l.Config.AlwaysOnTop {
+			l.Config.AlwaysOnTop = newTopmost
+			l.updateTopmost()
+			changed = true
+		}
+
+		// Save Edge Docking
+		dockValue := func(label string, entry *widget.Entry, min, max int) (int, bool) {
+			text := strings.TrimSpace(entry.Text)
+			if text == "" {
+				return 0, true
+			}
+			value, err := strconv.Atoi(text)
+			if err != nil || value < min || value > max {
+				dialog.ShowError(fmt.Errorf(language.T().SettingsDockValueInvalid, label, text), settingsWin)
+				return 0, false
+			}
+			return value, true
+		}
+		newHideDelay, ok := dockValue(language.T().SettingsDockHideDelay, dockHideDelayEntry, 0, 10000)
+		if !ok {
+			return
+		}
+		newShowDelay, ok := dockValue(language.T().SettingsDockShowDelay, dockShowDelayEntry, 0, 10000)
+		if !ok {
+			return
+		}
+		newRevealZone, ok := dockValue(language.T().SettingsDockRevealZone, dockRevealZoneEntry, 1, 200)
+		if !ok {
+			return
+		}
+		var newDockEdges []string
+		for i, e := range dockEdges {
+			if dockEdgeChecks[i].Checked {
+				newDockEdges = append(newDockEdges, e.String())
+			}
+		}
+		if dockCheck.Checked && len(newDockEdges) == 0 {
+			dialog.ShowError(errors.New(language.T().SettingsDockNoEdges), settingsWin)
+			return
+		}
+		if len(newDockEdges) == len(dockEdges) {
+			newDockEdges = nil // 全部边缘，与默认相同
+		}
+		newRevealHotkeyOnly := dockRevealSelect.SelectedIndex() == 1
+		if dockCheck.Checked == l.Config.DockDisabled ||
+			!slices.Equal(newDockEdges, l.Config.DockEdges) ||
+			dockAutoHideCheck.Checked == l.Config.DockAutoHideDisabled ||
+			newHideDelay != l.Config.DockHideDelay ||
+			newShowDelay != l.Config.DockShowDelay ||
+			newRevealZone != l.Config.DockRevealZone ||
+			newRevealHotkeyOnly != l.Config.DockRevealHotkeyOnly {
+			l.Config.DockDisabled = !dockCheck.Checked
+			l.Config.DockEdges = newDockEdges
+			l.Config.DockAutoHideDisabled = !dockAutoHideCheck.Checked
+			l.Config.DockHideDelay = newHideDelay
+			l.Config.DockShowDelay = newShowDelay
+			l.Config.DockRevealZone = newRevealZone
+			l.Config.DockRevealHotkeyOnly = newRevealHotkeyOnly
+			changed = true
+		}
+
+		// Save Local API
+		newAPIPort := 0
+		if text := strings.TrimSpace(apiPortEntry.Text); text != "" {
+			port, err := strconv.Atoi(text)
+			if err != nil || port < 1 || port > 65535 {
+				dialog.ShowError(fmt.Errorf(language.T().SettingsAPIPortInvalid, text), settingsWin)
+				return
+			}
+			newAPIPort = port
+		}
+		if apiCheck.Checked != l.Config.APIEnabled || newAPIPort != l.Config.APIPort {
+			l.Config.APIEnabled = apiCheck.Checked
+			l.Config.APIPort = newAPIPort
+			l.applyAPISettings()
+			changed = true
+		}
+
+		// Save Launch History
+		newRetention := l.Config.HistoryRetentionDays
+		if i := retentionSelect.SelectedIndex(); i >= 0 {
+			newRetention = retentionDays[i]
+		}
+		if historyCheck.Checked == l.Config.HistoryDisabled ||
+			sortByUsageCheck.Checked != l.Config.SortByUsage ||
+			newRetention != l.Config.HistoryRetentionDays {
+			l.Config.HistoryDisabled = !historyCheck.Checked
+			l.Config.SortByUsage = sortByUsageCheck.Checked
+			l.Config.HistoryRetentionDays = newRetention
+			l.pruneHistory()
+			changed = true
+		}
+
+		// Save Terminal
+		if i := terminalSelect.SelectedIndex(); i >= 0 && terminals[i] != l.Config.Terminal {
+			l.Config.Terminal = terminals[i]
+			launcher.SetTerminal(l.Config.Terminal)
+			changed = true
+		}
+
+		// Save Global Hotkeys
+		hotkeysChanged := false
+		setHotkey := func(name string, entry *HotkeyEntry, target *string) bool {
+			value := strings.TrimSpace(entry.Text)
+			if value != "" {
+				h, err := hotkey.Parse(value)
+				if err != nil {
+					dialog.ShowError(fmt.Errorf(language.T().HotkeyInvalid, name, err), settingsWin)
+					return false
+				}
+				value = h.String()
+			}
+			if value != *target {
+				*target = value
+				hotkeysChanged = true
+			}
+			return true
+		}
+		if !setHotkey(language.T().SettingsHotkeyToggle, hotkeyToggleEntry, &l.Config.HotkeyToggle) ||
+			!setHotkey(language.T().SettingsHotkeySearch, hotkeySearchEntry, &l.Config.HotkeySearch) {
+			return
+		}
+		for i, entry := range groupHotkeyEntries {
+			// 设置窗口打开期间分组可能被删除
+			if i >= len(l.Config.Groups) {
+				break
+			}
+			g := &l.Config.Groups[i]
+			if !setHotkey(fmt.Sprintf(language.T().SettingsHotkeyGroup, g.Name), entry, &g.Hotkey) {
+				return
+			}
+		}
+		if hotkeysChanged {
+			l.applyHotkeys(true)
+			changed = true
+		}
+
 		if changed {
 			if err := storage.SaveConfig(l.ConfigPath, l.Config); err != nil {
 				log.Printf("error saving config: %v", err)
@@ -1647,10 +2279,62 @@ func (l *LauncherApp) showShortcutDialog(editing *model.Shortcut) {
 	pathEntry.SetPlaceHolder(language.T().ShortcutPath)
 	iconEntry := widget.NewEntry()
 	iconEntry.SetPlaceHolder(language.T().ShortcutIcon)
+	argsEntry := widget.NewEntry()
+	argsEntry.SetPlaceHolder(language.T().ShortcutArgs)
+	workDirEntry := widget.NewEntry()
+	workDirEntry.SetPlaceHolder(language.T().ShortcutWorkDir)
+	commandEntry := widget.NewEntry()
+	commandEntry.SetPlaceHolder(language.T().ShortcutCommand)
+	textEntry := widget.NewMultiLineEntry()
+	textEntry.SetPlaceHolder(language.T().ShortcutSnippetText)
+	textEntry.SetMinRowsVisible(8)
+	textEntry.Wrapping = fyne.TextWrapWord
+	autoPasteCheck := widget.NewCheck(language.T().ShortcutAutoPaste, nil)
+	keywordEntry := widget.NewEntry()
+	keywordEntry.SetPlaceHolder(language.T().ShortcutKeyword)
+	searchHint := widget.NewLabel(language.T().ShortcutSearchHint)
+	searchHint.Wrapping = fyne.TextWrapWord
+	searchHint.TextStyle = fyne.TextStyle{Italic: true}
+	placeholderHint := widget.NewLabel(language.T().ShortcutPlaceholderHint)
+	placeholderHint.Wrapping = fyne.TextWrapWord
+	placeholderHint.TextStyle = fyne.TextStyle{Italic: true}
+	focusCheck := widget.NewCheck(language.T().ShortcutFocusExisting, nil)
+	keepOpenCheck := widget.NewCheck(language.T().ShortcutKeepOpen, nil)
+	terminalCheck := widget.NewCheck(language.T().ShortcutRunInTerminal, func(checked bool) {
+		if checked {
+			keepOpenCheck.Enable()
+		} else {
+			keepOpenCheck.SetChecked(false)
+			keepOpenCheck.Disable()
+		}
+	})
+	keepOpenCheck.Disable()
+	cronEntry := widget.NewEntry()
+	cronEntry.SetPlaceHolder(language.T().ShortcutScheduleCron)
+	atStartCheck := widget.NewCheck(language.T().ShortcutScheduleAtStart, nil)
+	catchUpCheck := widget.NewCheck(language.T().ShortcutScheduleCatchUp, nil)
+	hotkeyEntry := NewHotkeyEntry("")
+
+	// 类型选择：下拉框显示本地化名称，内部使用 model.Kind* 常量
+	kinds := []string{model.KindApplication, model.KindFile, model.KindFolder, model.KindURL, model.KindCommand, model.KindSnippet, model.KindMacro, model.KindSearch}
+	kindLabels := []string{
+		language.T().KindApplication,
+		language.T().KindFile,
+		language.T().KindFolder,
+		language.T().KindURL,
+		language.T().KindCommand,
+		language.T().KindSnippet,
+		language.T().KindMacro,
+		language.T().KindSearch,
+	}
+	selectedKind := model.KindApplication
+	kindChosen := false // 用户手动选择过类型后不再自动识别
+	kindSelect := widget.NewSelect(kindLabels, nil)
 
 	title := language.T().ShortcutAddTitle
 	btnText := language.T().ShortcutAdd
-	var originalName string
+	var originalName, originalID, originalHotkey string
+	var originalSteps []model.MacroStep
 	isEditing := false
 
 	if editing != nil {
@@ -1661,6 +2345,26 @@ func (l *LauncherApp) showShortcutDialog(editing *model.Shortcut) {
 		nameEntry.SetText(editing.Name)
 		pathEntry.SetText(editing.Path)
 		iconEntry.SetText(editing.IconPath)
+		argsEntry.SetText(editing.Args)
+		workDirEntry.SetText(editing.WorkDir)
+		commandEntry.SetText(editing.Command)
+		textEntry.SetText(editing.Text)
+		autoPasteCheck.SetChecked(editing.AutoPaste)
+		keywordEntry.SetText(editing.Keyword)
+		focusCheck.SetChecked(editing.FocusExisting)
+		terminalCheck.SetChecked(editing.RunInTerminal)
+		keepOpenCheck.SetChecked(editing.KeepOpen)
+		if editing.Schedule != nil {
+			cronEntry.SetText(editing.Schedule.Cron)
+			atStartCheck.SetChecked(editing.Schedule.AtStart)
+			catchUpCheck.SetChecked(editing.Schedule.CatchUp)
+		}
+		hotkeyEntry.SetText(hotkey.Normalize(editing.Hotkey))
+		originalID = editing.ID
+		originalHotkey = editing.Hotkey
+		originalSteps = editing.Steps
+		selectedKind = launcher.KindOf(*editing)
+		kindChosen = true
 	}
 
 	// Create independent window
@@ -1672,13 +2376,105 @@ func (l *LauncherApp) showShortcutDialog(editing *model.Shortcut) {
 	// Apply current theme (title bar color + TopMost)
 	l.applyWindowStyle(title)
 
-	browseBtn := widget.NewButton(language.T().ShortcutBrowse, func() {
+	browseBtn := widget.NewButton(language.T().ShortcutBrowse, nil)
+	pathRow := container.NewBorder(nil, nil, nil, browseBtn, pathEntry)
+	terminalRow := container.NewHBox(terminalCheck, keepOpenCheck)
+	stepsEditor := newMacroStepsEditor(originalSteps)
+	stepsContent := stepsEditor.Content()
+
+	// 按类型显示对应的输入项
+	updateFields := func() {
+		pathRow.Hide()
+		argsEntry.Hide()
+		workDirEntry.Hide()
+		commandEntry.Hide()
+		textEntry.Hide()
+		autoPasteCheck.Hide()
+		placeholderHint.Hide()
+		keywordEntry.Hide()
+		searchHint.Hide()
+		stepsContent.Hide()
+		focusCheck.Hide()
+		terminalRow.Hide()
+		switch selectedKind {
+		case model.KindApplication:
+			pathRow.Show()
+			argsEntry.Show()
+			workDirEntry.Show()
+			focusCheck.Show()
+			terminalRow.Show()
+		case model.KindCommand:
+			commandEntry.Show()
+			workDirEntry.Show()
+			terminalRow.Show()
+		case model.KindSnippet:
+			textEntry.Show()
+			autoPasteCheck.Show()
+			placeholderHint.Show()
+			// 多行文本需要更大的编辑区域
+			shortcutWin.Resize(fyne.NewSize(560, 480))
+		case model.KindMacro:
+			stepsContent.Show()
+			// 步骤行较宽，放大窗口
+			shortcutWin.Resize(fyne.NewSize(680, 420))
+		case model.KindSearch:
+			pathRow.Show()
+			keywordEntry.Show()
+			searchHint.Show()
+		default: // file, folder, url
+			pathRow.Show()
+		}
+		// 网址没有可浏览的文件
+		if selectedKind == model.KindURL || selectedKind == model.KindSearch {
+			browseBtn.Hide()
+		} else {
+			browseBtn.Show()
+		}
+	}
+	setKind := func(kind string) {
+		selectedKind = kind
+		for i, k := range kinds {
+			if k == kind {
+				kindSelect.SetSelectedIndex(i)
+			}
+		}
+		updateFields()
+	}
+	kindSelect.OnChanged = func(string) {
+		kind := kinds[kindSelect.SelectedIndex()]
+		if kind != selectedKind {
+			kindChosen = true
+			selectedKind = kind
+			updateFields()
+		}
+	}
+	// 输入路径时自动识别类型（网址 / 文件夹 / 程序 / 文件）
+	pathEntry.OnChanged = func(text string) {
+		if !kindChosen && text != "" {
+			setKind(launcher.DetectKind(text))
+		}
+	}
+	setKind(selectedKind)
+
+	browseBtn.OnTapped = func() {
+		if selectedKind == model.KindFolder {
+			dir, err := nativeDialog.Directory().Title(language.T().ShortcutBrowseFolder).Browse()
+			if err == nil && dir != "" {
+				pathEntry.SetText(dir)
+				if nameEntry.Text == "" {
+					nameEntry.SetText(filepath.Base(dir))
+				}
+			}
+			return
+		}
+
 		filename, err := nativeDialog.File().Title(language.T().ShortcutBrowseExe).
 			Filter("Executable/Shortcut Files", "exe", "lnk").
 			Filter("All Files", "*").
 			Load()
 		if err == nil && filename != "" {
 			pathEntry.SetText(filename)
+			setKind(launcher.DetectKind(filename))
 
 			// 直接调用同包下的函数
 			if nameEntry.Text == "" {
@@ -1691,24 +2487,83 @@ func (l *LauncherApp) showShortcutDialog(editing *model.Shortcut) {
 			if strings.HasSuffix(filePathLower, ".exe") {
 				iconPath = ExtractIconFromExe(filename)
 			} else if strings.HasSuffix(filePathLower, ".lnk") {
-				exePath := ResolveLnkTarget(filename)
+				exePath, _ := launcher.ResolveLink(filename)
 				if exePath != "" && strings.HasSuffix(strings.ToLower(exePath), ".exe") {
 					iconPath = ExtractIconFromExe(exePath)
 				}
 			}
 			iconEntry.SetText(iconPath)
 		}
-	})
+	}
 
 	saveBtn := widget.NewButton(btnText, func() {
 		name := nameEntry.Text
-		path := pathEntry.Text
-		icon := iconEntry.Text
-		if name == "" || path == "" {
-			log.Println("name or path is empty, cannot save shortcut")
+		newShortcut := model.Shortcut{Name: name, Kind: selectedKind, IconPath: iconEntry.Text}
+		// 只保存当前类型用到的字段
+		switch selectedKind {
+		case model.KindApplication:
+			newShortcut.Path = pathEntry.Text
+			newShortcut.Args = argsEntry.Text
+			newShortcut.WorkDir = workDirEntry.Text
+			newShortcut.FocusExisting = focusCheck.Checked
+			newShortcut.RunInTerminal = terminalCheck.Checked
+			newShortcut.KeepOpen = keepOpenCheck.Checked
+		case model.KindCommand:
+			newShortcut.Command = commandEntry.Text
+			newShortcut.WorkDir = workDirEntry.Text
+			newShortcut.RunInTerminal = terminalCheck.Checked
+			newShortcut.KeepOpen = keepOpenCheck.Checked
+		case model.KindSnippet:
+			newShortcut.Text = textEntry.Text
+			newShortcut.AutoPaste = autoPasteCheck.Checked
+		case model.KindSearch:
+			newShortcut.Path = pathEntry.Text
+			newShortcut.Keyword = strings.TrimSpace(keywordEntry.Text)
+			if err := launcher.Validate(newShortcut); errors.Is(err, launcher.ErrMissingQuery) {
+				dialog.ShowError(err, shortcutWin)
+				return
+			}
+			// 关键字必须唯一，否则搜索框无法确定用哪个搜索
+			if group, other, ok := storage.FindKeyword(l.Config, newShortcut.Keyword); ok &&
+				!(isEditing && group == l.CurrentGroup && other.Name == originalName) {
+				dialog.ShowError(fmt.Errorf(language.T().SearchKeywordTaken, newShortcut.Keyword, other.Name), shortcutWin)
+				return
+			}
+		case model.KindMacro:
+			newShortcut.Steps = stepsEditor.Steps()
+			if err := macro.Validate(newShortcut.Steps); err != nil {
+				dialog.ShowError(err, shortcutWin)
+				return
+			}
+		default:
+			newShortcut.Path = pathEntry.Text
+		}
+		if cron := strings.TrimSpace(cronEntry.Text); cron != "" || atStartCheck.Checked {
+			if cron != "" {
+				if _, err := schedule.ParseCron(cron); err != nil {
+					dialog.ShowError(err, shortcutWin)
+					return
+				}
+			}
+			newShortcut.Schedule = &model.Schedule{Cron: cron, AtStart: atStartCheck.Checked, CatchUp: catchUpCheck.Checked}
+		}
+		if text := strings.TrimSpace(hotkeyEntry.Text); text != "" {
+			h, err := hotkey.Parse(text)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf(language.T().HotkeyInvalid, name, err), shortcutWin)
+				return
+			}
+			// 不能与其它快捷方式、分组或应用热键重复
+			if owner, taken := l.hotkeyOwner(h, originalID); taken {
+				dialog.ShowError(fmt.Errorf(language.T().HotkeyTaken, h, owner), shortcutWin)
+				return
+			}
+			newShortcut.Hotkey = h.String()
+		}
+		if name == "" || launcher.Validate(newShortcut) != nil {
+			log.Printf("name or %s target is empty, cannot save shortcut", selectedKind)
 			return
 		}
-		newShortcut := model.Shortcut{Name: name, Path: path, IconPath: icon}
 		var err error
 
 		if isEditing {
@@ -1725,6 +2580,10 @@ func (l *LauncherApp) showShortcutDialog(editing *model.Shortcut) {
 			log.Printf("error saving config: %v", err)
 		}
 		log.Printf("shortcut saved successfully: %s", name)
+		if newShortcut.Hotkey != originalHotkey {
+			// 立即注册，被其它程序占用时提示用户
+			l.applyHotkeys(true)
+		}
 		l.setupUI()
 		l.ShortcutWindow = nil
 		shortcutWin.Close()
@@ -1748,9 +2607,25 @@ func (l *LauncherApp) showShortcutDialog(editing *model.Shortcut) {
 		nil,
 		container.NewVBox(
 			widget.NewLabel(title),
+			container.NewBorder(nil, nil, widget.NewLabel(language.T().ShortcutKind), nil, kindSelect),
 			nameEntry,
-			container.NewHBox(pathEntry, browseBtn),
+			pathRow,
+			commandEntry,
+			argsEntry,
+			workDirEntry,
+			focusCheck,
+			terminalRow,
+			textEntry,
+			autoPasteCheck,
+			placeholderHint,
+			keywordEntry,
+			searchHint,
+			stepsContent,
 			iconEntry,
+			widget.NewLabel(language.T().ShortcutSchedule),
+			cronEntry,
+			container.NewHBox(atStartCheck, catchUpCheck),
+			container.NewBorder(nil, nil, widget.NewLabel(language.T().ShortcutHotkey), nil, hotkeyEntry),
 		),
 	)
 
@@ -1896,45 +2771,11 @@ func (l *LauncherApp) moveShortcutToGroup(fromGroup, toGroup, shortcutName strin
 		return
 	}
 
-	var shortcutToMove *model.Shortcut
-	var fromGroupIndex int = -1
-	var toGroupIndex int = -1
-
-	// 查找源分组和目标分组
-	for i := range l.Config.Groups {
-		if l.Config.Groups[i].Name == fromGroup {
-			fromGroupIndex = i
-		}
-		if l.Config.Groups[i].Name == toGroup {
-			toGroupIndex = i
-		}
-	}
-
-	if fromGroupIndex == -1 || toGroupIndex == -1 {
-		log.Printf("source or target group not found")
-		return
-	}
-
-	// 从源分组中查找并删除快捷方式
-	for j, s := range l.Config.Groups[fromGroupIndex].Shortcuts {
-		if s.Name == shortcutName {
-			shortcutToMove = &s
-			l.Config.Groups[fromGroupIndex].Shortcuts = append(
-				l.Config.Groups[fromGroupIndex].Shortcuts[:j],
-				l.Config.Groups[fromGroupIndex].Shortcuts[j+1:]...,
-			)
-			break
-		}
-	}
-
-	if shortcutToMove == nil {
-		log.Printf("shortcut not found in source group")
+	if err := storage.MoveShortcut(l.Config, fromGroup, toGroup, shortcutName); err != nil {
+		log.Printf("error moving shortcut: %v", err)
 		return
 	}
 
-	// 添加到目标分组
-	l.Config.Groups[toGroupIndex].Shortcuts = append(l.Config.Groups[toGroupIndex].Shortcuts, *shortcutToMove)
-
 	// 保存配置
 	if err := storage.SaveConfig(l.ConfigPath, l.Config); err != nil {
 		log.Printf("error saving config: %v", err)
@@ -1946,27 +2787,56 @@ func (l *LauncherApp) moveShortcutToGroup(fromGroup, toGroup, shortcutName strin
 
 func (l *LauncherApp) Run() {
 	l.Window.SetOnDropped(l.Dropped)
-	log.Println("calling window.showandrun()...")
-	l.Window.ShowAndRun()
+	if l.StartHidden {
+		// 只显示托盘图标，从托盘或再次启动程序时显示主窗口
+		log.Println("starting hidden in the tray...")
+		l.restorePending = true
+		l.mainHidden = true
+		l.App.Run()
+	} else {
+		log.Println("showing window and running app...")
+		l.Window.Show()
+		l.mainWindowShown()
+		l.App.Run()
+	}
 	log.Println("window closed.")
+
+	if l.hotkeys != nil {
+		l.hotkeys.Close()
+	}
+	if l.ipcServer != nil {
+		l.ipcServer.Close()
+	}
+	if l.apiServer != nil {
+		l.apiServer.Close()
+	}
 }
 
 // Dropped handles the file drop event
 func (l *LauncherApp) Dropped(_ fyne.Position, uris []fyne.URI) {
-	addedCount := 0
+	paths := make([]string, 0, len(uris))
 	for _, uri := range uris {
-		filePath := uri.Path()
+		paths = append(paths, uri.Path())
+	}
+	l.addShortcutsFromPaths(paths)
+}
 
+// addShortcutsFromPaths adds each file as a shortcut to the current group and
+// returns how many were added
+func (l *LauncherApp) addShortcutsFromPaths(paths []string) int {
+	addedCount := 0
+	for _, filePath := range paths {
 		// 直接调用同包下的函数
 		name, _ := GetExecutableInfo(filePath)
 
+		kind := launcher.DetectKind(filePath)
 		iconPath := ""
 		filePathLower := strings.ToLower(filePath)
 
 		if strings.HasSuffix(filePathLower, ".exe") {
 			iconPath = ExtractIconFromExe(filePath)
 		} else if strings.HasSuffix(filePathLower, ".lnk") {
-			exePath := ResolveLnkTarget(filePath)
+			exePath, _ := launcher.ResolveLink(filePath)
 			if exePath != "" && strings.HasSuffix(strings.ToLower(exePath), ".exe") {
 				iconPath = ExtractIconFromExe(exePath)
 			}
@@ -1974,6 +2844,7 @@ func (l *LauncherApp) Dropped(_ fyne.Position, uris []fyne.URI) {
 
 		newShortcut := model.Shortcut{
 			Name:     name,
+			Kind:     kind,
 			Path:     filePath,
 			IconPath: iconPath,
 		}
@@ -1984,7 +2855,7 @@ func (l *LauncherApp) Dropped(_ fyne.Position, uris []fyne.URI) {
 			continue
 		}
 		addedCount++
-		log.Printf("shortcut added successfully via drag and drop: %s", name)
+		log.Printf("shortcut added successfully: %s", name)
 	}
 
 	if addedCount > 0 {
@@ -1994,6 +2865,7 @@ func (l *LauncherApp) Dropped(_ fyne.Position, uris []fyne.URI) {
 		}
 		l.setupUI()
 	}
+	return addedCount
 }
 
 // saveWindowState 保存窗口状态到配置文件
@@ -2004,7 +2876,7 @@ func (l *LauncherApp) saveWindowState() {
 		return
 	}
 
-	hwnd := GetWindowHandle(language.T().WindowTitle)
+	hwnd := l.mainWindowHandle()
 	if hwnd == 0 {
 		log.Println("failed to get window handle for saving state")
 		return
@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go-musetool/internal/history"
+	"go-musetool/internal/language"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 虚拟分组：由启动历史生成，显示在分组栏末尾
+const (
+	viewRecent   = "recent"
+	viewMostUsed = "most_used"
+
+	historyViewLimit = 24 // 虚拟分组最多显示的快捷方式数
+)
+
+// historyRetentionOptions 设置中可选的保留天数，0 表示永久
+var historyRetentionOptions = []int{0, 30, 90, 365}
+
+// openHistory 加载启动历史并按保留天数清理过期记录
+func (l *LauncherApp) openHistory() {
+	store, err := history.Open(history.Path(filepath.Dir(l.ConfigPath)))
+	if err != nil {
+		logger.Error("Failed to load launch history: %v", err)
+		return
+	}
+	l.History = store
+	l.pruneHistory()
+}
+
+func (l *LauncherApp) pruneHistory() {
+	if l.History == nil || l.Config.HistoryRetentionDays <= 0 {
+		return
+	}
+	before := time.Now().AddDate(0, 0, -l.Config.HistoryRetentionDays)
+	if err := l.History.Prune(before); err != nil {
+		logger.Error("Failed to prune launch history: %v", err)
+	}
+}
+
+// historyEnabled 是否记录并显示启动历史
+func (l *LauncherApp) historyEnabled() bool {
+	return l.History != nil && !l.Config.HistoryDisabled
+}
+
+// recordLaunch 记录一次启动及其结果
+func (l *LauncherApp) recordLaunch(shortcut model.Shortcut, launchErr error) {
+	if !l.historyEnabled() || shortcut.ID == "" {
+		return
+	}
+	if err := l.History.Record(history.NewEntry(shortcut.ID, shortcut.Name, time.Now(), launchErr)); err != nil {
+		logger.Error("Failed to record launch of %s: %v", shortcut.Name, err)
+	}
+	// 托盘的 "最近使用" 子菜单
+	l.refreshTray()
+}
+
+// shortcutTooltip 返回快捷方式的悬停提示：上次启动时间和次数
+func (l *LauncherApp) shortcutTooltip(shortcut model.Shortcut) string {
+	if !l.historyEnabled() {
+		return ""
+	}
+	st := l.History.Stats()[shortcut.ID]
+	if st.Launches == 0 {
+		return language.T().TooltipNeverLaunched
+	}
+	return fmt.Sprintf(language.T().TooltipLastLaunched, st.Last.Format("2006-01-02 15:04"), st.Launches)
+}
+
+// sortingByUsage 分组内是否按使用次数排序（此时不能拖动排序）
+func (l *LauncherApp) sortingByUsage() bool {
+	return l.Config.SortByUsage && l.historyEnabled()
+}
+
+// shortcutOrder 返回分组内快捷方式的显示顺序（下标）
+func (l *LauncherApp) shortcutOrder(shortcuts []model.Shortcut) []int {
+	order := make([]int, len(shortcuts))
+	for i := range order {
+		order[i] = i
+	}
+	if !l.sortingByUsage() {
+		return order
+	}
+	stats := l.History.Stats()
+	sort.SliceStable(order, func(a, b int) bool {
+		return stats[shortcuts[order[a]].ID].Launches > stats[shortcuts[order[b]].ID].Launches
+	})
+	return order
+}
+
+// historyTabButtons 创建"最近使用"和"最常使用"两个虚拟分组的标签按钮
+func (l *LauncherApp) historyTabButtons(switchView func(view string)) []fyne.CanvasObject {
+	if !l.historyEnabled() {
+		return nil
+	}
+	var buttons []fyne.CanvasObject
+	for _, v := range []struct{ view, title string }{
+		{viewRecent, language.T().TabRecent},
+		{viewMostUsed, language.T().TabMostUsed},
+	} {
+		view := v.view
+		btn := NewTabButton(v.title, nil, func() { switchView(view) }, nil, nil)
+		if l.CurrentView == view {
+			btn.Importance = widget.HighImportance
+		} else {
+			btn.Importance = widget.LowImportance
+		}
+		buttons = append(buttons, btn)
+	}
+	return buttons
+}
+
+// createHistoryContent 创建虚拟分组的内容，showGroup 用于跳转到快捷方式所在的分组
+func (l *LauncherApp) createHistoryContent(view string, showGroup func(groupName string)) fyne.CanvasObject {
+	var ids []string
+	if view == viewMostUsed {
+		ids = l.History.MostUsed(0)
+	} else {
+		ids = l.History.Recent(0)
+	}
+
+	var results []storage.SearchResult
+	for _, id := range ids {
+		// 已删除的快捷方式不再显示
+		groupName, shortcut, ok := storage.FindShortcutByID(l.Config, id)
+		if !ok {
+			continue
+		}
+		results = append(results, storage.SearchResult{Group: groupName, Shortcut: shortcut})
+		if len(results) == historyViewLimit {
+			break
+		}
+	}
+	return l.createShortcutList(results, language.T().HistoryEmpty, showGroup)
+}
+
+// exportHistoryCSV 把使用统计导出为 CSV
+func (l *LauncherApp) exportHistoryCSV(filename string) error {
+	if l.History == nil {
+		return fmt.Errorf("launch history is not available")
+	}
+	if !strings.HasSuffix(strings.ToLower(filename), ".csv") {
+		filename += ".csv"
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = history.WriteCSV(f, l.History.Stats(), func(id string) string {
+		groupName, _, _ := storage.FindShortcutByID(l.Config, id)
+		return groupName
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"strings"
+
+	"go-musetool/internal/hotkey"
+	"go-musetool/internal/language"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// captureKeyNames 与 hotkey 包写法不同的 Fyne 按键名
+var captureKeyNames = map[fyne.KeyName]string{
+	fyne.KeyReturn:   "Enter",
+	fyne.KeyEnter:    "Enter",
+	fyne.KeyPageUp:   "PageUp",
+	fyne.KeyPageDown: "PageDown",
+}
+
+// HotkeyEntry records a key combination instead of text: pressing Ctrl+Alt+T
+// while it has focus shows "Ctrl+Alt+T". Backspace or Delete clears it.
+type HotkeyEntry struct {
+	widget.Entry
+}
+
+// NewHotkeyEntry 创建热键输入框，value 为当前热键
+func NewHotkeyEntry(value string) *HotkeyEntry {
+	e := &HotkeyEntry{}
+	e.ExtendBaseWidget(e)
+	e.SetPlaceHolder(language.T().SettingsHotkeyHint)
+	e.SetText(hotkey.Normalize(value))
+	return e
+}
+
+// KeyDown captures the combination; modifier keys alone and keys that are
+// not a valid hotkey are ignored until a complete one is pressed
+func (e *HotkeyEntry) KeyDown(ev *fyne.KeyEvent) {
+	if e.Disabled() {
+		return
+	}
+	var mods fyne.KeyModifier
+	if d, ok := fyne.CurrentApp().Driver().(desktop.Driver); ok {
+		mods = d.CurrentKeyModifiers()
+	}
+	if mods == 0 && (ev.Name == fyne.KeyBackspace || ev.Name == fyne.KeyDelete) {
+		e.SetText("")
+		return
+	}
+
+	key, ok := captureKeyNames[ev.Name]
+	if !ok {
+		key = string(ev.Name)
+	}
+	var parts []string
+	for _, m := range []struct {
+		mod  fyne.KeyModifier
+		name string
+	}{{fyne.KeyModifierControl, "Ctrl"}, {fyne.KeyModifierAlt, "Alt"}, {fyne.KeyModifierShift, "Shift"}, {fyne.KeyModifierSuper, "Super"}} {
+		if mods&m.mod != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	if h, err := hotkey.Parse(strings.Join(append(parts, key), "+")); err == nil {
+		e.SetText(h.String())
+	}
+}
+
+// KeyUp 不需要处理
+func (e *HotkeyEntry) KeyUp(*fyne.KeyEvent) {}
+
+// TypedRune 忽略普通输入，内容只能通过按键组合设置
+func (e *HotkeyEntry) TypedRune(rune) {}
+
+// TypedKey 忽略编辑按键（已在 KeyDown 中处理）
+func (e *HotkeyEntry) TypedKey(*fyne.KeyEvent) {}
+
+// TypedShortcut 忽略复制、粘贴等，使 Ctrl+V 之类也能作为热键录入
+func (e *HotkeyEntry) TypedShortcut(fyne.Shortcut) {}
+
+// TappedSecondary 不显示编辑菜单（粘贴会绕过校验）
+func (e *HotkeyEntry) TappedSecondary(*fyne.PointEvent) {}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"go-musetool/internal/hotkey"
+	"go-musetool/internal/language"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// startHotkeys connects to the platform's hotkey backend and registers the
+// configured hotkeys. Without a backend (e.g. a Linux desktop without the
+// GlobalShortcuts portal) the hotkeys are reported as unsupported in settings.
+func (l *LauncherApp) startHotkeys() {
+	backend, err := hotkey.NewBackend()
+	if err != nil {
+		logger.Info("Global hotkeys are not available: %v", err)
+		l.hotkeyErr = err
+		return
+	}
+	l.hotkeys = hotkey.NewManager(backend)
+	l.applyHotkeys(false)
+}
+
+// hotkeyBindings 由配置生成全局热键，动作在 UI 线程执行。
+// 应用热键在前，冲突时保留先出现的；skipID 排除正在编辑的快捷方式
+func (l *LauncherApp) hotkeyBindings(skipID string) []hotkey.Binding {
+	t := language.T()
+	bindings := []hotkey.Binding{
+		{Name: t.SettingsHotkeyToggle, Hotkey: l.Config.HotkeyToggle, Action: func() { fyne.Do(l.toggleMainWindow) }},
+		{Name: t.SettingsHotkeySearch, Hotkey: l.Config.HotkeySearch, Action: func() { fyne.Do(l.focusSearch) }},
+	}
+	for _, g := range l.Config.Groups {
+		groupName := g.Name
+		bindings = append(bindings, hotkey.Binding{
+			Name:   fmt.Sprintf(t.SettingsHotkeyGroup, groupName),
+			Hotkey: g.Hotkey,
+			Action: func() { fyne.Do(func() { l.showGroup(groupName) }) },
+		})
+	}
+	for _, g := range l.Config.Groups {
+		for _, s := range g.Shortcuts {
+			if s.Hotkey == "" || (skipID != "" && s.ID == skipID) {
+				continue
+			}
+			id := s.ID
+			bindings = append(bindings, hotkey.Binding{
+				Name:   fmt.Sprintf(t.HotkeyShortcut, s.Name),
+				Hotkey: s.Hotkey,
+				Action: func() { fyne.Do(func() { l.launchHotkeyShortcut(id) }) },
+			})
+		}
+	}
+	return bindings
+}
+
+// hotkeyOwner returns the name of the binding that already uses h, ignoring
+// the shortcut with skipID (the one being edited)
+func (l *LauncherApp) hotkeyOwner(h hotkey.Hotkey, skipID string) (string, bool) {
+	for _, b := range l.hotkeyBindings(skipID) {
+		if other, err := hotkey.Parse(b.Hotkey); err == nil && other == h {
+			return b.Name, true
+		}
+	}
+	return "", false
+}
+
+// applyHotkeys registers the hotkeys in the background when they changed.
+// With report set, conflicts are shown to the user (after saving settings);
+// otherwise they are only logged.
+func (l *LauncherApp) applyHotkeys(report bool) {
+	bindings := l.hotkeyBindings("")
+	var sig strings.Builder
+	for _, b := range bindings {
+		fmt.Fprintf(&sig, "%s\x00%s\x00", b.Name, b.Hotkey)
+	}
+	if l.hotkeys == nil && l.hotkeyErr == nil {
+		return // startHotkeys 尚未运行
+	}
+	if sig.String() == l.hotkeySignature && !report {
+		return
+	}
+	l.hotkeySignature = sig.String()
+
+	if l.hotkeys == nil {
+		conflicts := hotkey.Check(bindings)
+		if l.hotkeyErr != nil {
+			for _, b := range bindings {
+				if strings.TrimSpace(b.Hotkey) != "" {
+					conflicts = append(conflicts, hotkey.Conflict{Name: b.Name, Hotkey: b.Hotkey, Err: l.hotkeyErr})
+				}
+			}
+		}
+		l.reportHotkeyConflicts(conflicts, report)
+		return
+	}
+	go func() {
+		conflicts := l.hotkeys.Set(bindings)
+		fyne.Do(func() { l.reportHotkeyConflicts(conflicts, report) })
+	}()
+}
+
+func (l *LauncherApp) reportHotkeyConflicts(conflicts []hotkey.Conflict, show bool) {
+	for _, c := range conflicts {
+		logger.Error("Hotkey not registered: %v", c)
+	}
+	if show && len(conflicts) > 0 {
+		l.showMainWindow()
+		dialog.ShowError(fmt.Errorf(language.T().HotkeyConflicts, hotkey.FormatConflicts(conflicts)), l.Window)
+	}
+}
+
+// toggleMainWindow 热键：隐藏可见的主窗口，否则（包括隐藏在屏幕边缘外时）显示并激活
+func (l *LauncherApp) toggleMainWindow() {
+	if l.mainHidden || (l.dockContainer != nil && l.dockContainer.Hidden()) {
+		l.showMainWindow()
+		return
+	}
+	l.hideMainWindow()
+}
+
+// focusSearch 热键：显示主窗口并聚焦搜索框
+func (l *LauncherApp) focusSearch() {
+	l.showMainWindow()
+	if l.searchEntry != nil {
+		l.Window.Canvas().Focus(l.searchEntry)
+	}
+}
+
+// launchHotkeyShortcut 热键：按 ID 查找快捷方式后启动，编辑过的快捷方式
+// 不需要重新注册热键
+func (l *LauncherApp) launchHotkeyShortcut(id string) {
+	_, shortcut, ok := storage.FindShortcutByID(l.Config, id)
+	if !ok {
+		return
+	}
+	if err := l.launchShortcut(shortcut); err != nil {
+		logger.Error("Failed to launch %s from hotkey: %v", shortcut.Name, err)
+		l.showMainWindow()
+		dialog.ShowError(err, l.Window)
+	}
+}
+
+// showGroup 热键：显示主窗口并切换到分组
+func (l *LauncherApp) showGroup(groupName string) {
+	if err := l.switchToGroup(groupName); err != nil {
+		logger.Error("Hotkey: %v", err)
+	}
+	l.showMainWindow()
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package ui
+
+// ExtractIconFromExe extracts the icon of a Windows executable. Linux has no
+// embedded executable icons, so shortcuts keep the default icon.
+func ExtractIconFromExe(exePath string) string {
+	return ""
+}
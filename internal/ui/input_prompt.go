@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"go-musetool/internal/language"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// inputHistoryLimit 每个占位符保留的历史记录条数
+const inputHistoryLimit = 10
+
+// showInputPrompt 为快捷方式的 {input:Name} 占位符收集输入，提交后启动
+func (l *LauncherApp) showInputPrompt(shortcut model.Shortcut, names []string) {
+	// 同一时间只保留一个输入窗口，新的启动请求替换旧的
+	if l.InputPromptWindow != nil {
+		l.InputPromptWindow.Close()
+	}
+
+	title := language.T().InputPromptTitle
+	win := l.App.NewWindow(title)
+	win.Resize(fyne.NewSize(380, 0))
+	win.CenterOnScreen()
+	win.SetIcon(nil)
+
+	entries := make([]*widget.SelectEntry, len(names))
+	form := widget.NewForm()
+	for i, name := range names {
+		history := l.Config.InputHistory[name]
+		entry := widget.NewSelectEntry(history)
+		if len(history) > 0 {
+			entry.SetText(history[0])
+		}
+		entries[i] = entry
+		form.Append(name, entry)
+	}
+
+	submit := func() {
+		inputs := make(map[string]string, len(names))
+		for i, name := range names {
+			inputs[name] = entries[i].Text
+		}
+		if err := l.launchWithInputs(shortcut, inputs); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		for _, name := range names {
+			l.rememberInput(name, inputs[name])
+		}
+		if err := storage.SaveConfig(l.ConfigPath, l.Config); err != nil {
+			logger.Error("error saving config: %v", err)
+		}
+		win.Close()
+	}
+
+	// 回车跳到下一个输入框，最后一个输入框回车即启动
+	for i := range entries {
+		next := i + 1
+		entries[i].OnSubmitted = func(string) {
+			if next < len(entries) {
+				win.Canvas().Focus(entries[next])
+				return
+			}
+			submit()
+		}
+	}
+
+	launchBtn := widget.NewButton(language.T().InputPromptLaunch, submit)
+	cancelBtn := widget.NewButton(language.T().Cancel, func() {
+		win.Close()
+	})
+
+	win.SetContent(container.NewVBox(
+		widget.NewLabel(shortcut.Name),
+		form,
+		container.NewHBox(layout.NewSpacer(), cancelBtn, launchBtn),
+	))
+
+	l.InputPromptWindow = win
+	win.SetOnClosed(func() {
+		if l.InputPromptWindow == win {
+			l.InputPromptWindow = nil
+		}
+	})
+	setupEscapeKeyCloseWithShortcut(win, func() {
+		win.Close()
+	})
+	win.Show()
+	l.applyWindowStyle(title)
+	win.Canvas().Focus(entries[0])
+}
+
+// rememberInput 把输入值放到该占位符历史的最前面，去重并限制条数
+func (l *LauncherApp) rememberInput(name, value string) {
+	if value == "" {
+		return
+	}
+	if l.Config.InputHistory == nil {
+		l.Config.InputHistory = map[string][]string{}
+	}
+	history := []string{value}
+	for _, v := range l.Config.InputHistory[name] {
+		if v != value && len(history) < inputHistoryLimit {
+			history = append(history, v)
+		}
+	}
+	l.Config.InputHistory[name] = history
+}
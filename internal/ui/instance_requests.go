@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+
+	"go-musetool/internal/ipc"
+	"go-musetool/internal/launcher"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+)
+
+// ListenForInstances 启动 IPC 服务，接收之后启动的实例转发过来的命令行意图。
+// startup 是本实例自身启动时的请求，在应用启动完成后执行。
+func (l *LauncherApp) ListenForInstances(startup ipc.Request) {
+	server, err := ipc.Listen(l.handleInstanceRequest)
+	if err != nil {
+		logger.Error("Failed to start IPC server: %v", err)
+	} else {
+		l.ipcServer = server
+		logger.Info("IPC server started")
+	}
+
+	if startup.Action != "" && startup.Action != ipc.ActionShow {
+		l.App.Lifecycle().SetOnStarted(func() {
+			go func() {
+				if err := l.handleInstanceRequest(startup); err != nil {
+					logger.Error("Failed to handle startup request %q: %v", startup.Action, err)
+				}
+			}()
+		})
+	}
+}
+
+// handleInstanceRequest 在 UI 线程上执行请求，并把结果返回给发送方
+func (l *LauncherApp) handleInstanceRequest(req ipc.Request) error {
+	logger.Info("Received instance request: %s %v", req.Action, req.Args)
+
+	var err error
+	fyne.DoAndWait(func() {
+		switch req.Action {
+		case ipc.ActionShow:
+			l.showMainWindow()
+
+		case ipc.ActionAdd:
+			if l.addShortcutsFromPaths(req.Args) == 0 {
+				err = fmt.Errorf("no shortcut was added to group %s", l.CurrentGroup)
+			}
+			l.showMainWindow()
+
+		case ipc.ActionLaunch:
+			_, shortcut, lookupErr := storage.LookupShortcut(l.Config, req.Args[0])
+			if lookupErr != nil {
+				err = fmt.Errorf("%w: %s", lookupErr, req.Args[0])
+				return
+			}
+			err = l.launchShortcut(shortcut)
+
+		case ipc.ActionWorkspace:
+			// 异步启动，避免阻塞 IPC 超时；失败汇总显示在主窗口
+			err = l.launchWorkspace(req.Args[0])
+
+		case ipc.ActionReload:
+			err = l.reloadConfig()
+
+		case ipc.ActionGroup:
+			if err = l.switchToGroup(req.Args[0]); err == nil {
+				l.showMainWindow()
+			}
+
+		default:
+			err = fmt.Errorf("%w: %q", ipc.ErrUnknownAction, req.Action)
+		}
+	})
+	return err
+}
+
+// reloadConfig 重新读取配置文件。命令行在程序运行时修改了配置，之后本实例保存时
+// 不能用内存中的旧配置覆盖这些修改
+func (l *LauncherApp) reloadConfig() error {
+	config, err := storage.LoadConfig(l.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+	l.Config = config
+	launcher.SetTerminal(config.Terminal)
+	launcher.SetRoutes(config.Routes)
+	l.setupUI()
+	return nil
+}
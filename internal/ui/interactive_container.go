@@ -1,36 +1,50 @@
 package ui
 
 import (
-	"go-musetool/internal/language"
+	"go-musetool/internal/dock"
+	"go-musetool/internal/model"
 	"log"
 	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/widget"
 )
 
-// InteractiveContainer wraps the content to handle window dragging and auto-hide events.
+// settleHideDelay 吸附到边缘后额外等待的时间，避免误触
+const settleHideDelay = 200 * time.Millisecond
+
+// InteractiveContainer wraps the content to handle window docking and auto-hide events.
+// The decisions are made by a dock.Machine; the container feeds it the window
+// geometry and moves the window. It lives as long as the main window, and
+// setupUI replaces its content.
+//
+// Nothing is polled: window moves arrive as platform events once Watch is
+// called, and the cursor is only followed while the window is hidden or has
+// been shown without the cursor entering the content.
 type InteractiveContainer struct {
 	widget.BaseWidget
-	content fyne.CanvasObject
+	content *fyne.Container // Stack，SetContent 替换其中的对象
 	window  fyne.Window
 
-	hideTimer *time.Timer
-	pollStop  chan struct{} // 用于停止轮询 Goroutine
-	mutex     sync.Mutex
-
-	dockSide         int    // 0: None, 1: Top, 2: Bottom, 3: Left, 4: Right
-	isHidden         bool   // 是否处于隐藏状态
-	lastX            int    // 上次窗口X位置
-	lastY            int    // 上次窗口Y位置
-	lastW            int    // 上次窗口宽度
-	lastH            int    // 上次窗口高度
-	saveStateFunc    func() // 保存状态的回调函数
-	saveTimer        *time.Timer
-	debugMode        bool
-	isHostFullscreen *bool // 宿主窗口是否处于全屏模式
+	// OnDockChanged 在窗口停靠到边缘或离开边缘时调用（在后台 goroutine 中）
+	OnDockChanged func()
+
+	hwnd       uintptr // 开始接收窗口移动事件之前为 0，停靠不起作用
+	watched    bool    // Watch 已调用过
+	hideTimer  *time.Timer
+	showTimer  *time.Timer // 鼠标进入热区后等待 ShowDelay
+	stopCursor func()      // 停止监听光标，没有监听时为 nil
+	mutex      sync.Mutex
+
+	dock          *dock.Machine
+	rect          dock.Rect // 最近一次隐藏或显示时的窗口位置和大小
+	workArea      dock.Rect
+	saveStateFunc func() // 保存状态的回调函数
+	saveTimer     *time.Timer
+	debugMode     bool
 }
 
 func (c *InteractiveContainer) SetDebugMode(enabled bool) {
@@ -39,146 +53,151 @@ func (c *InteractiveContainer) SetDebugMode(enabled bool) {
 	c.debugMode = enabled
 }
 
-const (
-	DockNone   = 0
-	DockTop    = 1
-	DockBottom = 2
-	DockLeft   = 3
-	DockRight  = 4
-
-	SnapDistance     = 80 // 吸附到边缘的距离 (增加以提高灵活性)
-	VisibleEdge      = 0  // 隐藏后可见的像素边缘 (0 表示完全隐藏)
-	VisualAdjustment = 8  // 视觉校正偏移量 (用于消除 Windows 窗口阴影带来的视觉间隙)
-
-	// BottomCenterZone 定义底部角落区域的大小（单位：像素）
-	// 用于区分底部的"角落区域"和"中间区域"，以解决任务栏预览窗口导致的闪烁问题
-	//
-	// 工作原理：
-	//   - 左下角：窗口左边缘距离屏幕左边缘 < BottomCenterZone
-	//   - 右下角：窗口右边缘距离屏幕右边缘 < BottomCenterZone
-	//   - 中间区域：不在左下角或右下角的底部区域
-	//
-	// 延迟策略：
-	//   - 角落区域：50ms 快速响应（任务栏图标稀疏，预览窗口少）
-	//   - 中间区域：300ms 延迟响应（任务栏图标密集，避免预览窗口干扰）
-	//
-	// 调整建议：
-	//   - 100-150：小角落，大中间区域（适合任务栏图标非常集中的情况）
-	//   - 200：默认值，平衡角落和中间区域
-	//   - 300-400：大角落，小中间区域（适合只有屏幕正中央需要延迟的情况）
-	//
-	// 示例（假设屏幕宽度1920px）：
-	//   BottomCenterZone = 200
-	//   - 左下角：0-200px
-	//   - 中间区域：200-1720px
-	//   - 右下角：1720-1920px
-	BottomCenterZone = 200
-)
-
 // NewInteractiveContainer creates a new container with the given window and content.
 func NewInteractiveContainer(w fyne.Window, content fyne.CanvasObject, saveFunc func()) *InteractiveContainer {
 	c := &InteractiveContainer{
 		window:        w,
-		content:       content,
-		lastX:         -1,
-		lastY:         -1,
-		lastW:         -1,
-		lastH:         -1,
+		content:       container.NewStack(content),
+		dock:          dock.NewMachine(dock.DefaultSettings()),
 		saveStateFunc: saveFunc,
 	}
 	c.ExtendBaseWidget(c)
-	// 启动位置检查定时器
-	c.startPositionCheck()
 	return c
 }
 
-// CreateRenderer implements the fyne.Widget interface.
-func (c *InteractiveContainer) CreateRenderer() fyne.WidgetRenderer {
-	return widget.NewSimpleRenderer(c.content)
-}
-
-// MouseIn implements the desktop.Hoverable interface.
-func (c *InteractiveContainer) MouseIn(e *desktop.MouseEvent) {
-	c.showWindow()
+// dockSettings 由配置生成停靠设置，未设置的项使用默认值
+func dockSettings(cfg *model.Config) dock.Settings {
+	s := dock.DefaultSettings()
+	if cfg.DockDisabled {
+		s.Edges = 0
+	} else if len(cfg.DockEdges) > 0 {
+		s.Edges = dock.ParseEdges(cfg.DockEdges)
+	}
+	s.AutoHide = !cfg.DockAutoHideDisabled
+	if cfg.DockHideDelay > 0 {
+		s.HideDelay = time.Duration(cfg.DockHideDelay) * time.Millisecond
+	}
+	s.ShowDelay = time.Duration(cfg.DockShowDelay) * time.Millisecond
+	if cfg.DockRevealZone > 0 {
+		s.RevealZone = cfg.DockRevealZone
+	}
+	s.RevealOnHover = !cfg.DockRevealHotkeyOnly
+	return s
 }
 
-// MouseOut implements the desktop.Hoverable interface.
-func (c *InteractiveContainer) MouseOut() {
-	// 如果窗口处于全屏模式，则不执行任何操作
-	if c.window.FullScreen() {
+// Watch starts following the moves of the native window hwnd. It is called
+// once the window exists; later calls do nothing. When the system cannot
+// report window moves, docking and auto-hide stay off for the whole session.
+func (c *InteractiveContainer) Watch(hwnd uintptr) {
+	c.mutex.Lock()
+	if hwnd == 0 || c.watched {
+		c.mutex.Unlock()
 		return
 	}
+	c.watched = true
+	c.mutex.Unlock()
 
-	// 增强全屏检测，通过Windows API检查窗口是否真正处于全屏状态
-	hwnd := GetWindowHandle(language.T().WindowTitle)
-	if hwnd != 0 {
-		// 检查窗口是否最大化
-		if IsWindowMaximized(hwnd) {
-			return
-		}
-
-		// 获取窗口和屏幕信息
-		wx, wy, ww, wh := GetWindowRect(hwnd)
-		screenW, screenH := GetScreenSize()
+	if _, ok := native.WatchWindow(hwnd, c.windowEvent); !ok {
+		log.Println("window move events are not available, edge docking and auto-hide are off")
+		return
+	}
+	c.mutex.Lock()
+	c.hwnd = hwnd
+	c.mutex.Unlock()
+	// 窗口可能一开始就在边缘
+	c.windowMoved()
+}
 
-		// 如果窗口尺寸接近全屏尺寸，则认为是全屏状态
-		if ww >= screenW && wh >= screenH {
-			return
-		}
+// SetContent replaces the wrapped content
+func (c *InteractiveContainer) SetContent(content fyne.CanvasObject) {
+	c.content.Objects = []fyne.CanvasObject{content}
+	c.content.Refresh()
+}
 
-		// 检查窗口位置是否覆盖了整个屏幕（考虑任务栏）
-		waLeft, waTop, waRight, waBottom := GetWorkArea()
-		workAreaWidth := waRight - waLeft
-		workAreaHeight := waBottom - waTop
+// SetSettings applies new dock settings. A hidden window is shown first so
+// that it does not stay off screen when its edge is no longer allowed.
+func (c *InteractiveContainer) SetSettings(settings dock.Settings) {
+	c.mutex.Lock()
+	if c.dock.Settings() == settings {
+		c.mutex.Unlock()
+		return
+	}
+	hidden := c.dock.Hidden()
+	c.mutex.Unlock()
 
-		if ww >= workAreaWidth && wh >= workAreaHeight && wx <= waLeft && wy <= waTop {
-			return
-		}
+	if hidden {
+		c.showWindow()
 	}
 
 	c.mutex.Lock()
-	dockSide := c.dockSide
+	c.stopTimersLocked()
+	stopCursor := c.detachCursorLocked()
+	before := c.dock.Edge()
+	c.dock.SetSettings(settings)
+	after := c.dock.Edge()
 	c.mutex.Unlock()
+	stopCursor()
 
-	// 只有当窗口已经吸附到边缘时，才在鼠标离开时隐藏窗口
-	if dockSide != DockNone {
-		go func() {
-			// 底部居中位置使用更长延迟，避免任务栏预览窗口干扰
-			// 左下角和右下角保持快速响应
-			delay := 50 * time.Millisecond
+	c.dockChanged(before, after)
+	// 按新设置重新判断停靠的边缘
+	c.windowMoved()
+}
 
-			if dockSide == DockBottom {
-				// 检查窗口是否在底部中间区域（非角落）
-				hwnd := GetWindowHandle(language.T().WindowTitle)
-				if hwnd != 0 {
-					x, _, w, _ := GetWindowRect(hwnd)
-					waLeft, _, waRight, _ := GetWorkArea()
+// Hidden reports whether the window is hidden behind a screen edge
+func (c *InteractiveContainer) Hidden() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.dock.Hidden()
+}
 
-					// 定义角落区域：距离左右边缘 BottomCenterZone 像素以内
-					isLeftCorner := x < waLeft+BottomCenterZone
-					isRightCorner := x+w > waRight-BottomCenterZone
+// Docked reports whether the window is docked to a screen edge
+func (c *InteractiveContainer) Docked() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.dock.Edge() != dock.None
+}
 
-					// 只有在中间区域才使用长延迟
-					if !isLeftCorner && !isRightCorner {
-						delay = 300 * time.Millisecond
-					}
-				}
-			}
+// Reveal brings the window back if it is hidden behind a screen edge. It
+// stays until the cursor has entered and left it.
+func (c *InteractiveContainer) Reveal() {
+	if !c.Hidden() {
+		return
+	}
+	c.showWindow()
+	c.mutex.Lock()
+	stopCursor := c.detachCursorLocked()
+	c.mutex.Unlock()
+	stopCursor()
+}
 
-			time.Sleep(delay)
+// CreateRenderer implements the fyne.Widget interface.
+func (c *InteractiveContainer) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(c.content)
+}
 
-			hwnd := GetWindowHandle(language.T().WindowTitle)
-			if hwnd == 0 {
-				return
-			}
+// MouseIn implements the desktop.Hoverable interface.
+func (c *InteractiveContainer) MouseIn(e *desktop.MouseEvent) {
+	c.showWindow()
+	// 鼠标在内容区内，离开时由 MouseOut 处理
+	c.mutex.Lock()
+	stopCursor := c.detachCursorLocked()
+	c.mutex.Unlock()
+	stopCursor()
+}
 
-			cx, cy := GetCursorPos()
-			wx, wy, ww, wh := GetWindowRect(hwnd)
+// MouseOut implements the desktop.Hoverable interface.
+func (c *InteractiveContainer) MouseOut() {
+	hwnd, rect, workArea := c.geometry()
+	// 如果窗口处于全屏模式，则不执行任何操作
+	if hwnd == 0 || c.isFullscreen(hwnd, rect, workArea) {
+		return
+	}
 
-			if cx < wx || cx > wx+ww || cy < wy || cy > wy+wh {
-				c.scheduleHide()
-			}
-		}()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// 只有当窗口已经吸附到边缘时，才在鼠标离开时隐藏窗口
+	if c.dock.AutoHides() {
+		c.scheduleHideLocked(c.dock.HideDelay(rect, workArea))
 	}
 }
 
@@ -204,554 +223,269 @@ func (c *InteractiveContainer) Dragged(e *fyne.DragEvent) {
 
 // DragEnd implements the fyne.Draggable interface.
 func (c *InteractiveContainer) DragEnd() {
-	// 由 checkPosition 定时器处理
+	// 由窗口移动事件处理
 }
 
-func (c *InteractiveContainer) showWindow() {
+// geometry returns the main window handle, its rect and the work area. hwnd
+// is 0 until window move events are followed, which keeps docking off.
+func (c *InteractiveContainer) geometry() (uintptr, dock.Rect, dock.Rect) {
 	c.mutex.Lock()
+	hwnd := c.hwnd
+	c.mutex.Unlock()
+	if hwnd == 0 {
+		return 0, dock.Rect{}, dock.Rect{}
+	}
+	x, y, w, h := GetWindowRect(hwnd)
+	return hwnd, dock.Rect{X: x, Y: y, W: w, H: h}, dock.WorkArea(GetWorkArea())
+}
 
-	if c.hideTimer != nil {
-		c.hideTimer.Stop()
-		c.hideTimer = nil
+// isFullscreen 全屏、最大化或铺满工作区的窗口不参与停靠和自动隐藏
+func (c *InteractiveContainer) isFullscreen(hwnd uintptr, rect, workArea dock.Rect) bool {
+	if c.window.FullScreen() || IsWindowMaximized(hwnd) {
+		return true
 	}
-	if c.pollStop != nil {
-		close(c.pollStop)
-		c.pollStop = nil
+	// 如果窗口尺寸接近全屏尺寸，则认为是全屏状态
+	screenW, screenH := GetScreenSize()
+	if rect.W >= screenW && rect.H >= screenH {
+		return true
 	}
+	// 检查窗口位置是否覆盖了整个屏幕（考虑任务栏）
+	return rect.W >= workArea.W && rect.H >= workArea.H && rect.X <= workArea.X && rect.Y <= workArea.Y
+}
 
-	c.isHidden = false
+// offScreen 窗口完全在工作区外（正在隐藏或显示）
+func offScreen(rect, workArea dock.Rect) bool {
+	return rect.Right() <= workArea.X || rect.X >= workArea.Right() ||
+		rect.Bottom() <= workArea.Y || rect.Y >= workArea.Bottom()
+}
 
-	hwnd := GetWindowHandle(language.T().WindowTitle)
-	if hwnd == 0 {
-		c.mutex.Unlock()
-		return
+// dockChanged calls OnDockChanged when the window was docked or undocked
+func (c *InteractiveContainer) dockChanged(before, after dock.Edge) {
+	if (before == dock.None) != (after == dock.None) && c.OnDockChanged != nil {
+		c.OnDockChanged()
 	}
+}
 
-	// 检查窗口是否处于全屏状态
-	if c.window.FullScreen() {
-		c.mutex.Unlock()
-		return
+func (c *InteractiveContainer) windowEvent(ev windowEvent) {
+	switch ev {
+	case windowMoved:
+		c.windowMoved()
+	case windowMoveEnded:
+		c.moveEnded()
 	}
+}
 
-	// 通过Windows API进一步检查窗口是否处于全屏状态
-	// 检查窗口是否最大化
-	if IsWindowMaximized(hwnd) {
-		c.mutex.Unlock()
+// windowMoved records a move or resize: the position is saved and the edge
+// the window is near is decided again. The window is snapped to it once the
+// move ends.
+func (c *InteractiveContainer) windowMoved() {
+	// System calls are slow, do not hold the lock
+	hwnd, rect, workArea := c.geometry()
+	if hwnd == 0 || c.isFullscreen(hwnd, rect, workArea) || offScreen(rect, workArea) {
 		return
 	}
 
-	// 获取窗口和屏幕信息
-	wx, wy, ww, wh := GetWindowRect(hwnd)
-	screenW, screenH := GetScreenSize()
-
-	// 如果窗口尺寸接近全屏尺寸，则认为是全屏状态
-	if ww >= screenW && wh >= screenH {
+	c.mutex.Lock()
+	// 隐藏时的移动是本容器自己做的
+	if c.dock.Hidden() {
 		c.mutex.Unlock()
 		return
 	}
+	c.scheduleSaveLocked()
+	oldEdge := c.dock.Edge()
+	edge := c.dock.Moved(rect, workArea)
+	stopCursor := func() {}
+	if edge != oldEdge {
+		c.stopTimersLocked()
+		stopCursor = c.detachCursorLocked()
+		if c.debugMode {
+			log.Printf("[DEBUG] windowMoved: dock edge %s -> %s", oldEdge, edge)
+		}
+	}
+	c.mutex.Unlock()
+	stopCursor()
 
-	// 检查窗口位置是否覆盖了整个屏幕（考虑任务栏）
-	waLeft, waTop, waRight, waBottom := GetWorkArea()
-	workAreaWidth := waRight - waLeft
-	workAreaHeight := waBottom - waTop
+	c.dockChanged(oldEdge, edge)
+}
 
-	if ww >= workAreaWidth && wh >= workAreaHeight && wx <= waLeft && wy <= waTop {
-		c.mutex.Unlock()
+// moveEnded snaps the window to its edge once the user lets go of it and
+// starts the auto-hide countdown
+func (c *InteractiveContainer) moveEnded() {
+	hwnd, rect, workArea := c.geometry()
+	if hwnd == 0 || c.isFullscreen(hwnd, rect, workArea) {
 		return
 	}
 
-	x, y, w, h := GetWindowRect(hwnd)
-
-	waLeft, waTop, waRight, waBottom = GetWorkArea()
-
-	// 根据 dockSide 恢复到正确的可见位置
-	// 左右两侧应用 VisualAdjustment 以消除视觉间隙
-	// Capture dockSide before releasing lock
-	dockSide := c.dockSide
-
-	// Release lock before system calls to avoid deadlock
+	c.mutex.Lock()
+	target, snap := c.dock.Settle(rect, workArea)
+	// 吸附后启动隐藏检查（鼠标仍在窗口上时不会隐藏）
+	if c.dock.AutoHides() {
+		c.scheduleHideLocked(settleHideDelay + c.dock.HideDelay(rect, workArea))
+	}
+	debug := c.debugMode
 	c.mutex.Unlock()
 
-	// 根据 dockSide 恢复到正确的可见位置
-	// 左右两侧应用 VisualAdjustment 以消除视觉间隙
-	switch dockSide {
-	case DockTop:
-		SetWindowPos(hwnd, x, waTop-VisualAdjustment)
-		log.Printf("showWindow: DockTop, moving to (%d, %d)", x, waTop-VisualAdjustment)
-	case DockBottom:
-		SetWindowPos(hwnd, x, waBottom-h+8)
-		log.Printf("showWindow: DockBottom, moving to (%d, %d)", x, waBottom-h+8)
-	case DockLeft:
-		SetWindowPos(hwnd, waLeft-VisualAdjustment, y)
-		log.Printf("showWindow: DockLeft, moving to (%d, %d)", waLeft-VisualAdjustment, y)
-	case DockRight:
-		SetWindowPos(hwnd, waRight-w+VisualAdjustment, y)
-		log.Printf("showWindow: DockRight, moving to (%d, %d)", waRight-w+VisualAdjustment, y)
-	default:
-		// 如果没有 dockSide，检查是否需要调整位置
-		if y < waTop {
-			SetWindowPos(hwnd, x, waTop)
-		} else if y > waBottom-h {
-			SetWindowPos(hwnd, x, waBottom-h)
-		} else if x < waLeft {
-			SetWindowPos(hwnd, waLeft-VisualAdjustment, y)
-		} else if x > waRight-w {
-			SetWindowPos(hwnd, waRight-w+VisualAdjustment, y)
+	if snap {
+		if debug {
+			log.Printf("[DEBUG] moveEnded: snapping to (%d, %d)", target.X, target.Y)
 		}
+		SetWindowPos(hwnd, target.X, target.Y)
 	}
-
 }
 
-func (c *InteractiveContainer) scheduleHide() {
+func (c *InteractiveContainer) showWindow() {
+	hwnd, rect, workArea := c.geometry()
+	skip := hwnd == 0 || c.isFullscreen(hwnd, rect, workArea)
+
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	c.scheduleHideLocked()
+	c.stopTimersLocked()
+	target, move := c.dock.Show(rect, workArea)
+	c.rect = dock.Rect{X: target.X, Y: target.Y, W: rect.W, H: rect.H}
+	c.workArea = workArea
+	edge := c.dock.Edge()
+	c.mutex.Unlock()
+
+	// 根据停靠的边缘恢复到正确的可见位置
+	if move && !skip {
+		SetWindowPos(hwnd, target.X, target.Y)
+		log.Printf("showWindow: dock %s, moving to (%d, %d)", edge, target.X, target.Y)
+	}
 }
 
-func (c *InteractiveContainer) scheduleHideLocked() {
+func (c *InteractiveContainer) scheduleHideLocked(delay time.Duration) {
 	if c.hideTimer != nil {
 		c.hideTimer.Stop()
 	}
-	c.hideTimer = time.AfterFunc(50*time.Millisecond, c.checkAndHide)
+	c.hideTimer = time.AfterFunc(delay, c.checkAndHide)
 }
 
-func (c *InteractiveContainer) checkAndHide() {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	hwnd := GetWindowHandle(language.T().WindowTitle)
-	if hwnd == 0 {
-		return
-	}
-
-	// 检查窗口是否处于全屏状态
-	if c.window.FullScreen() {
-		return
-	}
-
-	// 通过Windows API进一步检查窗口是否处于全屏状态
-	// 检查窗口是否最大化
-	if IsWindowMaximized(hwnd) {
-		return
+// stopTimersLocked 取消待执行的隐藏和显示
+func (c *InteractiveContainer) stopTimersLocked() {
+	if c.hideTimer != nil {
+		c.hideTimer.Stop()
+		c.hideTimer = nil
 	}
-
-	// 获取窗口和屏幕信息
-	wx, wy, ww, wh := GetWindowRect(hwnd)
-	screenW, screenH := GetScreenSize()
-
-	// 如果窗口尺寸接近全屏尺寸，则认为是全屏状态
-	if ww >= screenW && wh >= screenH {
-		return
+	if c.showTimer != nil {
+		c.showTimer.Stop()
+		c.showTimer = nil
 	}
+}
 
-	// 检查窗口位置是否覆盖了整个屏幕（考虑任务栏）
-	waLeft, waTop, waRight, waBottom := GetWorkArea()
-	workAreaWidth := waRight - waLeft
-	workAreaHeight := waBottom - waTop
-
-	if ww >= workAreaWidth && wh >= workAreaHeight && wx <= waLeft && wy <= waTop {
-		return
+// detachCursorLocked 取出停止监听光标的函数。停止可能阻塞（Windows 上要等事件线程），
+// 调用方解锁后再调用返回的函数
+func (c *InteractiveContainer) detachCursorLocked() (stop func()) {
+	stop, c.stopCursor = c.stopCursor, nil
+	if stop == nil {
+		return func() {}
 	}
+	return stop
+}
 
-	x, y, w, h := GetWindowRect(hwnd)
-	waLeft, waTop, waRight, waBottom = GetWorkArea()
-
-	if c.dockSide == DockNone {
-		// 优先检查左右边缘，这样高窗口会优先识别为左右停靠
-		if x <= waLeft {
-			c.dockSide = DockLeft
-		} else if x+w >= waRight {
-			c.dockSide = DockRight
-		} else if y <= waTop {
-			c.dockSide = DockTop
-		} else if y+h >= waBottom {
-			c.dockSide = DockBottom
-		}
+// watchCursor starts following the cursor unless it is already followed. It
+// reports false when the system cannot report cursor moves.
+func (c *InteractiveContainer) watchCursor() bool {
+	stop, ok := native.WatchCursor(c.cursorMoved)
+	if !ok {
+		return false
 	}
-
-	// 调试日志
-	// log.Printf("checkAndHide: pos=(%d,%d) size=(%dx%d) workArea=(%d,%d,%d,%d) dockSide=%d",
-	// 	x, y, w, h, waLeft, waTop, waRight, waBottom, c.dockSide)
-
-	hidden := false
-	// 隐藏偏移量：足够大以确保阴影也完全不可见
-	const hideOffset = 300
-
-	switch c.dockSide {
-	case DockTop:
-		c.mutex.Unlock()
-		SetWindowPos(hwnd, x, waTop-h-hideOffset)
-		c.mutex.Lock()
-		hidden = true
-	case DockBottom:
-		c.mutex.Unlock()
-		SetWindowPos(hwnd, x, waBottom+hideOffset)
-		c.mutex.Lock()
-		hidden = true
-	case DockLeft:
-		c.mutex.Unlock()
-		SetWindowPos(hwnd, waLeft-w-hideOffset, y)
-		c.mutex.Lock()
-		hidden = true
-	case DockRight:
-		c.mutex.Unlock()
-		SetWindowPos(hwnd, waRight+hideOffset, y)
-		c.mutex.Lock()
-		hidden = true
+	c.mutex.Lock()
+	if c.stopCursor == nil {
+		c.stopCursor, stop = stop, nil
 	}
-
-	if hidden {
-		c.isHidden = true
-		c.startPollingLocked()
+	c.mutex.Unlock()
+	if stop != nil {
+		stop() // 已经在监听
 	}
+	return true
 }
 
-func (c *InteractiveContainer) startPollingLocked() {
-	if c.pollStop != nil {
+func (c *InteractiveContainer) checkAndHide() {
+	hwnd, rect, workArea := c.geometry()
+	if hwnd == 0 || c.isFullscreen(hwnd, rect, workArea) {
 		return
 	}
-	c.pollStop = make(chan struct{})
-
-	go func() {
-		ticker := time.NewTicker(100 * time.Millisecond)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-c.pollStop:
-				return
-			case <-ticker.C:
-				cx, cy := GetCursorPos()
-				waLeft, waTop, waRight, waBottom := GetWorkArea()
-
-				c.mutex.Lock()
-				dockSide := c.dockSide
-				c.mutex.Unlock()
-
-				shouldShow := false
-				// 触发区域：鼠标移动到边缘 20px 范围内即可唤出 (增加灵敏度)
-				triggerZone := 1
-
-				// 获取窗口位置信息，用于严格的位置检查
-				hwnd := GetWindowHandle(language.T().WindowTitle)
-				if hwnd == 0 {
-					continue
-				}
-				_, wy, _, wh := GetWindowRect(hwnd)
-
-				// Y轴容差：允许鼠标在窗口上下各50px范围内触发
-				// 这样即使窗口隐藏了，鼠标在窗口原本位置附近也能触发
-				yTolerance := 50
-
-				switch dockSide {
-				case DockTop:
-					if cy <= waTop+triggerZone {
-						shouldShow = true
-					}
-				case DockBottom:
-					if cy >= waBottom-triggerZone {
-						shouldShow = true
-					}
-				case DockLeft:
-					// 严格检查：鼠标X坐标在左边缘 AND Y坐标在窗口范围内（含容差）
-					if cx <= waLeft+triggerZone {
-						// 检查鼠标Y坐标是否在窗口的垂直范围内
-						if cy >= wy-yTolerance && cy <= wy+wh+yTolerance {
-							shouldShow = true
-						}
-					}
-				case DockRight:
-					// 严格检查：鼠标X坐标在右边缘 AND Y坐标在窗口范围内（含容差）
-					if cx >= waRight-triggerZone {
-						// 检查鼠标Y坐标是否在窗口的垂直范围内
-						if cy >= wy-yTolerance && cy <= wy+wh+yTolerance {
-							shouldShow = true
-						}
-					}
-				}
-
-				if shouldShow {
-					// log.Printf("Showing window from polling: dockSide=%d cursor=(%d,%d)", dockSide, cx, cy)
-					c.showWindow()
-					return
-				}
-			}
-		}
-	}()
-}
-
-func (c *InteractiveContainer) startPositionCheck() {
-	go func() {
-		ticker := time.NewTicker(200 * time.Millisecond)
-		defer ticker.Stop()
+	cx, cy := GetCursorPos()
+	cursor := dock.Point{X: cx, Y: cy}
 
-		for range ticker.C {
-			c.checkPosition()
-		}
-	}()
-}
+	c.mutex.Lock()
+	c.hideTimer = nil
+	c.rect, c.workArea = rect, workArea
+	watching := c.stopCursor != nil
+	hover := c.dock.Settings().RevealOnHover
+	c.mutex.Unlock()
 
-func (c *InteractiveContainer) checkPosition() {
-	// 如果窗口处于全屏模式，则完全跳过所有位置检查和自动隐藏逻辑
-	if c.window.FullScreen() {
+	// 鼠标还在窗口上（例如在标题栏上）时先不隐藏，监听光标，离开窗口后再隐藏。
+	// 隐藏后靠光标事件在边缘显示；系统不支持时不隐藏，以免窗口无法找回
+	if !watching && (hover || rect.Contains(cursor)) && !c.watchCursor() && hover {
 		return
 	}
 
-	// 通过Windows API进一步检查窗口是否处于全屏状态
-	hwnd := GetWindowHandle(language.T().WindowTitle)
-	if hwnd != 0 {
-		// 检查窗口是否最大化
-		if IsWindowMaximized(hwnd) {
-			return
-		}
-
-		// 获取窗口和屏幕信息
-		wx, wy, ww, wh := GetWindowRect(hwnd)
-		screenW, screenH := GetScreenSize()
-
-		// 如果窗口尺寸接近全屏尺寸，则认为是全屏状态
-		if ww >= screenW && wh >= screenH {
-			return
-		}
-
-		// 检查窗口位置是否覆盖了整个屏幕（考虑任务栏）
-		waLeft, waTop, waRight, waBottom := GetWorkArea()
-		workAreaWidth := waRight - waLeft
-		workAreaHeight := waBottom - waTop
-
-		if ww >= workAreaWidth && wh >= workAreaHeight && wx <= waLeft && wy <= waTop {
-			return
-		}
-	}
-
-	// 1. Fast check for hidden state
 	c.mutex.Lock()
-	if c.isHidden {
-		c.mutex.Unlock()
-		return
+	target, hide := c.dock.Hide(rect, workArea, cursor)
+	stopCursor := func() {}
+	if hide && !hover {
+		stopCursor = c.detachCursorLocked()
 	}
+	debug := c.debugMode
 	c.mutex.Unlock()
+	stopCursor()
 
-	// 2. System calls (slow, do not hold lock)
-	if hwnd == 0 {
-		hwnd = GetWindowHandle(language.T().WindowTitle)
-		if hwnd == 0 {
-			return
+	if hide {
+		SetWindowPos(hwnd, target.X, target.Y)
+		if debug {
+			log.Printf("[DEBUG] checkAndHide: hiding to (%d, %d)", target.X, target.Y)
 		}
 	}
+}
 
-	x, y, w, h := GetWindowRect(hwnd)
-	waLeft, waTop, waRight, waBottom := GetWorkArea()
-
-	// 3. Update state (hold lock)
+// cursorMoved follows the cursor: a hidden window is shown once the cursor
+// has stayed in the hot zone for the show delay, a visible one is hidden
+// when the cursor leaves it
+func (c *InteractiveContainer) cursorMoved(x, y int) {
+	cursor := dock.Point{X: x, Y: y}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	// Re-check hidden state in case it changed
-	if c.isHidden {
-		return
-	}
-
-	posChanged := (x != c.lastX || y != c.lastY)
-	sizeChanged := (w != c.lastW || h != c.lastH)
-
-	// 检查窗口是否最大化
-	isMaximized := IsWindowMaximized(hwnd)
-
-	if !posChanged && !sizeChanged {
-		// 如果窗口最大化，跳过所有位置调整
-		if isMaximized {
-			return
-		}
-
-		if c.dockSide != DockNone {
-			shouldSnap := false
-			targetX, targetY := x, y
-
-			switch c.dockSide {
-			case DockLeft:
-				if x != waLeft-VisualAdjustment {
-					targetX = waLeft - VisualAdjustment
-					shouldSnap = true
-				}
-				// Corner Snap: Bottom-Left - 当接近底部时强制吸附
-				if y+h >= waBottom-SnapDistance {
-					if y != waBottom-h+8 {
-						targetY = waBottom - h + 8
-						shouldSnap = true
-					}
-				} else if y+h > waBottom+8 {
-					targetY = waBottom - h + 8
-					shouldSnap = true
-				}
-			case DockRight:
-				if x != waRight-w+VisualAdjustment && x < waRight {
-					targetX = waRight - w + VisualAdjustment
-					shouldSnap = true
-				}
-				// Corner Snap: Bottom-Right - 当接近底部时强制吸附
-				if y+h >= waBottom-SnapDistance {
-					if y != waBottom-h+8 {
-						targetY = waBottom - h + 8
-						shouldSnap = true
-					}
-				} else if y+h > waBottom+8 {
-					targetY = waBottom - h + 8
-					shouldSnap = true
-				}
-			case DockTop:
-				if y != waTop-VisualAdjustment {
-					targetY = waTop - VisualAdjustment
-					shouldSnap = true
-				}
-			case DockBottom:
-				if y != waBottom-h+8 {
-					targetY = waBottom - h + 8
-					shouldSnap = true
-				}
-			}
-
-			if shouldSnap {
-				if c.debugMode {
-					log.Println("[DEBUG] checkPosition: snapping")
-				}
-				c.mutex.Unlock() // Release lock before system call
-				SetWindowPos(hwnd, targetX, targetY)
-				c.mutex.Lock() // Re-acquire lock
-
-				c.lastX = targetX
-				c.lastY = targetY
-
-				// 吸附后立即启动隐藏检查
-				go func() {
-					time.Sleep(200 * time.Millisecond) // 增加延迟，避免误触
-					cx, cy := GetCursorPos()
-					// 再次获取窗口位置，确保准确
-					if wx, wy, ww, wh := GetWindowRect(hwnd); wx != 0 {
-						if cx < wx || cx > wx+ww || cy < wy || cy > wy+wh {
-							c.scheduleHideLocked()
-						}
-					}
-				}()
-			} else {
-				// Watchdog: 监控鼠标是否离开窗口
-				cx, cy := GetCursorPos()
-				if cx < x || cx > x+w || cy < y || cy > y+h {
-					if c.hideTimer == nil {
-						// 延迟隐藏，防止抖动
-						c.scheduleHideLocked()
-					}
-				}
-			}
-		} else {
-			// 即使没有吸附，也要限制窗口不能超出工作区
-			needsAdjustment := false
-			targetX, targetY := x, y
-
-			// 检查顶部边界（防止标题栏超出屏幕）
-			if y < waTop {
-				targetY = waTop
-				needsAdjustment = true
-				if c.debugMode {
-					log.Printf("[DEBUG] checkPosition: restricting top boundary. y=%d, waTop=%d", y, waTop)
-				}
-			}
-
-			// 检查底部边界（防止窗口超出任务栏上方）
-			if y+h > waBottom {
-				targetY = waBottom - h
-				needsAdjustment = true
-				if c.debugMode {
-					log.Printf("[DEBUG] checkPosition: restricting bottom boundary. y=%d, h=%d, waBottom=%d", y, h, waBottom)
-				}
-			}
-
-			// 检查左边界
-			if x < waLeft {
-				targetX = waLeft
-				needsAdjustment = true
-			}
-
-			// 检查右边界
-			if x+w > waRight {
-				targetX = waRight - w
-				needsAdjustment = true
-			}
-
-			if needsAdjustment {
-				c.mutex.Unlock()
-				SetWindowPos(hwnd, targetX, targetY)
-				c.mutex.Lock()
-				c.lastX = targetX
-				c.lastY = targetY
-			}
+	if !c.dock.Hidden() {
+		if c.dock.AutoHides() && c.hideTimer == nil && !c.rect.Contains(cursor) {
+			c.scheduleHideLocked(c.dock.HideDelay(c.rect, c.workArea))
 		}
 		return
 	}
-
-	c.lastX = x
-	c.lastY = y
-	c.lastW = w
-	c.lastH = h
-
-	if (posChanged || sizeChanged) && c.saveStateFunc != nil {
-		// Debounce save: wait for 1 second of inactivity before saving
-		if c.saveTimer != nil {
-			c.saveTimer.Stop()
-		}
-		c.saveTimer = time.AfterFunc(1*time.Second, func() {
-			c.saveStateFunc()
-		})
-	}
-
-	oldDockSide := c.dockSide
-	c.dockSide = DockNone
-
-	// 如果窗口最大化，跳过吸附检测（已在前面检测过）
-	if isMaximized {
-		// 窗口最大化时，清除 dockSide 并确保不会隐藏
-		if oldDockSide != DockNone {
-			c.isHidden = false
-			if c.hideTimer != nil {
-				c.hideTimer.Stop()
-				c.hideTimer = nil
-			}
-			if c.pollStop != nil {
-				close(c.pollStop)
-				c.pollStop = nil
-			}
+	if !c.dock.InRevealZone(c.rect, c.workArea, cursor) {
+		if c.showTimer != nil {
+			c.showTimer.Stop()
+			c.showTimer = nil
 		}
 		return
 	}
+	if c.showTimer == nil {
+		c.showTimer = time.AfterFunc(c.dock.Settings().ShowDelay, c.revealFromEdge)
+	}
+}
 
-	// 增加吸附距离，使其更容易触发
-	snapDist := 100
+// revealFromEdge shows the window if the cursor is still in the hot zone.
+// The cursor stays followed so that the window hides again if the cursor
+// leaves without entering the content.
+func (c *InteractiveContainer) revealFromEdge() {
+	cx, cy := GetCursorPos()
+	c.mutex.Lock()
+	c.showTimer = nil
+	inZone := c.dock.InRevealZone(c.rect, c.workArea, dock.Point{X: cx, Y: cy})
+	c.mutex.Unlock()
 
-	// 优先级：左右 > 上下
-	if x < waLeft+snapDist {
-		c.dockSide = DockLeft
-	} else if x+w > waRight-snapDist {
-		c.dockSide = DockRight
-	} else if y < waTop+snapDist {
-		c.dockSide = DockTop
-	} else if y+h > waBottom-snapDist {
-		c.dockSide = DockBottom
+	if inZone {
+		c.showWindow()
 	}
+}
 
-	if c.dockSide != oldDockSide && c.dockSide != DockNone {
-		if c.hideTimer != nil {
-			c.hideTimer.Stop()
-			c.hideTimer = nil
-		}
-	} else if c.dockSide == DockNone && oldDockSide != DockNone {
-		c.isHidden = false
-		if c.hideTimer != nil {
-			c.hideTimer.Stop()
-			c.hideTimer = nil
-		}
-		if c.pollStop != nil {
-			close(c.pollStop)
-			c.pollStop = nil
-		}
+// scheduleSaveLocked debounces saving: wait for 1 second of inactivity
+func (c *InteractiveContainer) scheduleSaveLocked() {
+	if c.saveStateFunc == nil {
+		return
+	}
+	if c.saveTimer != nil {
+		c.saveTimer.Stop()
 	}
+	c.saveTimer = time.AfterFunc(1*time.Second, c.saveStateFunc)
 }
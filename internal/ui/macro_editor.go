@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"strconv"
+
+	"go-musetool/internal/language"
+	"go-musetool/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// macroStepsEditor 编辑宏快捷方式的步骤列表，每行一个步骤
+type macroStepsEditor struct {
+	steps []model.MacroStep
+	rows  *fyne.Container
+}
+
+var (
+	macroStepTypes    = []string{model.StepLaunch, model.StepCommand, model.StepWait, model.StepWaitProcess, model.StepOpenURL}
+	macroFailPolicies = []string{model.OnFailureStop, model.OnFailureContinue}
+)
+
+func macroStepLabels() []string {
+	return []string{
+		language.T().MacroStepLaunch,
+		language.T().MacroStepCommand,
+		language.T().MacroStepWait,
+		language.T().MacroStepWaitProcess,
+		language.T().MacroStepOpenURL,
+	}
+}
+
+func macroPolicyLabels() []string {
+	return []string{language.T().MacroOnFailureStop, language.T().MacroOnFailureContinue}
+}
+
+// macroStepLabel 返回步骤类型的本地化名称
+func macroStepLabel(stepType string) string {
+	for i, t := range macroStepTypes {
+		if t == stepType {
+			return macroStepLabels()[i]
+		}
+	}
+	return stepType
+}
+
+func newMacroStepsEditor(steps []model.MacroStep) *macroStepsEditor {
+	e := &macroStepsEditor{
+		steps: append([]model.MacroStep{}, steps...),
+		rows:  container.NewVBox(),
+	}
+	e.refresh()
+	return e
+}
+
+// Content 返回步骤列表和"添加步骤"按钮
+func (e *macroStepsEditor) Content() fyne.CanvasObject {
+	addBtn := widget.NewButtonWithIcon(language.T().MacroAddStep, theme.ContentAddIcon(), func() {
+		e.steps = append(e.steps, model.MacroStep{Type: model.StepLaunch, OnFailure: model.OnFailureStop})
+		e.refresh()
+	})
+	return container.NewVBox(widget.NewLabel(language.T().MacroSteps), e.rows, addBtn)
+}
+
+// Steps 返回编辑后的步骤
+func (e *macroStepsEditor) Steps() []model.MacroStep {
+	return append([]model.MacroStep{}, e.steps...)
+}
+
+func (e *macroStepsEditor) refresh() {
+	e.rows.RemoveAll()
+	for i := range e.steps {
+		e.rows.Add(e.newRow(i))
+	}
+	e.rows.Refresh()
+}
+
+func (e *macroStepsEditor) newRow(index int) fyne.CanvasObject {
+	step := &e.steps[index]
+
+	targetEntry := widget.NewEntry()
+	targetEntry.SetText(step.Target)
+	targetEntry.OnChanged = func(text string) { step.Target = text }
+
+	secondsEntry := widget.NewEntry()
+	secondsEntry.SetPlaceHolder(language.T().MacroSeconds)
+	if step.Seconds > 0 {
+		secondsEntry.SetText(strconv.Itoa(step.Seconds))
+	}
+	secondsEntry.OnChanged = func(text string) {
+		// 无效输入按 0 处理，保存时由 macro.Validate 报错
+		step.Seconds, _ = strconv.Atoi(text)
+	}
+
+	// 按步骤类型调整输入框
+	updateInputs := func() {
+		targetEntry.Enable()
+		secondsEntry.Hide()
+		switch step.Type {
+		case model.StepLaunch:
+			targetEntry.SetPlaceHolder(language.T().MacroTargetLaunch)
+		case model.StepCommand:
+			targetEntry.SetPlaceHolder(language.T().ShortcutCommand)
+		case model.StepOpenURL:
+			targetEntry.SetPlaceHolder("https://")
+		case model.StepWaitProcess:
+			targetEntry.SetPlaceHolder(language.T().MacroTargetProcess)
+			secondsEntry.Show()
+		case model.StepWait:
+			targetEntry.SetPlaceHolder("")
+			targetEntry.SetText("")
+			targetEntry.Disable()
+			secondsEntry.Show()
+		}
+	}
+
+	typeSelect := widget.NewSelect(macroStepLabels(), nil)
+	for i, t := range macroStepTypes {
+		if t == step.Type {
+			typeSelect.SetSelectedIndex(i)
+		}
+	}
+	typeSelect.OnChanged = func(string) {
+		step.Type = macroStepTypes[typeSelect.SelectedIndex()]
+		updateInputs()
+	}
+	updateInputs()
+
+	policySelect := widget.NewSelect(macroPolicyLabels(), nil)
+	policySelect.SetSelectedIndex(0)
+	if step.OnFailure == model.OnFailureContinue {
+		policySelect.SetSelectedIndex(1)
+	}
+	policySelect.OnChanged = func(string) {
+		step.OnFailure = macroFailPolicies[policySelect.SelectedIndex()]
+	}
+
+	upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+		e.steps[index-1], e.steps[index] = e.steps[index], e.steps[index-1]
+		e.refresh()
+	})
+	if index == 0 {
+		upBtn.Disable()
+	}
+	deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		e.steps = append(e.steps[:index], e.steps[index+1:]...)
+		e.refresh()
+	})
+
+	return container.NewBorder(nil, nil, typeSelect,
+		container.NewHBox(secondsEntry, policySelect, upBtn, deleteBtn),
+		targetEntry)
+}
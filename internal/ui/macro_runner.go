@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-musetool/internal/language"
+	"go-musetool/internal/launcher"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/macro"
+	"go-musetool/internal/model"
+	"go-musetool/internal/process"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+var errNestedMacro = errors.New("a macro cannot launch another macro")
+
+// macroRunner 把宏步骤落到实际操作上，在后台 goroutine 中被调用
+type macroRunner struct {
+	l *LauncherApp
+}
+
+func (r macroRunner) LaunchShortcut(ref string) error {
+	var err error
+	fyne.DoAndWait(func() {
+		_, shortcut, lookupErr := storage.LookupShortcut(r.l.Config, ref)
+		if lookupErr != nil {
+			err = fmt.Errorf("%w: %s", lookupErr, ref)
+			return
+		}
+		if launcher.KindOf(shortcut) == model.KindMacro {
+			err = errNestedMacro
+			return
+		}
+		err = r.l.launchShortcut(shortcut)
+	})
+	return err
+}
+
+func (r macroRunner) RunCommand(command string) error {
+	return launcher.Launch(model.Shortcut{Kind: model.KindCommand, Command: command})
+}
+
+func (r macroRunner) OpenURL(url string) error {
+	return launcher.Open(url)
+}
+
+func (r macroRunner) ProcessRunning(name string) (bool, error) {
+	return process.IsRunning(name)
+}
+
+// runMacro 在后台执行宏，并显示带取消按钮的进度窗口（在 UI 线程调用）
+func (l *LauncherApp) runMacro(shortcut model.Shortcut) error {
+	if err := macro.Validate(shortcut.Steps); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	title := language.T().MacroRunningTitle
+
+	win := l.App.NewWindow(title)
+	win.Resize(fyne.NewSize(420, 220))
+	win.CenterOnScreen()
+	win.SetIcon(nil)
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.Wrapping = fyne.TextWrapWord
+	progressBar := widget.NewProgressBar()
+	progressBar.Max = float64(len(shortcut.Steps))
+	logLabel := widget.NewLabel("")
+	logLabel.Wrapping = fyne.TextWrapWord
+
+	finished := false
+	actionBtn := widget.NewButton(language.T().Cancel, nil)
+	actionBtn.OnTapped = func() {
+		if finished {
+			win.Close()
+			return
+		}
+		cancel()
+	}
+	// 关闭窗口等同于取消
+	win.SetOnClosed(cancel)
+
+	win.SetContent(container.NewBorder(
+		container.NewVBox(widget.NewLabel(shortcut.Name), statusLabel, progressBar),
+		container.NewHBox(layout.NewSpacer(), actionBtn),
+		nil, nil,
+		container.NewVScroll(logLabel),
+	))
+	win.Show()
+	l.applyWindowStyle(title)
+
+	var failures []string
+	onProgress := func(p macro.Progress) {
+		fyne.Do(func() {
+			stepText := fmt.Sprintf(language.T().MacroStepProgress, p.Index+1, p.Total, macroStepLabel(p.Step.Type))
+			if p.Step.Target != "" {
+				stepText += " " + p.Step.Target
+			}
+			if !p.Done {
+				statusLabel.SetText(stepText)
+				return
+			}
+			progressBar.SetValue(float64(p.Index + 1))
+			if p.Err != nil {
+				failures = append(failures, fmt.Sprintf("✗ %s: %v", stepText, p.Err))
+				logLabel.SetText(strings.Join(failures, "\n"))
+			}
+		})
+	}
+
+	go func() {
+		err := macro.Run(ctx, shortcut.Steps, macroRunner{l: l}, onProgress)
+		switch {
+		case err == nil:
+			logger.Info("Macro %s finished", shortcut.Name)
+		case ctx.Err() != nil:
+			logger.Info("Macro %s cancelled", shortcut.Name)
+		default:
+			logger.Error("Macro %s failed: %v", shortcut.Name, err)
+		}
+
+		fyne.Do(func() {
+			finished = true
+			actionBtn.SetText(language.T().Close)
+			switch {
+			case err == nil:
+				// 全部成功时自动关闭
+				win.Close()
+			case ctx.Err() != nil:
+				statusLabel.SetText(language.T().MacroCancelled)
+			default:
+				statusLabel.SetText(language.T().MacroFailed)
+			}
+		})
+	}()
+	return nil
+}
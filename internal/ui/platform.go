@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"log"
+
+	"go-musetool/internal/language"
+)
+
+// platform is what the UI needs from the desktop environment: native window
+// handles and geometry, screen metrics, theme detection and the
+// single-instance lock. Windows implements it with Win32 (*_windows.go); other
+// systems use X11/EWMH where available and fall back to no-ops (*_unix.go).
+//
+// Window handles are looked up by title and are opaque: an HWND on Windows,
+// an X11 window id elsewhere. 0 means the window was not found, and every
+// method accepts 0 without doing anything.
+//
+// The Watch methods replace polling: they call back when a window moves, the
+// active window changes or the cursor moves. Callbacks run on a background
+// goroutine and must return quickly. ok is false when the system cannot
+// report the event; stop is never nil and may be called more than once.
+type platform interface {
+	// 窗口
+	WindowHandle(title string) uintptr
+	WindowRect(hwnd uintptr) (x, y, w, h int)
+	MoveWindow(hwnd uintptr, x, y int)
+	MoveAndResizeWindow(hwnd uintptr, x, y, w, h int)
+	IsMaximized(hwnd uintptr) bool
+	SetOpacity(hwnd uintptr, opacity float64)
+	SetAlwaysOnTop(hwnd uintptr, alwaysOnTop bool)
+	SetNoTaskbar(hwnd uintptr)
+	SetTitleBarColor(hwnd uintptr, color uint32) // BGR (0xBBGGRR)
+
+	// 屏幕
+	ScreenSize() (w, h int)
+	WorkArea() (left, top, right, bottom int)
+	CursorPos() (x, y int)
+	IsDarkMode() bool
+
+	// 单实例
+	AcquireSingleInstance() bool
+	ReleaseSingleInstance()
+	ShowAlreadyRunning(title, message string)
+
+	// 事件
+	WatchWindow(hwnd uintptr, onEvent func(windowEvent)) (stop func(), ok bool)
+	WatchForeground(onChange func()) (stop func(), ok bool)
+	WatchCursor(onMove func(x, y int)) (stop func(), ok bool)
+}
+
+// windowEvent is a change of a watched window
+type windowEvent int
+
+const (
+	windowMoved     windowEvent = iota // 位置或大小改变，拖动时连续发生
+	windowMoveEnded                    // 拖动或调整大小结束
+)
+
+// native is the platform of the running system
+var native = newPlatform()
+
+func GetWindowHandle(title string) uintptr {
+	return native.WindowHandle(title)
+}
+
+func GetWindowRect(hwnd uintptr) (int, int, int, int) {
+	return native.WindowRect(hwnd)
+}
+
+func SetWindowPos(hwnd uintptr, x, y int) {
+	native.MoveWindow(hwnd, x, y)
+}
+
+func MoveAndResizeWindow(hwnd uintptr, x, y, w, h int) {
+	native.MoveAndResizeWindow(hwnd, x, y, w, h)
+}
+
+// IsWindowMaximized 检测窗口是否处于最大化状态
+func IsWindowMaximized(hwnd uintptr) bool {
+	return native.IsMaximized(hwnd)
+}
+
+// SetWindowOpacity sets the window opacity (0.0 = fully transparent, 1.0 = fully opaque)
+func SetWindowOpacity(hwnd uintptr, opacity float64) {
+	native.SetOpacity(hwnd, opacity)
+}
+
+// SetWindowAlwaysOnTop 设置窗口是否总在最前
+func SetWindowAlwaysOnTop(hwnd uintptr, alwaysOnTop bool) {
+	native.SetAlwaysOnTop(hwnd, alwaysOnTop)
+}
+
+// SetWindowNoTaskbar 设置窗口不在任务栏显示
+func SetWindowNoTaskbar(hwnd uintptr) {
+	native.SetNoTaskbar(hwnd)
+}
+
+// SetTitleBarColor 设置标题栏颜色，color 必须是 BGR (0xBBGGRR) 格式
+func SetTitleBarColor(hwnd uintptr, color uint32) {
+	native.SetTitleBarColor(hwnd, color)
+}
+
+func GetScreenSize() (int, int) {
+	return native.ScreenSize()
+}
+
+// GetWorkArea 获取不包含任务栏的屏幕工作区
+func GetWorkArea() (int, int, int, int) {
+	return native.WorkArea()
+}
+
+func GetCursorPos() (int, int) {
+	return native.CursorPos()
+}
+
+// IsSystemDarkMode 检测系统是否使用深色模式
+func IsSystemDarkMode() bool {
+	return native.IsDarkMode()
+}
+
+// CheckSingleInstance checks if another instance is already running
+// Returns true if this is the first instance, false if already running
+func CheckSingleInstance() bool {
+	return native.AcquireSingleInstance()
+}
+
+// ReleaseSingleInstance releases the lock when application exits
+func ReleaseSingleInstance() {
+	native.ReleaseSingleInstance()
+}
+
+// ShowAlreadyRunningDialog shows a dialog when instance is already running.
+// It runs before the UI loads a language, so it loads lang itself.
+func ShowAlreadyRunningDialog(lang string) {
+	if err := language.Load(lang); err != nil {
+		log.Printf("failed to load language: %v", err)
+	}
+	t := language.T()
+	native.ShowAlreadyRunning(t.WindowTitle, t.AlreadyRunning)
+}
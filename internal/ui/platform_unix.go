@@ -0,0 +1,214 @@
+//go:build !windows
+
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rymdport/portal/settings/appearance"
+	nativeDialog "github.com/sqweek/dialog"
+)
+
+// handleCacheTTL 按标题查找窗口需要启动 xdotool，短时间内重复查找时复用结果
+const handleCacheTTL = time.Second
+
+// unixPlatform implements platform for X11 desktops through the EWMH tools
+// xdotool, wmctrl and xprop. When a tool is missing, or there is no X
+// display (e.g. Wayland without XWayland), the calls do nothing and the
+// window manager keeps its default placement.
+type unixPlatform struct {
+	mu      sync.Mutex
+	handles map[string]cachedHandle
+	above   map[uintptr]bool // 已设置的置顶状态，避免重复调用 wmctrl
+	lock    *os.File         // 单实例锁文件
+}
+
+type cachedHandle struct {
+	id uintptr
+	at time.Time
+}
+
+func newPlatform() platform {
+	return &unixPlatform{handles: map[string]cachedHandle{}, above: map[uintptr]bool{}}
+}
+
+// x11 runs an X11 helper and returns its output, or "" when it fails
+func x11(tool string, args ...string) string {
+	if os.Getenv("DISPLAY") == "" {
+		return ""
+	}
+	out, err := exec.Command(tool, args...).Output()
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// shellValues parses the KEY=VALUE lines printed by xdotool --shell
+func shellValues(out string) map[string]int {
+	values := map[string]int{}
+	for _, line := range strings.Split(out, "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			if n, err := strconv.Atoi(v); err == nil {
+				values[k] = n
+			}
+		}
+	}
+	return values
+}
+
+func windowID(hwnd uintptr) string {
+	return strconv.FormatUint(uint64(hwnd), 10)
+}
+
+func (p *unixPlatform) WindowHandle(title string) uintptr {
+	p.mu.Lock()
+	cached, ok := p.handles[title]
+	p.mu.Unlock()
+	if ok && time.Since(cached.at) < handleCacheTTL {
+		return cached.id
+	}
+
+	out := x11("xdotool", "search", "--name", "^"+regexp.QuoteMeta(title)+"$")
+	first, _, _ := strings.Cut(strings.TrimSpace(out), "\n")
+	id, err := strconv.ParseUint(first, 10, 64)
+	if err != nil {
+		return 0
+	}
+	p.mu.Lock()
+	p.handles[title] = cachedHandle{id: uintptr(id), at: time.Now()}
+	p.mu.Unlock()
+	return uintptr(id)
+}
+
+func (p *unixPlatform) WindowRect(hwnd uintptr) (int, int, int, int) {
+	if hwnd == 0 {
+		return 0, 0, 0, 0
+	}
+	v := shellValues(x11("xdotool", "getwindowgeometry", "--shell", windowID(hwnd)))
+	return v["X"], v["Y"], v["WIDTH"], v["HEIGHT"]
+}
+
+func (p *unixPlatform) MoveWindow(hwnd uintptr, x, y int) {
+	if hwnd != 0 {
+		x11("xdotool", "windowmove", windowID(hwnd), strconv.Itoa(x), strconv.Itoa(y))
+	}
+}
+
+func (p *unixPlatform) MoveAndResizeWindow(hwnd uintptr, x, y, w, h int) {
+	if hwnd != 0 {
+		x11("xdotool", "windowsize", windowID(hwnd), strconv.Itoa(w), strconv.Itoa(h))
+		x11("xdotool", "windowmove", windowID(hwnd), strconv.Itoa(x), strconv.Itoa(y))
+	}
+}
+
+func (p *unixPlatform) IsMaximized(hwnd uintptr) bool {
+	if hwnd == 0 {
+		return false
+	}
+	state := x11("xprop", "-id", windowID(hwnd), "_NET_WM_STATE")
+	return strings.Contains(state, "_NET_WM_STATE_MAXIMIZED_VERT") &&
+		strings.Contains(state, "_NET_WM_STATE_MAXIMIZED_HORZ")
+}
+
+// SetOpacity 设置 _NET_WM_WINDOW_OPACITY，需要合成管理器才有效果
+func (p *unixPlatform) SetOpacity(hwnd uintptr, opacity float64) {
+	if hwnd == 0 {
+		return
+	}
+	if opacity >= 1.0 {
+		x11("xprop", "-id", windowID(hwnd), "-remove", "_NET_WM_WINDOW_OPACITY")
+		return
+	}
+	if opacity < 0.0 {
+		opacity = 0.0
+	}
+	value := uint32(opacity * 0xFFFFFFFF)
+	x11("xprop", "-id", windowID(hwnd), "-f", "_NET_WM_WINDOW_OPACITY", "32c",
+		"-set", "_NET_WM_WINDOW_OPACITY", strconv.FormatUint(uint64(value), 10))
+}
+
+// SetAlwaysOnTop 设置 _NET_WM_STATE_ABOVE；窗口管理器会保持该状态，所以只在变化时调用
+func (p *unixPlatform) SetAlwaysOnTop(hwnd uintptr, alwaysOnTop bool) {
+	if hwnd == 0 {
+		return
+	}
+	p.mu.Lock()
+	current, known := p.above[hwnd]
+	p.above[hwnd] = alwaysOnTop
+	p.mu.Unlock()
+	if known && current == alwaysOnTop {
+		return
+	}
+	action := "remove,above"
+	if alwaysOnTop {
+		action = "add,above"
+	}
+	x11("wmctrl", "-i", "-r", fmt.Sprintf("0x%x", hwnd), "-b", action)
+}
+
+func (p *unixPlatform) SetNoTaskbar(hwnd uintptr) {
+	if hwnd != 0 {
+		x11("wmctrl", "-i", "-r", fmt.Sprintf("0x%x", hwnd), "-b", "add,skip_taskbar")
+	}
+}
+
+// SetTitleBarColor 标题栏由窗口管理器绘制，无法设置颜色
+func (p *unixPlatform) SetTitleBarColor(hwnd uintptr, color uint32) {}
+
+func (p *unixPlatform) ScreenSize() (int, int) {
+	fields := strings.Fields(x11("xdotool", "getdisplaygeometry"))
+	if len(fields) != 2 {
+		return 0, 0
+	}
+	w, _ := strconv.Atoi(fields[0])
+	h, _ := strconv.Atoi(fields[1])
+	return w, h
+}
+
+// WorkArea 读取 _NET_WORKAREA（不包含面板），不支持时使用整个屏幕
+func (p *unixPlatform) WorkArea() (int, int, int, int) {
+	out := x11("xprop", "-root", "-notype", "_NET_WORKAREA")
+	if _, values, ok := strings.Cut(out, "="); ok {
+		var area [4]int
+		fields := strings.Split(values, ",")
+		if len(fields) >= 4 {
+			valid := true
+			for i := range area {
+				n, err := strconv.Atoi(strings.TrimSpace(fields[i]))
+				if err != nil {
+					valid = false
+					break
+				}
+				area[i] = n
+			}
+			if valid {
+				return area[0], area[1], area[0] + area[2], area[1] + area[3]
+			}
+		}
+	}
+	w, h := p.ScreenSize()
+	return 0, 0, w, h
+}
+
+func (p *unixPlatform) CursorPos() (int, int) {
+	v := shellValues(x11("xdotool", "getmouselocation", "--shell"))
+	return v["X"], v["Y"]
+}
+
+// IsDarkMode 通过 XDG 桌面门户读取系统配色方案，无法读取时视为浅色
+func (p *unixPlatform) IsDarkMode() bool {
+	scheme, err := appearance.GetColorScheme()
+	return err == nil && scheme == appearance.Dark
+}
+
+func (p *unixPlatform) ShowAlreadyRunning(title, message string) {
+	nativeDialog.Message("%s", message).Title(title).Info()
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"fmt"
+
+	"go-musetool/internal/language"
+	"go-musetool/internal/launcher"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showRoutesDialog 编辑打开规则：按协议、主机或扩展名把网址和文件交给指定命令
+func (l *LauncherApp) showRoutesDialog() {
+	if l.RoutesWindow != nil {
+		l.applyWindowStyle(language.T().RoutesTitle)
+		l.RoutesWindow.Show()
+		l.RoutesWindow.RequestFocus()
+		return
+	}
+
+	routes := append([]model.Route{}, l.Config.Routes...)
+
+	win := l.App.NewWindow(language.T().RoutesTitle)
+	win.Resize(fyne.NewSize(720, 420))
+	win.CenterOnScreen()
+	win.SetIcon(nil)
+	l.applyWindowStyle(language.T().RoutesTitle)
+
+	rows := container.NewVBox()
+	var refreshRows func()
+	refreshRows = func() {
+		rows.RemoveAll()
+		for i := range routes {
+			index := i
+			route := &routes[index]
+
+			schemeEntry := widget.NewEntry()
+			schemeEntry.SetPlaceHolder(language.T().RoutesScheme)
+			schemeEntry.SetText(route.Scheme)
+			schemeEntry.OnChanged = func(text string) { route.Scheme = text }
+			hostEntry := widget.NewEntry()
+			hostEntry.SetPlaceHolder(language.T().RoutesHost)
+			hostEntry.SetText(route.Host)
+			hostEntry.OnChanged = func(text string) { route.Host = text }
+			extEntry := widget.NewEntry()
+			extEntry.SetPlaceHolder(language.T().RoutesExt)
+			extEntry.SetText(route.Ext)
+			extEntry.OnChanged = func(text string) { route.Ext = text }
+			commandEntry := widget.NewEntry()
+			commandEntry.SetPlaceHolder(language.T().RoutesCommand)
+			commandEntry.SetText(route.Command)
+			commandEntry.OnChanged = func(text string) { route.Command = text }
+			terminalCheck := widget.NewCheck(language.T().RoutesTerminal, func(checked bool) { route.Terminal = checked })
+			terminalCheck.SetChecked(route.Terminal)
+
+			// 靠前的规则优先匹配
+			upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+				routes[index-1], routes[index] = routes[index], routes[index-1]
+				refreshRows()
+			})
+			if index == 0 {
+				upBtn.Disable()
+			}
+			deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				routes = append(routes[:index], routes[index+1:]...)
+				refreshRows()
+			})
+
+			criteria := container.NewGridWithColumns(3, schemeEntry, hostEntry, extEntry)
+			rows.Add(container.NewBorder(nil, nil, criteria,
+				container.NewHBox(terminalCheck, upBtn, deleteBtn),
+				commandEntry))
+		}
+		rows.Refresh()
+	}
+	refreshRows()
+
+	addBtn := widget.NewButtonWithIcon(language.T().RoutesAdd, theme.ContentAddIcon(), func() {
+		routes = append(routes, model.Route{})
+		refreshRows()
+	})
+
+	saveBtn := widget.NewButton(language.T().Save, func() {
+		for i, r := range routes {
+			if err := launcher.ValidateRoute(r); err != nil {
+				dialog.ShowError(fmt.Errorf(language.T().RoutesInvalid, i+1, err), win)
+				return
+			}
+		}
+		l.Config.Routes = routes
+		if len(routes) == 0 {
+			l.Config.Routes = nil
+		}
+		launcher.SetRoutes(l.Config.Routes)
+		if err := storage.SaveConfig(l.ConfigPath, l.Config); err != nil {
+			logger.Error("error saving config: %v", err)
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), win)
+			return
+		}
+		l.RoutesWindow = nil
+		win.Close()
+	})
+	cancelBtn := widget.NewButton(language.T().Cancel, func() {
+		l.RoutesWindow = nil
+		win.Close()
+	})
+
+	win.SetContent(container.NewBorder(
+		widget.NewLabel(language.T().RoutesHint),
+		container.NewHBox(addBtn, layout.NewSpacer(), cancelBtn, saveBtn),
+		nil, nil,
+		container.NewVScroll(rows),
+	))
+
+	l.RoutesWindow = win
+	win.SetOnClosed(func() {
+		l.RoutesWindow = nil
+	})
+	setupEscapeKeyCloseWithShortcut(win, func() {
+		win.Close()
+	})
+	win.Show()
+}
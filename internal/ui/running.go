@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"go-musetool/internal/launcher"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/model"
+	"go-musetool/internal/process"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	// runningScanInterval 发现在别处启动或关闭的程序的扫描间隔（见 process.Tracker）
+	runningScanInterval = 3 * time.Second
+	// launchRescanDelay 启动后等新进程出现在进程列表中再检查
+	launchRescanDelay = time.Second
+)
+
+// runningTile 是当前显示的、需要跟踪运行状态的快捷方式
+type runningTile struct {
+	path   string
+	widget *ShortcutWidget
+}
+
+// executablePath 返回程序快捷方式实际运行的可执行文件路径，无法跟踪时返回空串
+func (l *LauncherApp) executablePath(shortcut model.Shortcut) string {
+	// 需要输入的参数化路径在启动前无法确定
+	if launcher.KindOf(shortcut) != model.KindApplication || len(launcher.InputNames(shortcut)) > 0 {
+		return ""
+	}
+	resolved, err := launcher.Resolve(shortcut, launcher.Values{SelectedGroup: l.CurrentGroup})
+	if err != nil {
+		return ""
+	}
+	path := resolved.Path
+	if strings.HasSuffix(strings.ToLower(path), ".lnk") {
+		// 解析 .lnk 较慢，缓存结果
+		target, ok := l.lnkTargets[path]
+		if !ok {
+			var err error
+			if target, err = launcher.ResolveLink(path); err != nil {
+				logger.Debug("Failed to resolve shortcut: %v", err)
+			}
+			if l.lnkTargets == nil {
+				l.lnkTargets = map[string]string{}
+			}
+			l.lnkTargets[path] = target
+		}
+		path = target
+	}
+	return path
+}
+
+// trackRunning 登记一个快捷方式图标，定期刷新其运行状态（createGroupContent 等调用）
+func (l *LauncherApp) trackRunning(shortcut model.Shortcut, btn *ShortcutWidget) {
+	path := l.executablePath(shortcut)
+	if path == "" {
+		return
+	}
+	l.runningTiles = append(l.runningTiles, runningTile{path: path, widget: btn})
+	// 先用上一次的结果，避免重建界面后圆点闪烁
+	btn.SetRunning(l.runningTracker.Watch(path))
+}
+
+// startRunningTracker 跟踪程序的启动和退出，只在状态变化时更新图标上的运行标记
+func (l *LauncherApp) startRunningTracker() {
+	l.runningTracker = process.NewTracker(runningScanInterval, func(path string, running bool) {
+		fyne.Do(func() {
+			for _, t := range l.runningTiles {
+				if t.path == path {
+					t.widget.SetRunning(running)
+				}
+			}
+		})
+	})
+	l.runningTracker.Start()
+}
+
+// rescanRunning 在启动快捷方式后调用，新进程不必等到下一次定期扫描
+func (l *LauncherApp) rescanRunning() {
+	time.AfterFunc(launchRescanDelay, l.runningTracker.Rescan)
+}
+
+// focusPath 返回设置了"切换到现有窗口"的快捷方式要查找的程序路径，不切换时返回空串（在 UI 线程调用）
+func (l *LauncherApp) focusPath(shortcut model.Shortcut) string {
+	if !shortcut.FocusExisting {
+		return ""
+	}
+	return l.executablePath(shortcut)
+}
+
+// focusRunning 在 path 程序已运行时激活其窗口，返回是否已激活。可在任意 goroutine 调用
+func focusRunning(shortcut model.Shortcut, path string) bool {
+	focused, err := process.Focus(path)
+	if err != nil {
+		log.Printf("error focusing %s: %v", shortcut.Name, err)
+		return false
+	}
+	if focused {
+		log.Printf("focused running instance of %s", shortcut.Name)
+	}
+	return focused
+}
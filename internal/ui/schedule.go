@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+
+	"go-musetool/internal/logger"
+	"go-musetool/internal/schedule"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+)
+
+// startScheduler 加载快捷方式的定时设置，运行"启动时运行"的快捷方式并开始按 cron 检查
+func (l *LauncherApp) startScheduler() {
+	l.scheduler = schedule.New(func(job schedule.Job) {
+		fyne.Do(func() {
+			_, shortcut, ok := storage.FindShortcutByID(l.Config, job.ID)
+			if !ok {
+				return
+			}
+			log.Printf("running scheduled shortcut: %s", shortcut.Name)
+			l.launchShortcut(shortcut)
+		})
+	}, schedule.Options{
+		Idle:      schedule.IdleTime,
+		StatePath: schedule.StatePath(filepath.Dir(l.ConfigPath)),
+	})
+	l.reloadSchedule()
+	go l.scheduler.Run(context.Background())
+}
+
+// reloadSchedule 在配置变化后更新定时任务（setupUI 调用）
+func (l *LauncherApp) reloadSchedule() {
+	if l.scheduler == nil {
+		return
+	}
+	jobs, errs := schedule.Jobs(l.Config)
+	for _, err := range errs {
+		logger.Error("Skipping schedule of %v", err)
+	}
+	if err := l.scheduler.SetJobs(jobs); err != nil {
+		logger.Error("Failed to save schedule state: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"go-musetool/internal/language"
+	"go-musetool/internal/launcher"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	nativeDialog "github.com/sqweek/dialog"
+)
+
+// createSearchContent 显示所有分组中名称、路径、命令或文本片段内容匹配 query 的快捷方式。
+// 以网页搜索关键字开头时（"g golang"），第一个结果是用其余文字搜索的网页搜索
+func (l *LauncherApp) createSearchContent(query string, showGroup func(groupName string)) fyne.CanvasObject {
+	results := storage.SearchShortcuts(l.Config, query)
+	if groupName, shortcut, ok := l.keywordSearch(query); ok {
+		results = append([]storage.SearchResult{{Group: groupName, Shortcut: shortcut}}, results...)
+	}
+	return l.createShortcutList(results, language.T().SearchNoResults, showGroup)
+}
+
+// keywordSearch 识别 "关键字 查询词" 形式的输入，返回已填入查询词的网页搜索快捷方式
+func (l *LauncherApp) keywordSearch(input string) (string, model.Shortcut, bool) {
+	keyword, query := launcher.SplitKeywordQuery(input)
+	if query == "" {
+		return "", model.Shortcut{}, false
+	}
+	groupName, shortcut, ok := storage.FindKeyword(l.Config, keyword)
+	if !ok {
+		return "", model.Shortcut{}, false
+	}
+	shortcut.Path = launcher.ExpandSearch(shortcut.Path, query)
+	return groupName, shortcut, true
+}
+
+// submitSearch 在搜索框中按回车：执行关键字搜索，或启动唯一的搜索结果
+func (l *LauncherApp) submitSearch(input string) {
+	if _, shortcut, ok := l.keywordSearch(input); ok {
+		l.launchShortcut(shortcut)
+		return
+	}
+	if results := storage.SearchShortcuts(l.Config, input); len(results) == 1 {
+		l.launchShortcut(results[0].Shortcut)
+	}
+}
+
+// createShortcutList 创建跨分组的快捷方式列表（虚拟分组、搜索结果），右键可跳转到所在分组
+func (l *LauncherApp) createShortcutList(results []storage.SearchResult, empty string, showGroup func(groupName string)) fyne.CanvasObject {
+	l.runningTiles = nil
+	var items []fyne.CanvasObject
+	for _, r := range results {
+		groupName, shortcut := r.Group, r.Shortcut
+		btn := NewShortcutWidget(shortcut.Name, func() {
+			l.launchShortcut(shortcut)
+		}, func(e *fyne.PointEvent) {
+			menu := fyne.NewMenu("Shortcut",
+				fyne.NewMenuItem(language.T().ContextMenuShowInGroup, func() { showGroup(groupName) }),
+			)
+			widget.ShowPopUpMenuAtPosition(menu, l.Window.Canvas(), e.AbsolutePosition)
+		}, nil)
+		btn.Tooltip = func() string {
+			return groupName + "\n" + l.shortcutTooltip(shortcut)
+		}
+		l.trackRunning(shortcut, btn)
+		if shortcut.IconPath != "" {
+			if res, err := fyne.LoadResourceFromPath(shortcut.IconPath); err == nil {
+				btn.SetIcon(res)
+			} else {
+				log.Printf("failed to load icon: %v", err)
+			}
+		}
+		items = append(items, btn)
+	}
+
+	if len(items) == 0 {
+		return container.NewCenter(widget.NewLabel(empty))
+	}
+	grid := container.New(layout.NewGridWrapLayout(fyne.NewSize(90, 90)), items...)
+	return container.NewScroll(container.NewPadded(container.NewPadded(grid)))
+}
+
+// addSearches 把网页搜索添加到 "网页搜索" 分组，跳过关键字已被使用的搜索
+func (l *LauncherApp) addSearches(searches []model.Shortcut) {
+	groupName := language.T().SearchGroupName
+	if storage.FindGroup(l.Config, groupName) == nil {
+		if err := storage.AddGroup(l.Config, groupName); err != nil {
+			dialog.ShowError(err, l.Window)
+			return
+		}
+	}
+	added := 0
+	for _, s := range searches {
+		if _, _, ok := storage.FindKeyword(l.Config, s.Keyword); ok {
+			continue
+		}
+		if _, err := storage.FindShortcut(l.Config, groupName, s.Name); err == nil {
+			continue
+		}
+		if err := storage.AddShortcut(l.Config, groupName, s); err != nil {
+			logger.Error("Failed to add web search %s: %v", s.Name, err)
+			continue
+		}
+		added++
+	}
+	if added > 0 {
+		if err := storage.SaveConfig(l.ConfigPath, l.Config); err != nil {
+			logger.Error("error saving config: %v", err)
+		}
+		l.setupUI()
+	}
+	l.showToast(fmt.Sprintf(language.T().SearchesAdded, added))
+}
+
+// importOpenSearch 从 OpenSearch 描述文件（网站的 opensearch.xml）导入网页搜索
+func (l *LauncherApp) importOpenSearch() {
+	filename, err := nativeDialog.File().Title(language.T().SettingsImportOpenSearch).Filter("OpenSearch", "xml").Load()
+	if err != nil || filename == "" {
+		return
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		dialog.ShowError(err, l.Window)
+		return
+	}
+	defer f.Close()
+	search, err := launcher.ParseOpenSearch(f)
+	if err != nil {
+		logger.Error("Failed to import OpenSearch description %s: %v", filename, err)
+		dialog.ShowError(err, l.Window)
+		return
+	}
+	l.addSearches([]model.Shortcut{search})
+}
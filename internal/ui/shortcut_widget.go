@@ -1,24 +1,40 @@
 package ui
 
 import (
+	"time"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
+// tooltipDelay 鼠标停留多久后显示提示
+const tooltipDelay = 600 * time.Millisecond
+
 // ShortcutWidget is a custom widget that displays an icon above text (Windows 11 style)
 type ShortcutWidget struct {
 	widget.BaseWidget
 	icon         *canvas.Image
 	label        *widget.Label
+	runningDot   *canvas.Circle
+	running      bool
 	OnTapped     func()
 	OnRightClick func(*fyne.PointEvent)
 	onDragEnd    func(startPos, endPos fyne.Position)
 	dragStartPos fyne.Position
 	dragEndPos   fyne.Position
 	isDragging   bool
+
+	// Tooltip 返回悬停提示文本，每次显示时调用以保证内容最新；为 nil 或返回空串时不显示
+	Tooltip      func() string
+	tooltip      *widget.PopUp
+	tooltipTimer *time.Timer
+	tooltipPos   fyne.Position
+	hovered      bool
 }
 
 // NewShortcutWidget creates a new vertical shortcut widget (Windows 11 style)
@@ -37,6 +53,10 @@ func NewShortcutWidget(labelText string, onLeft func(), onRight func(*fyne.Point
 	s.label.Alignment = fyne.TextAlignCenter
 	s.label.Wrapping = fyne.TextWrapBreak
 
+	// 程序运行中时在右上角显示一个圆点
+	s.runningDot = canvas.NewCircle(theme.Color(theme.ColorNameSuccess))
+	s.runningDot.Hide()
+
 	s.ExtendBaseWidget(s)
 	return s
 }
@@ -53,11 +73,29 @@ func (s *ShortcutWidget) CreateRenderer() fyne.WidgetRenderer {
 		container.NewCenter(s.icon),
 		s.label,
 	)
-	return widget.NewSimpleRenderer(container.NewStack(content))
+	dot := container.NewVBox(container.NewHBox(
+		layout.NewSpacer(),
+		container.NewGridWrap(fyne.NewSize(8, 8), s.runningDot),
+	))
+	return widget.NewSimpleRenderer(container.NewStack(content, dot))
+}
+
+// SetRunning shows or hides the running indicator
+func (s *ShortcutWidget) SetRunning(running bool) {
+	if s.running == running {
+		return
+	}
+	s.running = running
+	if running {
+		s.runningDot.Show()
+	} else {
+		s.runningDot.Hide()
+	}
 }
 
 // Tapped handles left click
 func (s *ShortcutWidget) Tapped(_ *fyne.PointEvent) {
+	s.hideTooltip()
 	if s.OnTapped != nil {
 		s.OnTapped()
 	}
@@ -65,6 +103,7 @@ func (s *ShortcutWidget) Tapped(_ *fyne.PointEvent) {
 
 // TappedSecondary handles right click
 func (s *ShortcutWidget) TappedSecondary(e *fyne.PointEvent) {
+	s.hideTooltip()
 	if s.OnRightClick != nil {
 		s.OnRightClick(e)
 	}
@@ -72,6 +111,7 @@ func (s *ShortcutWidget) TappedSecondary(e *fyne.PointEvent) {
 
 // Dragged 实现 fyne.Draggable 接口以支持拖拽
 func (s *ShortcutWidget) Dragged(e *fyne.DragEvent) {
+	s.hideTooltip()
 	if !s.isDragging {
 		s.isDragging = true
 		s.dragStartPos = e.Position
@@ -87,3 +127,52 @@ func (s *ShortcutWidget) DragEnd() {
 	}
 	s.isDragging = false
 }
+
+// MouseIn 实现 desktop.Hoverable，停留一段时间后显示提示
+func (s *ShortcutWidget) MouseIn(e *desktop.MouseEvent) {
+	s.hideTooltip()
+	s.hovered = true
+	s.tooltipPos = e.AbsolutePosition
+	if s.Tooltip == nil {
+		return
+	}
+	s.tooltipTimer = time.AfterFunc(tooltipDelay, func() {
+		fyne.Do(s.showTooltip)
+	})
+}
+
+// MouseMoved 记录鼠标位置，提示显示在鼠标附近
+func (s *ShortcutWidget) MouseMoved(e *desktop.MouseEvent) {
+	s.tooltipPos = e.AbsolutePosition
+}
+
+// MouseOut 隐藏提示
+func (s *ShortcutWidget) MouseOut() {
+	s.hovered = false
+	s.hideTooltip()
+}
+
+func (s *ShortcutWidget) showTooltip() {
+	// 计时器触发前鼠标可能已经离开或开始拖拽
+	if !s.hovered || s.isDragging || s.tooltip != nil {
+		return
+	}
+	text := s.Tooltip()
+	c := fyne.CurrentApp().Driver().CanvasForObject(s)
+	if text == "" || c == nil {
+		return
+	}
+	s.tooltip = widget.NewPopUp(widget.NewLabel(text), c)
+	s.tooltip.ShowAtPosition(s.tooltipPos.Add(fyne.NewPos(12, 16)))
+}
+
+func (s *ShortcutWidget) hideTooltip() {
+	if s.tooltipTimer != nil {
+		s.tooltipTimer.Stop()
+		s.tooltipTimer = nil
+	}
+	if s.tooltip != nil {
+		s.tooltip.Hide()
+		s.tooltip = nil
+	}
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+
+package ui
+
+import (
+	"errors"
+
+	"go-musetool/internal/instance"
+	"go-musetool/internal/logger"
+)
+
+// AcquireSingleInstance takes an exclusive flock on the lock file.
+// Returns true if this is the first instance, false if already running
+func (p *unixPlatform) AcquireSingleInstance() bool {
+	f, err := instance.Acquire(instance.LockPath())
+	if errors.Is(err, instance.ErrLocked) {
+		return false
+	}
+	if err != nil {
+		logger.Error("Failed to acquire single-instance lock: %v", err)
+		return true // If error, allow to continue
+	}
+	p.mu.Lock()
+	p.lock = f
+	p.mu.Unlock()
+	return true
+}
+
+// ReleaseSingleInstance releases the lock when application exits
+func (p *unixPlatform) ReleaseSingleInstance() {
+	p.mu.Lock()
+	f := p.lock
+	p.lock = nil
+	p.mu.Unlock()
+	if f != nil {
+		instance.Release(f)
+	}
+}
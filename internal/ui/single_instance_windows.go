@@ -1,13 +1,15 @@
 package ui
 
 import (
-	"go-musetool/internal/language"
 	"syscall"
 	"unsafe"
 )
 
+// mutexName lives in the session-local namespace, so each logged-in session
+// (fast user switching, RDP) gets its own instance, matching the per-session
+// IPC pipe.
 const (
-	mutexName = "Global\\GoMuseToolSingleInstanceMutex"
+	mutexName = "Local\\GoMuseToolSingleInstanceMutex"
 )
 
 var (
@@ -25,9 +27,9 @@ const (
 	ERROR_ALREADY_EXISTS = 183
 )
 
-// CheckSingleInstance checks if another instance is already running
+// AcquireSingleInstance checks if another instance is already running
 // Returns true if this is the first instance, false if already running
-func CheckSingleInstance() bool {
+func (windowsPlatform) AcquireSingleInstance() bool {
 	mutexNamePtr, err := syscall.UTF16PtrFromString(mutexName)
 	if err != nil {
 		return true // If error, allow to continue
@@ -62,7 +64,7 @@ func CheckSingleInstance() bool {
 }
 
 // ReleaseSingleInstance releases the mutex when application exits
-func ReleaseSingleInstance() {
+func (windowsPlatform) ReleaseSingleInstance() {
 	if singleInstanceMutexHandle != 0 {
 		procReleaseMutex.Call(uintptr(singleInstanceMutexHandle))
 		procCloseHandle.Call(uintptr(singleInstanceMutexHandle))
@@ -70,11 +72,8 @@ func ReleaseSingleInstance() {
 	}
 }
 
-// ShowAlreadyRunningDialog shows a dialog when instance is already running
-func ShowAlreadyRunningDialog() {
-	title := language.T().WindowTitle
-	message := "程序已在运行中"
-
+// ShowAlreadyRunning shows a message box when instance is already running
+func (windowsPlatform) ShowAlreadyRunning(title, message string) {
 	// Show message box
 	messagePtr, _ := syscall.UTF16PtrFromString(message)
 	titlePtr, _ := syscall.UTF16PtrFromString(title)
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"go-musetool/internal/language"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/model"
+	"go-musetool/internal/paste"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	nativeDialog "github.com/sqweek/dialog"
+)
+
+// copySnippet 是文本片段的启动处理程序：复制到剪贴板并提示，按设置粘贴到之前使用的窗口
+func (l *LauncherApp) copySnippet(s model.Shortcut) error {
+	l.App.Clipboard().SetContent(s.Text)
+	l.showToast(fmt.Sprintf(language.T().SnippetCopied, s.Name))
+	if s.AutoPaste {
+		go func() {
+			if err := paste.IntoPreviousWindow(); err != nil {
+				logger.Error("Failed to paste snippet %s: %v", s.Name, err)
+				fyne.Do(func() { l.showToast(language.T().SnippetPasteFailed) })
+			}
+		}()
+	}
+	return nil
+}
+
+// exportSnippets 把分组中的文本片段导出为 JSON 文件，便于分享给他人
+func (l *LauncherApp) exportSnippets(groupName string) {
+	group := storage.FindGroup(l.Config, groupName)
+	if group == nil {
+		return
+	}
+	filename, err := nativeDialog.File().Title(language.T().ContextMenuExportSnippets).Filter("JSON", "json").Save()
+	if err != nil || filename == "" {
+		return
+	}
+	if !strings.HasSuffix(strings.ToLower(filename), ".json") {
+		filename += ".json"
+	}
+	n, err := storage.ExportSnippets(filename, group.Shortcuts)
+	if err != nil {
+		logger.Error("Failed to export snippets of %s: %v", groupName, err)
+		dialog.ShowError(err, l.Window)
+		return
+	}
+	l.showToast(fmt.Sprintf(language.T().SnippetsExported, n))
+}
+
+// importSnippets 把文本片段文件中的片段添加到分组
+func (l *LauncherApp) importSnippets(groupName string) {
+	filename, err := nativeDialog.File().Title(language.T().ContextMenuImportSnippets).Filter("JSON", "json").Load()
+	if err != nil || filename == "" {
+		return
+	}
+	n, err := storage.ImportSnippets(l.Config, groupName, filename)
+	if n > 0 {
+		if saveErr := storage.SaveConfig(l.ConfigPath, l.Config); saveErr != nil {
+			logger.Error("error saving config: %v", saveErr)
+		}
+		l.setupUI()
+	}
+	if err != nil {
+		logger.Error("Failed to import snippets into %s: %v", groupName, err)
+		dialog.ShowError(err, l.Window)
+		return
+	}
+	l.showToast(fmt.Sprintf(language.T().SnippetsImported, n))
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// toastDuration 提示显示的时间
+const toastDuration = 1500 * time.Millisecond
+
+// showToast 在主窗口底部短暂显示一条提示，不需要用户关闭
+func (l *LauncherApp) showToast(message string) {
+	if l.Window == nil {
+		return
+	}
+	if l.toast != nil {
+		l.toast.Hide()
+	}
+	c := l.Window.Canvas()
+	toast := widget.NewPopUp(widget.NewLabel(message), c)
+	size := toast.MinSize()
+	toast.ShowAtPosition(fyne.NewPos((c.Size().Width-size.Width)/2, c.Size().Height-size.Height-48))
+	l.toast = toast
+
+	time.AfterFunc(toastDuration, func() {
+		fyne.Do(func() {
+			toast.Hide()
+			if l.toast == toast {
+				l.toast = nil
+			}
+		})
+	})
+}
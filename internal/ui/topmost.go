@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"log"
+
+	"go-musetool/internal/language"
+	"go-musetool/internal/model"
+
+	"fyne.io/fyne/v2"
+)
+
+// mainWindowHandle returns the native handle of the main window. It is looked
+// up by title until the window exists and cached after that; the first time
+// it is found the window events are started.
+func (l *LauncherApp) mainWindowHandle() uintptr {
+	if hwnd := l.hwnd.Load(); hwnd != 0 {
+		return hwnd
+	}
+	hwnd := GetWindowHandle(language.T().WindowTitle)
+	if hwnd != 0 && l.hwnd.CompareAndSwap(0, hwnd) {
+		l.watchMainWindow(hwnd)
+	}
+	return hwnd
+}
+
+// watchMainWindow starts the events that drive the main window: its moves
+// for edge docking and changes of the active window for the always-on-top
+// state
+func (l *LauncherApp) watchMainWindow(hwnd uintptr) {
+	if _, ok := native.WatchForeground(func() { fyne.Do(l.updateTopmost) }); !ok {
+		log.Println("active window events are not available")
+	}
+	fyne.Do(func() {
+		if c := l.dockContainer; c != nil {
+			go c.Watch(hwnd)
+		}
+		l.refreshTopmost()
+	})
+}
+
+// hasChildWindow 是否有子窗口打开（使用内部引用而不是系统调用）
+func (l *LauncherApp) hasChildWindow() bool {
+	return l.SettingsWindow != nil ||
+		l.ShortcutWindow != nil ||
+		l.AddGroupWindow != nil ||
+		l.EditGroupWindow != nil ||
+		l.DeleteGroupWindow != nil ||
+		l.EditGroupDialogForWindow != nil ||
+		l.DeleteGroupDialogForWindow != nil ||
+		l.DeleteShortcutWindow != nil ||
+		l.AboutWindow != nil ||
+		l.WorkspaceWindow != nil ||
+		l.InputPromptWindow != nil ||
+		l.RoutesWindow != nil
+}
+
+// wantTopmost 按置顶策略决定主窗口是否应总在最前
+func (l *LauncherApp) wantTopmost() bool {
+	// 有子窗口时主窗口不置顶，以免挡住子窗口
+	if l.hasChildWindow() {
+		return false
+	}
+	switch l.Config.AlwaysOnTop {
+	case model.TopmostNever:
+		return false
+	case model.TopmostDocked:
+		return l.dockContainer != nil && l.dockContainer.Docked()
+	}
+	return true
+}
+
+// updateTopmost applies the always-on-top policy. It runs on the UI thread
+// when the active window changes, the window docks or undocks and the
+// settings are saved, and only calls the platform when the wanted state
+// differs from the last one, so that it does not fight other topmost windows.
+func (l *LauncherApp) updateTopmost() {
+	hwnd := l.hwnd.Load()
+	// 全屏时不改变置顶状态
+	if hwnd == 0 || l.Window == nil || l.Window.FullScreen() {
+		return
+	}
+	want := l.wantTopmost()
+	if l.topmostSet && l.topmost == want {
+		return
+	}
+	l.topmost, l.topmostSet = want, true
+	go SetWindowAlwaysOnTop(hwnd, want)
+}
+
+// refreshTopmost applies the policy even if it did not change, e.g. after the
+// window style was changed
+func (l *LauncherApp) refreshTopmost() {
+	l.topmostSet = false
+	l.updateTopmost()
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"bytes"
+	"image/png"
+
+	"go-musetool/internal/language"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"github.com/fyne-io/image/ico"
+)
+
+// trayRecentLimit 托盘 "最近使用" 子菜单最多显示的快捷方式数
+const trayRecentLimit = 10
+
+// initTray shows the tray icon (notification area on Windows,
+// StatusNotifierItem on Linux). Left click shows the main window; the menu
+// launches shortcuts without opening it.
+func (l *LauncherApp) initTray() {
+	desk, ok := l.App.(desktop.App)
+	if !ok {
+		logger.Info("[Tray] System tray is not supported by this driver")
+		return
+	}
+	if icon := trayIconPNG(l.getMainWindowIconData()); icon != nil {
+		desk.SetSystemTrayIcon(icon)
+	}
+	desk.SetSystemTrayMenu(l.trayMenu())
+	// 不使用 SetSystemTrayWindow：它直接调用 Window.Show，隐藏启动后第一次显示时
+	// 需要经过 showMainWindow 恢复窗口位置和样式
+	setTrayTapped(func() { fyne.Do(l.showMainWindow) })
+}
+
+// refreshTray rebuilds the tray menu after the groups, shortcuts or launch
+// history changed (must run on the UI thread)
+func (l *LauncherApp) refreshTray() {
+	if desk, ok := l.App.(desktop.App); ok {
+		desk.SetSystemTrayMenu(l.trayMenu())
+	}
+}
+
+func (l *LauncherApp) trayMenu() *fyne.Menu {
+	items := []*fyne.MenuItem{
+		fyne.NewMenuItem(language.T().TrayShow, l.showMainWindow),
+		fyne.NewMenuItemSeparator(),
+	}
+
+	// 每个分组一个子菜单：全部启动 + 分组内的快捷方式
+	for _, group := range l.Config.Groups {
+		if len(group.Shortcuts) == 0 {
+			continue
+		}
+		groupName := group.Name
+		groupItems := []*fyne.MenuItem{
+			fyne.NewMenuItem(language.T().TrayLaunchAll, func() { l.launchWorkspaceFromMenu(groupName) }),
+			fyne.NewMenuItemSeparator(),
+		}
+		for _, i := range l.shortcutOrder(group.Shortcuts) {
+			groupItems = append(groupItems, l.trayShortcutItem(group.Shortcuts[i]))
+		}
+		item := fyne.NewMenuItem(group.Name, nil)
+		item.ChildMenu = fyne.NewMenu("", groupItems...)
+		items = append(items, item)
+	}
+
+	if recent := l.trayRecentItems(); len(recent) > 0 {
+		item := fyne.NewMenuItem(language.T().TrayRecent, nil)
+		item.ChildMenu = fyne.NewMenu("", recent...)
+		items = append(items, fyne.NewMenuItemSeparator(), item)
+	}
+
+	// Fyne 会自动添加 "Quit"，这里使用自己的退出项以便保存窗口状态
+	quit := fyne.NewMenuItem(language.T().TrayExit, func() {
+		l.saveWindowState()
+		l.App.Quit()
+	})
+	quit.IsQuit = true
+	items = append(items,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(language.T().TraySettings, l.showSettingsDialog),
+		fyne.NewMenuItemSeparator(),
+		quit,
+	)
+	return fyne.NewMenu("", items...)
+}
+
+// trayRecentItems 最近启动过、且仍然存在的快捷方式
+func (l *LauncherApp) trayRecentItems() []*fyne.MenuItem {
+	if !l.historyEnabled() {
+		return nil
+	}
+	var items []*fyne.MenuItem
+	for _, id := range l.History.Recent(0) {
+		_, shortcut, ok := storage.FindShortcutByID(l.Config, id)
+		if !ok {
+			continue
+		}
+		items = append(items, l.trayShortcutItem(shortcut))
+		if len(items) == trayRecentLimit {
+			break
+		}
+	}
+	return items
+}
+
+func (l *LauncherApp) trayShortcutItem(shortcut model.Shortcut) *fyne.MenuItem {
+	item := fyne.NewMenuItem(shortcut.Name, func() {
+		if err := l.launchShortcut(shortcut); err != nil {
+			logger.Error("Failed to launch %s from tray: %v", shortcut.Name, err)
+			l.showMainWindow()
+			dialog.ShowError(err, l.Window)
+		}
+	})
+	item.Icon = l.trayIcon(shortcut.IconPath)
+	return item
+}
+
+// trayIcon 加载快捷方式图标，菜单每次重建都会用到，所以缓存结果（包括失败）
+func (l *LauncherApp) trayIcon(path string) fyne.Resource {
+	if path == "" {
+		return nil
+	}
+	if res, ok := l.trayIcons[path]; ok {
+		return res
+	}
+	res, err := fyne.LoadResourceFromPath(path)
+	if err != nil {
+		logger.Debug("[Tray] Failed to load icon %s: %v", path, err)
+		res = nil
+	}
+	if l.trayIcons == nil {
+		l.trayIcons = map[string]fyne.Resource{}
+	}
+	l.trayIcons[path] = res
+	return res
+}
+
+// trayIconPNG converts the embedded .ico to PNG, which both the Windows
+// notification area (via Fyne) and StatusNotifierItem hosts accept
+func trayIconPNG(iconData []byte) fyne.Resource {
+	img, err := ico.Decode(bytes.NewReader(iconData))
+	if err != nil {
+		logger.Error("[Tray] Failed to decode tray icon: %v", err)
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		logger.Error("[Tray] Failed to encode tray icon: %v", err)
+		return nil
+	}
+	return fyne.NewStaticResource("tray.png", buf.Bytes())
+}
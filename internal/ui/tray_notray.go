@@ -0,0 +1,6 @@
+//go:build wasm || test_web_driver
+
+package ui
+
+// setTrayTapped does nothing: the web driver has no system tray
+func setTrayTapped(func()) {}
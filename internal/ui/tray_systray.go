@@ -0,0 +1,13 @@
+//go:build !wasm && !test_web_driver
+
+package ui
+
+import "fyne.io/systray"
+
+// setTrayTapped sets what a left click on the tray icon does. Fyne only
+// offers SetSystemTrayWindow, which replaces the close intercept and calls
+// Window.Show directly, so the desktop driver's systray is used here; the
+// build constraint matches the Fyne driver that runs it.
+func setTrayTapped(f func()) {
+	systray.SetOnTapped(f)
+}
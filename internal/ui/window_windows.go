@@ -18,6 +18,13 @@ var (
 	procDwmSetWindowAttribute = dwmapi.NewProc("DwmSetWindowAttribute")
 )
 
+// windowsPlatform implements platform with the Win32 API
+type windowsPlatform struct{}
+
+func newPlatform() platform {
+	return windowsPlatform{}
+}
+
 type RECT struct {
 	Left, Top, Right, Bottom int32
 }
@@ -54,24 +61,23 @@ const (
 	DWMWA_CAPTION_COLOR = 35
 )
 
-func GetWindowHandle(title string) uintptr {
+func (windowsPlatform) WindowHandle(title string) uintptr {
 	ptr, _ := syscall.UTF16PtrFromString(title)
 	hwnd, _, _ := procFindWindowW.Call(0, uintptr(unsafe.Pointer(ptr)))
 	return hwnd
 }
 
-func GetWindowRect(hwnd uintptr) (int, int, int, int) {
+func (windowsPlatform) WindowRect(hwnd uintptr) (int, int, int, int) {
 	var rect RECT
 	procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&rect)))
 	return int(rect.Left), int(rect.Top), int(rect.Right - rect.Left), int(rect.Bottom - rect.Top)
 }
 
-func SetWindowPos(hwnd uintptr, x, y int) {
+func (windowsPlatform) MoveWindow(hwnd uintptr, x, y int) {
 	procSetWindowPos.Call(hwnd, 0, uintptr(x), uintptr(y), 0, 0, SWP_NOSIZE|SWP_NOZORDER)
 }
 
-// SetWindowAlwaysOnTop 设置窗口是否总在最前
-func SetWindowAlwaysOnTop(hwnd uintptr, alwaysOnTop bool) {
+func (windowsPlatform) SetAlwaysOnTop(hwnd uintptr, alwaysOnTop bool) {
 	var target uintptr
 	if alwaysOnTop {
 		target = ^uintptr(0) // -1
@@ -83,11 +89,7 @@ func SetWindowAlwaysOnTop(hwnd uintptr, alwaysOnTop bool) {
 	procSetWindowPos.Call(hwnd, target, 0, 0, 0, 0, SWP_NOMOVE|SWP_NOSIZE|SWP_NOACTIVATE)
 }
 
-func ResizeWindow(hwnd uintptr, w, h int) {
-	procSetWindowPos.Call(hwnd, 0, 0, 0, uintptr(w), uintptr(h), SWP_NOMOVE|SWP_NOZORDER)
-}
-
-func MoveAndResizeWindow(hwnd uintptr, x, y, w, h int) {
+func (windowsPlatform) MoveAndResizeWindow(hwnd uintptr, x, y, w, h int) {
 	procSetWindowPos.Call(hwnd, 0, uintptr(x), uintptr(y), uintptr(w), uintptr(h), SWP_NOZORDER)
 }
 
@@ -102,8 +104,7 @@ func SetWindowLong(hwnd uintptr, nIndex int, dwNewLong uintptr) uintptr {
 }
 
 // SetTitleBarColor 使用 DWM API 设置标题栏颜色
-// color 必须是 BGR (0xBBGGRR) 格式
-func SetTitleBarColor(hwnd uintptr, color uint32) {
+func (windowsPlatform) SetTitleBarColor(hwnd uintptr, color uint32) {
 	attr := uintptr(DWMWA_CAPTION_COLOR)
 	colorPtr := unsafe.Pointer(&color)
 
@@ -152,7 +153,7 @@ const (
 	ResizeBottomRight = 8
 )
 
-func GetCursorPos() (int, int) {
+func (windowsPlatform) CursorPos() (int, int) {
 	var pt POINT
 	procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
 	return int(pt.X), int(pt.Y)
@@ -169,21 +170,21 @@ const (
 	SPI_GETWORKAREA = 0x0030
 )
 
-func GetScreenSize() (int, int) {
+func (windowsPlatform) ScreenSize() (int, int) {
 	w, _, _ := procGetSystemMetrics.Call(uintptr(SM_CXSCREEN))
 	h, _, _ := procGetSystemMetrics.Call(uintptr(SM_CYSCREEN))
 	return int(w), int(h)
 }
 
-// GetWorkArea 获取不包含任务栏的屏幕工作区
-func GetWorkArea() (int, int, int, int) {
+// WorkArea 获取不包含任务栏的屏幕工作区
+func (windowsPlatform) WorkArea() (int, int, int, int) {
 	var rect RECT
 	procSystemParametersInfoW.Call(SPI_GETWORKAREA, 0, uintptr(unsafe.Pointer(&rect)), 0)
 	return int(rect.Left), int(rect.Top), int(rect.Right), int(rect.Bottom)
 }
 
-// IsSystemDarkMode 检测 Windows 系统是否使用深色模式
-func IsSystemDarkMode() bool {
+// IsDarkMode 检测 Windows 系统是否使用深色模式
+func (windowsPlatform) IsDarkMode() bool {
 	// 读取注册表：HKEY_CURRENT_USER\Software\Microsoft\Windows\CurrentVersion\Themes\Personalize
 	// 键名：AppsUseLightTheme
 	// 值：0 = 深色模式，1 = 浅色模式
@@ -239,15 +240,15 @@ func IsSystemDarkMode() bool {
 	return data == 0
 }
 
-// IsWindowMaximized 检测窗口是否处于最大化状态
-func IsWindowMaximized(hwnd uintptr) bool {
+// IsMaximized 检测窗口是否处于最大化状态
+func (windowsPlatform) IsMaximized(hwnd uintptr) bool {
 	style := GetWindowLong(hwnd, GWL_STYLE)
 	// 检查 WS_MAXIMIZE 标志
 	return (style & WS_MAXIMIZE) != 0
 }
 
-// SetWindowOpacity sets the window opacity (0.0 = fully transparent, 1.0 = fully opaque)
-func SetWindowOpacity(hwnd uintptr, opacity float64) {
+// SetOpacity sets the window opacity (0.0 = fully transparent, 1.0 = fully opaque)
+func (windowsPlatform) SetOpacity(hwnd uintptr, opacity float64) {
 	// Clamp opacity to valid range
 	if opacity < 0.0 {
 		opacity = 0.0
@@ -278,8 +279,8 @@ func SetWindowOpacity(hwnd uintptr, opacity float64) {
 	procSetLayeredWindowAttributes.Call(hwnd, 0, uintptr(alpha), LWA_ALPHA)
 }
 
-// SetWindowNoTaskbar 设置窗口不在任务栏显示
-func SetWindowNoTaskbar(hwnd uintptr) {
+// SetNoTaskbar 设置窗口不在任务栏显示
+func (windowsPlatform) SetNoTaskbar(hwnd uintptr) {
 	const (
 		WS_EX_TOOLWINDOW = 0x00000080
 		WS_EX_APPWINDOW  = 0x00040000
@@ -291,4 +292,83 @@ func SetWindowNoTaskbar(hwnd uintptr) {
 	// Add WS_EX_TOOLWINDOW style and remove WS_EX_APPWINDOW to prevent showing in taskbar
 	newStyle := (exStyle | WS_EX_TOOLWINDOW) &^ WS_EX_APPWINDOW
 	SetWindowLong(hwnd, GWL_EXSTYLE, newStyle)
-}
\ No newline at end of file
+}
+
+// MinimizeWindow minimizes a window to the taskbar
+func MinimizeWindow(hwnd uintptr) {
+	const SW_MINIMIZE = 6
+	showWindow := syscall.NewLazyDLL("user32.dll").NewProc("ShowWindow")
+	showWindow.Call(hwnd, SW_MINIMIZE)
+}
+
+// HideWindow hides a window completely
+func HideWindow(hwnd uintptr) {
+	const SW_HIDE = 0
+	showWindow := syscall.NewLazyDLL("user32.dll").NewProc("ShowWindow")
+	showWindow.Call(hwnd, SW_HIDE)
+}
+
+// ShowWindow shows a window
+func ShowWindowNormal(hwnd uintptr) {
+	const SW_SHOW = 5
+	const SW_RESTORE = 9
+	showWindow := syscall.NewLazyDLL("user32.dll").NewProc("ShowWindow")
+	// First restore if minimized
+	showWindow.Call(hwnd, SW_RESTORE)
+	// Then show
+	showWindow.Call(hwnd, SW_SHOW)
+	// Bring to foreground
+	setForegroundWindow := syscall.NewLazyDLL("user32.dll").NewProc("SetForegroundWindow")
+	setForegroundWindow.Call(hwnd)
+}
+
+// IsWindowVisible checks if a window is currently visible
+func IsWindowVisible(hwnd uintptr) bool {
+	isWindowVisible := syscall.NewLazyDLL("user32.dll").NewProc("IsWindowVisible")
+	ret, _, _ := isWindowVisible.Call(hwnd)
+	return ret != 0
+}
+
+// BringWindowToFront brings a window to the foreground
+func BringWindowToFront(hwnd uintptr) {
+	// Remove topmost flag temporarily
+	SetWindowAlwaysOnTop(hwnd, false)
+
+	// Show and restore the window
+	const SW_RESTORE = 9
+	showWindow := syscall.NewLazyDLL("user32.dll").NewProc("ShowWindow")
+	showWindow.Call(hwnd, SW_RESTORE)
+
+	// Set foreground
+	setForegroundWindow := syscall.NewLazyDLL("user32.dll").NewProc("SetForegroundWindow")
+	setForegroundWindow.Call(hwnd)
+
+	// Restore topmost flag
+	SetWindowAlwaysOnTop(hwnd, true)
+}
+
+// FlashWindow flashes the window to get user attention
+func FlashWindow(hwnd uintptr) {
+	const FLASHW_ALL = 3
+	const FLASHW_TIMERNOFG = 12
+
+	type FLASHWINFO struct {
+		cbSize    uint32
+		hwnd      uintptr
+		dwFlags   uint32
+		uCount    uint32
+		dwTimeout uint32
+	}
+
+	flashWindowEx := syscall.NewLazyDLL("user32.dll").NewProc("FlashWindowEx")
+
+	fwi := FLASHWINFO{
+		cbSize:    uint32(unsafe.Sizeof(FLASHWINFO{})),
+		hwnd:      hwnd,
+		dwFlags:   FLASHW_ALL | FLASHW_TIMERNOFG,
+		uCount:    3,
+		dwTimeout: 0,
+	}
+
+	flashWindowEx.Call(uintptr(unsafe.Pointer(&fwi)))
+}
@@ -0,0 +1,274 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-musetool/internal/language"
+	"go-musetool/internal/launcher"
+	"go-musetool/internal/logger"
+	"go-musetool/internal/model"
+	"go-musetool/internal/storage"
+	"go-musetool/internal/workspace"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// launchWorkspace 在后台按分组的"全部启动"设置启动所有快捷方式（在 UI 线程调用）。
+// 返回的错误只表示无法开始；各个启动的失败汇总后显示在主窗口中。
+func (l *LauncherApp) launchWorkspace(groupName string) error {
+	group := storage.FindGroup(l.Config, groupName)
+	if group == nil {
+		return fmt.Errorf("%w: %s", storage.ErrGroupNotFound, groupName)
+	}
+	// 复制一份，后台启动期间配置可能被修改
+	snapshot := model.Group{Name: group.Name, Shortcuts: append([]model.Shortcut{}, group.Shortcuts...)}
+	if group.Workspace != nil {
+		ws := *group.Workspace
+		ws.Shortcuts = append([]string{}, ws.Shortcuts...)
+		snapshot.Workspace = &ws
+	}
+	if len(workspace.Plan(snapshot)) == 0 {
+		return fmt.Errorf(language.T().WorkspaceEmpty, groupName)
+	}
+
+	logger.Info("Launching workspace %s", groupName)
+	go func() {
+		summary := workspace.Launch(context.Background(), snapshot, l.launchFromWorkspace)
+
+		failed := summary.Failed()
+		if len(failed) == 0 {
+			logger.Info("Workspace %s launched %d shortcuts", groupName, len(summary.Results))
+			return
+		}
+		logger.Error("Workspace %s: %v", groupName, summary.Err())
+
+		lines := []string{fmt.Sprintf(language.T().WorkspaceFailed, len(failed), len(summary.Results))}
+		for _, r := range failed {
+			lines = append(lines, fmt.Sprintf("• %s: %v", r.Name, r.Err))
+		}
+		fyne.Do(func() {
+			l.showMainWindow()
+			dialog.ShowInformation(language.T().Error, strings.Join(lines, "\n"), l.Window)
+		})
+	}()
+	return nil
+}
+
+// launchFromWorkspace 在后台 goroutine 中启动一个快捷方式：只有读取界面状态和记录历史
+// 在 UI 线程执行，启动本身不占用 UI 线程，这样并发数设置才能生效
+func (l *LauncherApp) launchFromWorkspace(s model.Shortcut) error {
+	var p pendingLaunch
+	var err error
+	started := false
+	fyne.DoAndWait(func() {
+		// 需要输入或使用界面的快捷方式直接在 UI 线程启动
+		if len(launcher.InputNames(s)) > 0 || startsOnUIThread(s) {
+			err, started = l.launchShortcut(s), true
+			return
+		}
+		p, err = l.prepareLaunch(s, nil)
+	})
+	if started || err != nil {
+		return err
+	}
+
+	launchErr := p.start()
+	fyne.Do(func() { l.recordLaunch(p.shortcut, launchErr) })
+	l.rescanRunning()
+	return launchErr
+}
+
+// launchWorkspaceFromMenu 供右键菜单和托盘使用，出错时弹窗提示
+func (l *LauncherApp) launchWorkspaceFromMenu(groupName string) {
+	if err := l.launchWorkspace(groupName); err != nil {
+		logger.Error("Failed to launch workspace %s: %v", groupName, err)
+		dialog.ShowError(err, l.Window)
+	}
+}
+
+// showWorkspaceSettingsDialog 编辑分组的"全部启动"设置：参与的快捷方式、顺序、间隔和并发数
+func (l *LauncherApp) showWorkspaceSettingsDialog(groupName string) {
+	if l.WorkspaceWindow != nil {
+		l.applyWindowStyle(language.T().WorkspaceSettingsTitle)
+		l.WorkspaceWindow.Show()
+		l.WorkspaceWindow.RequestFocus()
+		return
+	}
+	group := storage.FindGroup(l.Config, groupName)
+	if group == nil {
+		return
+	}
+
+	// 已选中的按设置中的顺序排在前面，其余按分组顺序排在后面
+	// 没有设置列表时全部勾选
+	hasList := group.Workspace != nil && len(group.Workspace.Shortcuts) > 0
+	var order []string
+	included := map[string]bool{}
+	if hasList {
+		for _, s := range workspace.Plan(*group) {
+			order = append(order, s.Name)
+			included[s.Name] = true
+		}
+	}
+	for _, s := range group.Shortcuts {
+		if _, seen := included[s.Name]; !seen {
+			order = append(order, s.Name)
+			included[s.Name] = !hasList
+		}
+	}
+
+	win := l.App.NewWindow(language.T().WorkspaceSettingsTitle)
+	win.Resize(fyne.NewSize(400, 420))
+	win.CenterOnScreen()
+	win.SetIcon(nil)
+	l.applyWindowStyle(language.T().WorkspaceSettingsTitle)
+
+	rows := container.NewVBox()
+	var refreshRows func()
+	refreshRows = func() {
+		rows.RemoveAll()
+		for i, name := range order {
+			index, shortcutName := i, name
+			check := widget.NewCheck(shortcutName, func(checked bool) { included[shortcutName] = checked })
+			check.SetChecked(included[shortcutName])
+			upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+				order[index-1], order[index] = order[index], order[index-1]
+				refreshRows()
+			})
+			if index == 0 {
+				upBtn.Disable()
+			}
+			downBtn := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() {
+				order[index+1], order[index] = order[index], order[index+1]
+				refreshRows()
+			})
+			if index == len(order)-1 {
+				downBtn.Disable()
+			}
+			rows.Add(container.NewBorder(nil, nil, nil, container.NewHBox(upBtn, downBtn), check))
+		}
+		rows.Refresh()
+	}
+	refreshRows()
+
+	delayEntry := widget.NewEntry()
+	delayEntry.SetPlaceHolder("0")
+	concurrencyEntry := widget.NewEntry()
+	concurrencyEntry.SetPlaceHolder("1")
+	if group.Workspace != nil {
+		if group.Workspace.DelayMs > 0 {
+			delayEntry.SetText(strconv.Itoa(group.Workspace.DelayMs))
+		}
+		if group.Workspace.Concurrency > 0 {
+			concurrencyEntry.SetText(strconv.Itoa(group.Workspace.Concurrency))
+		}
+	}
+
+	// parseCount 解析非负整数，空值为 0
+	parseCount := func(entry *widget.Entry) (int, bool) {
+		text := strings.TrimSpace(entry.Text)
+		if text == "" {
+			return 0, true
+		}
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 0 {
+			dialog.ShowError(fmt.Errorf(language.T().WorkspaceInvalidNumber, text), win)
+			return 0, false
+		}
+		return n, true
+	}
+
+	saveBtn := widget.NewButton(language.T().Save, func() {
+		delay, ok := parseCount(delayEntry)
+		if !ok {
+			return
+		}
+		concurrency, ok := parseCount(concurrencyEntry)
+		if !ok {
+			return
+		}
+
+		// 分组可能在窗口打开期间被改名或删除
+		target := storage.FindGroup(l.Config, groupName)
+		if target == nil {
+			dialog.ShowError(fmt.Errorf("%w: %s", storage.ErrGroupNotFound, groupName), win)
+			return
+		}
+
+		ws := &model.Workspace{DelayMs: delay, Concurrency: concurrency}
+		allIncluded := true
+		for _, name := range order {
+			if included[name] {
+				ws.Shortcuts = append(ws.Shortcuts, name)
+			} else {
+				allIncluded = false
+			}
+		}
+		if len(ws.Shortcuts) == 0 && len(order) > 0 {
+			dialog.ShowError(fmt.Errorf(language.T().WorkspaceEmpty, groupName), win)
+			return
+		}
+		// 全部勾选且顺序未变时不保存列表，之后新增的快捷方式也会自动参与
+		if allIncluded && sameOrder(order, target.Shortcuts) {
+			ws.Shortcuts = nil
+		}
+		if ws.Shortcuts == nil && ws.DelayMs == 0 && ws.Concurrency == 0 {
+			target.Workspace = nil
+		} else {
+			target.Workspace = ws
+		}
+		if err := storage.SaveConfig(l.ConfigPath, l.Config); err != nil {
+			logger.Error("error saving config: %v", err)
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), win)
+			return
+		}
+		l.WorkspaceWindow = nil
+		win.Close()
+	})
+	cancelBtn := widget.NewButton(language.T().Cancel, func() {
+		l.WorkspaceWindow = nil
+		win.Close()
+	})
+
+	form := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel(language.T().WorkspaceDelay), nil, delayEntry),
+		container.NewBorder(nil, nil, widget.NewLabel(language.T().WorkspaceConcurrency), nil, concurrencyEntry),
+		container.NewHBox(layout.NewSpacer(), cancelBtn, saveBtn),
+	)
+	win.SetContent(container.NewBorder(
+		widget.NewLabel(language.T().WorkspaceShortcuts),
+		form,
+		nil, nil,
+		container.NewVScroll(rows),
+	))
+
+	l.WorkspaceWindow = win
+	win.SetOnClosed(func() {
+		l.WorkspaceWindow = nil
+	})
+	setupEscapeKeyCloseWithShortcut(win, func() {
+		win.Close()
+	})
+	win.Show()
+}
+
+// sameOrder reports whether names lists the group's shortcuts in group order
+func sameOrder(names []string, shortcuts []model.Shortcut) bool {
+	if len(names) != len(shortcuts) {
+		return false
+	}
+	for i, s := range shortcuts {
+		if names[i] != s.Name {
+			return false
+		}
+	}
+	return true
+}
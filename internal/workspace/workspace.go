@@ -0,0 +1,115 @@
+// Package workspace launches a whole group ("Launch all") according to the
+// group's workspace settings.
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-musetool/internal/model"
+)
+
+// Result is the outcome of one launch
+type Result struct {
+	Name string
+	Err  error
+}
+
+// Summary collects the results of launching a group, in launch order
+type Summary struct {
+	Group   string
+	Results []Result
+}
+
+// Failed returns the launches that returned an error
+func (s Summary) Failed() []Result {
+	var failed []Result
+	for _, r := range s.Results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// Err summarises the failed launches, or returns nil if all succeeded
+func (s Summary) Err() error {
+	failed := s.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	lines := make([]string, len(failed))
+	for i, r := range failed {
+		lines[i] = fmt.Sprintf("%s: %v", r.Name, r.Err)
+	}
+	return fmt.Errorf("%d of %d launches in %s failed: %s", len(failed), len(s.Results), s.Group, strings.Join(lines, "; "))
+}
+
+// Plan returns the shortcuts launched by "Launch all", in launch order.
+// Without settings every shortcut is launched in group order; names in the
+// settings that no longer exist are skipped.
+func Plan(group model.Group) []model.Shortcut {
+	if group.Workspace == nil || len(group.Workspace.Shortcuts) == 0 {
+		return append([]model.Shortcut{}, group.Shortcuts...)
+	}
+	byName := make(map[string]model.Shortcut, len(group.Shortcuts))
+	for _, s := range group.Shortcuts {
+		byName[s.Name] = s
+	}
+	var plan []model.Shortcut
+	for _, name := range group.Workspace.Shortcuts {
+		if s, ok := byName[name]; ok {
+			plan = append(plan, s)
+			delete(byName, name) // 重复的名称只启动一次
+		}
+	}
+	return plan
+}
+
+// Launch starts the planned shortcuts. At most Concurrency launches are in
+// flight at once, and consecutive launches are started DelayMs apart.
+// Cancelling ctx skips the launches that have not started yet.
+func Launch(ctx context.Context, group model.Group, launch func(model.Shortcut) error) Summary {
+	plan := Plan(group)
+	summary := Summary{Group: group.Name, Results: make([]Result, len(plan))}
+
+	concurrency, delay := 1, time.Duration(0)
+	if ws := group.Workspace; ws != nil {
+		if ws.Concurrency > 1 {
+			concurrency = ws.Concurrency
+		}
+		delay = time.Duration(ws.DelayMs) * time.Millisecond
+	}
+
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, s := range plan {
+		summary.Results[i].Name = s.Name
+		if i > 0 && delay > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
+		}
+		select {
+		case <-ctx.Done():
+		case slots <- struct{}{}:
+		}
+		if err := ctx.Err(); err != nil {
+			summary.Results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, s model.Shortcut) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			summary.Results[i].Err = launch(s)
+		}(i, s)
+	}
+	wg.Wait()
+	return summary
+}
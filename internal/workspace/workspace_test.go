@@ -0,0 +1,161 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-musetool/internal/model"
+)
+
+func group(ws *model.Workspace, names ...string) model.Group {
+	g := model.Group{Name: "Dev", Workspace: ws}
+	for _, name := range names {
+		g.Shortcuts = append(g.Shortcuts, model.Shortcut{Name: name})
+	}
+	return g
+}
+
+func names(shortcuts []model.Shortcut) []string {
+	var out []string
+	for _, s := range shortcuts {
+		out = append(out, s.Name)
+	}
+	return out
+}
+
+// fakeLauncher records launch order and the highest number of launches in
+// flight at once. Each launch takes hold; names in fail return errFake.
+type fakeLauncher struct {
+	hold time.Duration
+	fail map[string]bool
+
+	mu          sync.Mutex
+	order       []string
+	started     []time.Time
+	inFlight    int
+	maxInFlight int
+}
+
+var errFake = errors.New("fake failure")
+
+func (f *fakeLauncher) launch(s model.Shortcut) error {
+	f.mu.Lock()
+	f.order = append(f.order, s.Name)
+	f.started = append(f.started, time.Now())
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	f.mu.Unlock()
+
+	time.Sleep(f.hold)
+
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+	if f.fail[s.Name] {
+		return errFake
+	}
+	return nil
+}
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name string
+		ws   *model.Workspace
+		want []string
+	}{
+		{"no settings", nil, []string{"A", "B", "C"}},
+		{"empty list", &model.Workspace{DelayMs: 100}, []string{"A", "B", "C"}},
+		{"custom order", &model.Workspace{Shortcuts: []string{"C", "A"}}, []string{"C", "A"}},
+		{"missing names skipped", &model.Workspace{Shortcuts: []string{"Gone", "B"}}, []string{"B"}},
+		{"duplicates once", &model.Workspace{Shortcuts: []string{"B", "B", "A"}}, []string{"B", "A"}},
+	}
+	for _, tt := range tests {
+		if got := names(Plan(group(tt.ws, "A", "B", "C"))); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Plan = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLaunchSequentialByDefault(t *testing.T) {
+	f := &fakeLauncher{hold: 5 * time.Millisecond}
+	summary := Launch(context.Background(), group(nil, "A", "B", "C", "D"), f.launch)
+	if summary.Err() != nil {
+		t.Fatal(summary.Err())
+	}
+	if f.maxInFlight != 1 {
+		t.Errorf("%d launches in flight, want 1", f.maxInFlight)
+	}
+	if want := []string{"A", "B", "C", "D"}; !reflect.DeepEqual(f.order, want) {
+		t.Errorf("order = %q, want %q", f.order, want)
+	}
+}
+
+func TestLaunchConcurrencyLimit(t *testing.T) {
+	f := &fakeLauncher{hold: 30 * time.Millisecond}
+	ws := &model.Workspace{Concurrency: 3}
+	Launch(context.Background(), group(ws, "A", "B", "C", "D", "E", "F", "G"), f.launch)
+	if f.maxInFlight != 3 {
+		t.Errorf("%d launches in flight, want 3", f.maxInFlight)
+	}
+	if len(f.order) != 7 {
+		t.Errorf("launched %q", f.order)
+	}
+}
+
+func TestLaunchDelay(t *testing.T) {
+	f := &fakeLauncher{}
+	ws := &model.Workspace{DelayMs: 30, Concurrency: 4}
+	Launch(context.Background(), group(ws, "A", "B", "C"), f.launch)
+	if want := []string{"A", "B", "C"}; !reflect.DeepEqual(f.order, want) {
+		t.Fatalf("order = %q, want %q", f.order, want)
+	}
+	for i := 1; i < len(f.started); i++ {
+		if gap := f.started[i].Sub(f.started[i-1]); gap < 30*time.Millisecond {
+			t.Errorf("launch %d started %v after the previous one, want at least 30ms", i, gap)
+		}
+	}
+}
+
+func TestLaunchCollectsFailures(t *testing.T) {
+	f := &fakeLauncher{fail: map[string]bool{"B": true, "D": true}}
+	summary := Launch(context.Background(), group(&model.Workspace{Concurrency: 2}, "A", "B", "C", "D"), f.launch)
+
+	if len(summary.Results) != 4 || summary.Results[2].Name != "C" {
+		t.Fatalf("results = %+v", summary.Results)
+	}
+	var failed []string
+	for _, r := range summary.Failed() {
+		failed = append(failed, r.Name)
+	}
+	if !reflect.DeepEqual(failed, []string{"B", "D"}) {
+		t.Errorf("failed = %q, want [B D]", failed)
+	}
+	err := summary.Err()
+	if err == nil || !strings.Contains(err.Error(), "2 of 4") || !strings.Contains(err.Error(), "B: fake failure") {
+		t.Errorf("Err = %v", err)
+	}
+}
+
+func TestLaunchCancel(t *testing.T) {
+	f := &fakeLauncher{}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	summary := Launch(ctx, group(&model.Workspace{DelayMs: 200}, "A", "B", "C"), f.launch)
+
+	if !reflect.DeepEqual(f.order, []string{"A"}) {
+		t.Errorf("launched %q after cancel, want only A", f.order)
+	}
+	for _, r := range summary.Results[1:] {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("%s: %v, want %v", r.Name, r.Err, context.Canceled)
+		}
+	}
+}
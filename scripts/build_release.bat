@@ -31,7 +31,7 @@ if %ERRORLEVEL% EQU 0 (
 REM Move .syso file to the package directory so go build picks it up
 REM Standard behavior: syso must be in the package directory being built
 echo Moving resource file to package directory...
-copy ..\GoMuseTool.syso ..\cmd\Go-MuseTool\GoMuseTool.syso /Y
+copy ..\GoMuseTool.syso ..\cmd\Go-MuseTool\GoMuseTool_windows_amd64.syso /Y
 
 REM Set output directory and filename
 set OUTPUT_DIR=..\release